@@ -27,7 +27,23 @@ func StartApp() {
 
 	defer tracing.ShutdownTracerProvider(context.Background(), cfg.TracerProvider)
 
-	server.NewServer(cfg.ServerConfig, cfg.BookingService)
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go cfg.OutboxRelay.Run(relayCtx)
+
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go cfg.HoldSweeper.Run(sweeperCtx)
+
+	refundCtx, stopRefunds := context.WithCancel(context.Background())
+	defer stopRefunds()
+	go cfg.RefundRelay.Run(refundCtx)
+
+	syncerCtx, stopSyncer := context.WithCancel(context.Background())
+	defer stopSyncer()
+	go cfg.ChannelSyncer.Run(syncerCtx)
+
+	server.NewServer(cfg.ServerConfig, cfg.ApiServices)
 	slog.Info("Application is running")
 }
 
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"booking_service/internal/service_interaction/user_service"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type contextKey struct{}
+
+// AuthMiddleware resolves the user who sent the request by the bearer token and puts them into the request context.
+// Requests without a valid token are rejected with 401
+func AuthMiddleware(userServiceBridge user_service.IUserServiceBridge) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := getBearerToken(w, r)
+			if !ok {
+				return
+			}
+
+			user, err := userServiceBridge.GetUserContactData(token)
+			if err != nil {
+				if errors.Is(err, user_service.ErrInvalidToken) {
+					http.Error(w, "Invalid token", http.StatusUnauthorized)
+					slog.Error("Invalid token" + strconv.Itoa(http.StatusUnauthorized))
+				} else {
+					http.Error(w, "Failed to authenticate user", http.StatusInternalServerError)
+					slog.Error("Failed to authenticate user" + strconv.Itoa(http.StatusInternalServerError))
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithUser(r.Context(), user)))
+		})
+	}
+}
+
+// ContextWithUser returns a copy of the context carrying the authenticated user
+func ContextWithUser(ctx context.Context, user *user_service.UserData) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user, or nil if the request has not passed AuthMiddleware
+func UserFromContext(ctx context.Context) *user_service.UserData {
+	user, _ := ctx.Value(contextKey{}).(*user_service.UserData)
+	return user
+}
+
+// getBearerToken gets the token of the user who sent the request, writing 401 if it is missing
+func getBearerToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+
+	if authHeader == "" {
+		http.Error(w, "Authorization header is missing", http.StatusUnauthorized)
+		slog.Error("Authorization header is missing" + strconv.Itoa(http.StatusUnauthorized))
+		return "", false
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+		slog.Error("Invalid Authorization header format" + strconv.Itoa(http.StatusUnauthorized))
+		return "", false
+	}
+
+	return parts[1], true
+}
@@ -4,14 +4,83 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
 	"log/slog"
 )
 
 type ServerConfig struct {
-	Port   string `yaml:"port"`
-	Prefix string `yaml:"prefix"`
+	Port        string            `yaml:"port"`
+	Prefix      string            `yaml:"prefix"`
+	Pricing     PricingConfig     `yaml:"pricing"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Holds       HoldConfig        `yaml:"holds"`
+	Promotions  PromotionConfig   `yaml:"promotions"`
+	Payments    PaymentConfig     `yaml:"payments"`
+	Channels    ChannelConfig     `yaml:"channels"`
+}
+
+// PricingConfig holds the charges added on top of the room price of a stay
+type PricingConfig struct {
+	// TaxPercent is taken from the price of all nights
+	TaxPercent int `yaml:"tax_percent"`
+	// ServiceFee is charged once per booking
+	ServiceFee int `yaml:"service_fee"`
+}
+
+// OutboxConfig holds the settings of the relay which publishes notifications from the outbox
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+	// MaxBackoff limits the delay between retries of a failed notification
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// IdempotencyConfig holds the settings of the idempotency keys of the rent creation
+type IdempotencyConfig struct {
+	// KeyTTL is how long a key replays the original response, after that it may be reused
+	KeyTTL time.Duration `yaml:"key_ttl"`
+}
+
+// HoldConfig holds the settings of the tentative holds made during checkout
+type HoldConfig struct {
+	// TTL is how long a hold blocks its room before it is released
+	TTL time.Duration `yaml:"ttl"`
+	// SweepInterval is how often expired holds are released
+	SweepInterval time.Duration `yaml:"sweep_interval"`
+	// OfferTTL is how long a hold offered to a waitlisted guest blocks its room
+	OfferTTL time.Duration `yaml:"offer_ttl"`
+}
+
+// PromotionConfig holds the settings of the promo codes
+type PromotionConfig struct {
+	// AdminIDs are the users who manage the global promo codes, the codes of a hotel are managed by its owner
+	AdminIDs []uuid.UUID `yaml:"admin_ids"`
+}
+
+// PaymentConfig holds the settings of the payment provider
+type PaymentConfig struct {
+	// Provider selects the implementation, only the in-process fake is available yet
+	Provider string `yaml:"provider"`
+	// FakeOutcome is the result of every operation of the fake provider: succeed, decline or timeout
+	FakeOutcome string `yaml:"fake_outcome"`
+	// RefundInterval is how often the pending refunds are retried
+	RefundInterval time.Duration `yaml:"refund_interval"`
+	// RefundBatchSize limits the refunds retried at once
+	RefundBatchSize int `yaml:"refund_batch_size"`
+}
+
+// ChannelConfig holds the settings of the synchronization of the calendars of other booking channels
+type ChannelConfig struct {
+	// SyncInterval is how often every channel calendar is fetched and applied
+	SyncInterval time.Duration `yaml:"sync_interval"`
+	// FetchTimeout limits the download of a calendar from its URL
+	FetchTimeout time.Duration `yaml:"fetch_timeout"`
+	// AllowPrivateNetworks lets calendars be fetched from loopback and private addresses, for local setups only
+	AllowPrivateNetworks bool `yaml:"allow_private_networks"`
 }
 
 func getConfigPath() (string, error) {
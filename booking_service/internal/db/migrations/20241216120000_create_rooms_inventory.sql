@@ -0,0 +1,87 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE rooms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    room_number TEXT NOT NULL,
+    CONSTRAINT uq_rooms_hotel_room_number UNIQUE (hotel_id, room_number)
+);
+
+ALTER TABLE bookings
+    ALTER COLUMN id SET DEFAULT gen_random_uuid(),
+    ADD COLUMN IF NOT EXISTS hotel_id UUID,
+    ADD COLUMN IF NOT EXISTS check_in_date TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS check_out_date TIMESTAMPTZ;
+
+-- A booking without a hotel or dates cannot be given a room, it has to be fixed by hand before the migration
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM bookings WHERE hotel_id IS NULL OR check_in_date IS NULL OR check_out_date IS NULL) THEN
+        RAISE EXCEPTION 'bookings without a hotel or dates have to be fixed before the rooms inventory is created';
+    END IF;
+END $$;
+-- Check-out on the day of check-in was accepted before, such bookings are one night long
+UPDATE bookings SET check_out_date = check_in_date + INTERVAL '1 day' WHERE check_out_date <= check_in_date;
+-- The rooms already booked become the inventory of their hotels, numbered by their IDs
+INSERT INTO rooms (id, hotel_id, room_number)
+SELECT DISTINCT ON (b.room_id) b.room_id, b.hotel_id, b.room_id::text
+FROM bookings b
+WHERE b.room_id IS NOT NULL
+ORDER BY b.room_id, b.check_in_date
+ON CONFLICT DO NOTHING;
+-- Bookings made without a room get one, otherwise new bookings would not see them and could overlap them.
+-- In the order of check-in every booking takes a room of its hotel which is free for its stay, a room is
+-- added to the hotel only if none is, so the hotel gets as many rooms as it has overlapping bookings
+DO $$
+DECLARE
+    booking RECORD;
+    free_room UUID;
+BEGIN
+    FOR booking IN
+        SELECT b.id, b.hotel_id, b.check_in_date, b.check_out_date
+        FROM bookings b
+        WHERE b.room_id IS NULL
+        ORDER BY b.hotel_id, b.check_in_date, b.id
+    LOOP
+        SELECT r.id INTO free_room
+        FROM rooms r
+        WHERE r.hotel_id = booking.hotel_id AND NOT EXISTS (
+            SELECT 1 FROM bookings o
+            WHERE o.room_id = r.id
+              AND tstzrange(o.check_in_date, o.check_out_date) && tstzrange(booking.check_in_date, booking.check_out_date))
+        ORDER BY r.room_number
+        LIMIT 1;
+
+        IF free_room IS NULL THEN
+            free_room := gen_random_uuid();
+            INSERT INTO rooms (id, hotel_id, room_number) VALUES (free_room, booking.hotel_id, free_room::text);
+        END IF;
+        UPDATE bookings SET room_id = free_room WHERE id = booking.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE bookings
+    ALTER COLUMN hotel_id SET NOT NULL,
+    ALTER COLUMN check_in_date SET NOT NULL,
+    ALTER COLUMN check_out_date SET NOT NULL;
+
+ALTER TABLE bookings
+    ADD CONSTRAINT fk_room FOREIGN KEY (room_id) REFERENCES rooms (id),
+    ADD CONSTRAINT chk_booking_dates CHECK (check_out_date > check_in_date),
+    ADD CONSTRAINT excl_room_overlapping_bookings EXCLUDE USING gist (
+        room_id WITH =,
+        tstzrange(check_in_date, check_out_date) WITH &&
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS excl_room_overlapping_bookings,
+    DROP CONSTRAINT IF EXISTS chk_booking_dates,
+    DROP CONSTRAINT IF EXISTS fk_room;
+
+DROP TABLE IF EXISTS rooms;
+-- +goose StatementEnd
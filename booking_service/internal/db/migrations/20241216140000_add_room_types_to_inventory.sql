@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rooms ADD COLUMN room_type_id UUID;
+ALTER TABLE bookings ADD COLUMN room_type_id UUID;
+
+CREATE INDEX idx_rooms_hotel_room_type ON rooms (hotel_id, room_type_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_rooms_hotel_room_type;
+ALTER TABLE bookings DROP COLUMN IF EXISTS room_type_id;
+ALTER TABLE rooms DROP COLUMN IF EXISTS room_type_id;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE cancellation_policies (
+    hotel_id UUID PRIMARY KEY,
+    free_cancellation_hours INT NOT NULL,
+    penalty_nights INT NOT NULL,
+    CONSTRAINT chk_free_cancellation_hours CHECK (free_cancellation_hours >= 0),
+    CONSTRAINT chk_penalty_nights CHECK (penalty_nights >= 0)
+);
+
+ALTER TABLE bookings
+    ADD COLUMN cancelled_at TIMESTAMPTZ,
+    ADD COLUMN cancelled_by UUID,
+    ADD COLUMN cancellation_reason TEXT,
+    ADD COLUMN refund_amount INT;
+
+-- Cancelled bookings no longer hold their room
+ALTER TABLE bookings DROP CONSTRAINT excl_room_overlapping_bookings;
+ALTER TABLE bookings ADD CONSTRAINT excl_room_overlapping_bookings EXCLUDE USING gist (
+    room_id WITH =,
+    tstzrange(check_in_date, check_out_date) WITH &&
+) WHERE (cancelled_at IS NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP CONSTRAINT excl_room_overlapping_bookings;
+ALTER TABLE bookings ADD CONSTRAINT excl_room_overlapping_bookings EXCLUDE USING gist (
+    room_id WITH =,
+    tstzrange(check_in_date, check_out_date) WITH &&
+);
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS refund_amount,
+    DROP COLUMN IF EXISTS cancellation_reason,
+    DROP COLUMN IF EXISTS cancelled_by,
+    DROP COLUMN IF EXISTS cancelled_at;
+
+DROP TABLE IF EXISTS cancellation_policies;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bookings
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'pending',
+    ADD CONSTRAINT chk_booking_status CHECK (
+        status IN ('pending', 'confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show')
+    );
+
+UPDATE bookings SET status = 'cancelled' WHERE cancelled_at IS NOT NULL;
+
+CREATE INDEX idx_bookings_status ON bookings (status);
+
+CREATE TABLE booking_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    changed_by UUID NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_booking FOREIGN KEY (booking_id) REFERENCES bookings (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_booking_status_transitions_booking ON booking_status_transitions (booking_id, changed_at);
+
+-- Cancelled bookings and no-shows release their room
+ALTER TABLE bookings DROP CONSTRAINT excl_room_overlapping_bookings;
+ALTER TABLE bookings ADD CONSTRAINT excl_room_overlapping_bookings EXCLUDE USING gist (
+    room_id WITH =,
+    tstzrange(check_in_date, check_out_date) WITH &&
+) WHERE (status NOT IN ('cancelled', 'no_show'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP CONSTRAINT excl_room_overlapping_bookings;
+ALTER TABLE bookings ADD CONSTRAINT excl_room_overlapping_bookings EXCLUDE USING gist (
+    room_id WITH =,
+    tstzrange(check_in_date, check_out_date) WITH &&
+) WHERE (cancelled_at IS NULL);
+
+DROP TABLE IF EXISTS booking_status_transitions;
+
+DROP INDEX IF EXISTS idx_bookings_status;
+
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS chk_booking_status,
+    DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Prices are stored at booking time, rows created before have them empty
+ALTER TABLE bookings
+    ADD COLUMN night_price INT,
+    ADD COLUMN nights INT,
+    ADD COLUMN currency TEXT,
+    ADD COLUMN total_amount INT,
+    ADD CONSTRAINT chk_booking_price_snapshot CHECK (
+        (night_price IS NULL AND nights IS NULL AND currency IS NULL AND total_amount IS NULL)
+        OR (night_price >= 0 AND nights > 0 AND currency IS NOT NULL AND total_amount >= 0)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS chk_booking_price_snapshot,
+    DROP COLUMN IF EXISTS total_amount,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS nights,
+    DROP COLUMN IF EXISTS night_price;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys (
+    key TEXT NOT NULL,
+    client_id UUID NOT NULL,
+    fingerprint TEXT NOT NULL,
+    -- A key cannot be replayed without its rent, so it goes away with the rent
+    rent_id UUID NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, client_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bookings
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Rents are paged by the sort column with the id as a tiebreaker
+CREATE INDEX idx_bookings_check_in_date_id ON bookings (check_in_date, id);
+CREATE INDEX idx_bookings_created_at_id ON bookings (created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_created_at_id;
+DROP INDEX IF EXISTS idx_bookings_check_in_date_id;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A hold is a booking which blocks its room until it is converted or expires
+ALTER TABLE bookings
+    ADD COLUMN hold_expires_at TIMESTAMPTZ,
+    DROP CONSTRAINT chk_booking_status,
+    ADD CONSTRAINT chk_booking_status CHECK (
+        status IN ('held', 'pending', 'confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show')
+    );
+
+-- The sweeper looks up expired holds
+CREATE INDEX idx_bookings_hold_expires_at ON bookings (hold_expires_at) WHERE status = 'held';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_hold_expires_at;
+
+DELETE FROM bookings WHERE status = 'held';
+
+ALTER TABLE bookings
+    DROP CONSTRAINT chk_booking_status,
+    ADD CONSTRAINT chk_booking_status CHECK (
+        status IN ('pending', 'confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show')
+    ),
+    DROP COLUMN IF EXISTS hold_expires_at;
+-- +goose StatementEnd
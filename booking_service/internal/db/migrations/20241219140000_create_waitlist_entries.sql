@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Guests wait for a room of a sold-out hotel, the oldest entry is offered a hold first.
+-- The contact data is kept because the offer is made while the guest is not making a request.
+-- An entry whose offered hold lapsed is expired, it is not offered again
+CREATE TABLE waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    client_id UUID NOT NULL,
+    client_email TEXT NOT NULL DEFAULT '',
+    client_phone TEXT NOT NULL DEFAULT '',
+    hotel_id UUID NOT NULL,
+    room_type_id UUID,
+    check_in_date TIMESTAMPTZ NOT NULL,
+    check_out_date TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'waiting',
+    hold_id UUID REFERENCES bookings (id) ON DELETE SET NULL,
+    offered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_waitlist_dates CHECK (check_out_date > check_in_date),
+    CONSTRAINT chk_waitlist_status CHECK (status IN ('waiting', 'offered', 'expired'))
+);
+
+CREATE INDEX idx_waitlist_entries_waiting ON waitlist_entries (hotel_id, created_at) WHERE status = 'waiting';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS waitlist_entries;
+-- +goose StatementEnd
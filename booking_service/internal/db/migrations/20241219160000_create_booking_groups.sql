@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A group is one reservation of several rooms of a hotel, each room is a rent of its own
+CREATE TABLE booking_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE bookings ADD COLUMN group_id UUID REFERENCES booking_groups (id);
+
+CREATE INDEX idx_bookings_group_id ON bookings (group_id) WHERE group_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_group_id;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS booking_groups;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The rents made before the guests were recorded are taken for one adult
+ALTER TABLE bookings
+    ADD COLUMN adults INT NOT NULL DEFAULT 1,
+    ADD COLUMN children INT NOT NULL DEFAULT 0,
+    ADD COLUMN primary_guest_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN additional_guests TEXT[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT chk_booking_occupancy CHECK (adults > 0 AND children >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS chk_booking_occupancy,
+    DROP COLUMN IF EXISTS adults,
+    DROP COLUMN IF EXISTS children,
+    DROP COLUMN IF EXISTS primary_guest_name,
+    DROP COLUMN IF EXISTS additional_guests;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A promo code without a hotel is global and applies to every hotel.
+-- used_count is increased in the transaction which books the rent, the check keeps it within the limit
+CREATE TABLE promo_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code TEXT NOT NULL,
+    hotel_id UUID,
+    discount_type TEXT NOT NULL,
+    discount_value INT NOT NULL,
+    valid_from TIMESTAMPTZ,
+    valid_until TIMESTAMPTZ,
+    max_uses INT,
+    used_count INT NOT NULL DEFAULT 0,
+    min_nights INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_promo_code_discount CHECK (discount_type IN ('percent', 'fixed') AND discount_value > 0
+        AND (discount_type <> 'percent' OR discount_value <= 100)),
+    CONSTRAINT chk_promo_code_validity CHECK (valid_until IS NULL OR valid_from IS NULL OR valid_until > valid_from),
+    CONSTRAINT chk_promo_code_usage CHECK (max_uses IS NULL OR used_count <= max_uses)
+);
+
+CREATE UNIQUE INDEX idx_promo_codes_code ON promo_codes (upper(code));
+
+-- A rent is booked with one code at most. The terms of the code are copied to the redemption, so the rent
+-- keeps its discount when the code is changed or deleted
+CREATE TABLE promo_code_redemptions (
+    booking_id UUID PRIMARY KEY REFERENCES bookings (id) ON DELETE CASCADE,
+    promo_code_id UUID REFERENCES promo_codes (id) ON DELETE SET NULL,
+    code TEXT NOT NULL,
+    discount_type TEXT NOT NULL,
+    discount_value INT NOT NULL,
+    min_nights INT NOT NULL,
+    discount_amount INT NOT NULL,
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_promo_code_redemptions_promo_code_id ON promo_code_redemptions (promo_code_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS promo_code_redemptions;
+
+DROP TABLE IF EXISTS promo_codes;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The payment of a rent is authorized when the rent is booked, captured when the guest checks in
+-- and refunded after a cancellation, the refund is pending until the provider has made it.
+-- payment_id is the reference of the payment at the provider
+ALTER TABLE bookings ADD COLUMN payment_status TEXT NOT NULL DEFAULT 'unpaid';
+ALTER TABLE bookings ADD COLUMN payment_id TEXT;
+
+ALTER TABLE bookings ADD CONSTRAINT chk_booking_payment_status
+    CHECK (payment_status IN ('unpaid', 'pending', 'authorized', 'declined', 'captured', 'refund_pending', 'refunded'));
+
+CREATE UNIQUE INDEX idx_bookings_payment_id ON bookings (payment_id) WHERE payment_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_payment_id;
+
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS chk_booking_payment_status;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS payment_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS payment_status;
+-- +goose StatementEnd
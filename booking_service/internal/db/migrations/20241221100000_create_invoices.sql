@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The taxes and the service fee are stored with the price of the rent, so invoices itemize them as they were charged.
+-- Rents priced before have them empty and are not invoiced
+ALTER TABLE bookings
+    ADD COLUMN taxes INT,
+    ADD COLUMN service_fee INT;
+
+-- Invoices and credit notes of a hotel share one gapless sequence. The counter row is locked
+-- by the transaction which issues a document, so numbers are never skipped nor reused
+CREATE TABLE hotel_invoice_sequences (
+    hotel_id UUID PRIMARY KEY,
+    last_number INT NOT NULL
+);
+
+-- document is the snapshot of the issued invoice as it was rendered, an issued invoice is never changed:
+-- a correction is a credit note cancelling the invoice, followed by a new invoice
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    booking_id UUID NOT NULL REFERENCES bookings (id),
+    sequence INT NOT NULL,
+    number TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    credited_invoice_id UUID REFERENCES invoices (id),
+    total INT NOT NULL,
+    currency TEXT NOT NULL,
+    document JSONB NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT uq_invoices_hotel_sequence UNIQUE (hotel_id, sequence),
+    CONSTRAINT chk_invoice_kind CHECK ((kind = 'invoice' AND credited_invoice_id IS NULL)
+        OR (kind = 'credit_note' AND credited_invoice_id IS NOT NULL))
+);
+
+CREATE INDEX idx_invoices_booking ON invoices (booking_id, sequence);
+
+-- An invoice is cancelled at most once
+CREATE UNIQUE INDEX idx_invoices_credited_invoice ON invoices (credited_invoice_id)
+    WHERE credited_invoice_id IS NOT NULL;
+
+CREATE FUNCTION reject_invoice_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'issued invoices are immutable, issue a credit note instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_invoices_immutable
+    BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION reject_invoice_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invoices;
+
+DROP FUNCTION IF EXISTS reject_invoice_change();
+
+DROP TABLE IF EXISTS hotel_invoice_sequences;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS service_fee,
+    DROP COLUMN IF EXISTS taxes;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Calendar clients poll the feeds without a bearer token, so every user has a secret feed token
+-- put into the URL. Only its hash is stored, issuing a new token revokes the previous one
+CREATE TABLE calendar_feed_tokens (
+    user_id UUID PRIMARY KEY,
+    token_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_calendar_feed_tokens_hash ON calendar_feed_tokens (token_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS calendar_feed_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A channel calendar is the iCal feed of a hotel on another booking platform, fetched from source_url
+-- or uploaded by the owner as calendar_data. Its bookings block rooms of the hotel, of the room type if it is set
+CREATE TABLE channel_calendars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    room_type_id UUID,
+    owner_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    source_url TEXT,
+    calendar_data TEXT,
+    last_synced_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_channel_calendars_hotel ON channel_calendars (hotel_id);
+
+-- A blocked booking is an external booking of a channel, it takes a room like any other rent
+-- but is managed only by the synchronization of its channel
+ALTER TABLE bookings
+    ADD COLUMN channel_id UUID REFERENCES channel_calendars (id) ON DELETE CASCADE,
+    ADD COLUMN external_uid TEXT,
+    DROP CONSTRAINT chk_booking_status,
+    ADD CONSTRAINT chk_booking_status CHECK (
+        status IN ('held', 'pending', 'confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show', 'blocked')
+    ),
+    ADD CONSTRAINT chk_booking_channel CHECK ((status = 'blocked') = (channel_id IS NOT NULL));
+
+CREATE UNIQUE INDEX idx_bookings_channel_event ON bookings (channel_id, external_uid) WHERE channel_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM bookings WHERE status = 'blocked';
+
+DROP INDEX IF EXISTS idx_bookings_channel_event;
+
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS chk_booking_channel,
+    DROP CONSTRAINT chk_booking_status,
+    ADD CONSTRAINT chk_booking_status CHECK (
+        status IN ('held', 'pending', 'confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show')
+    ),
+    DROP COLUMN IF EXISTS external_uid,
+    DROP COLUMN IF EXISTS channel_id;
+
+DROP TABLE IF EXISTS channel_calendars;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Owners search the rents of their hotels by the overlap and containment of the stay with a window
+CREATE INDEX idx_bookings_stay ON bookings USING gist (tstzrange(check_in_date, check_out_date), hotel_id);
+-- Guests list their own rents, the stay index has no client so they are found by this one
+CREATE INDEX idx_bookings_client_id ON bookings (client_id, check_in_date);
+-- Departures are searched by the check-out date, arrivals use the index of the check-in date
+CREATE INDEX idx_bookings_check_out_date ON bookings (check_out_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_check_out_date;
+DROP INDEX IF EXISTS idx_bookings_client_id;
+DROP INDEX IF EXISTS idx_bookings_stay;
+-- +goose StatementEnd
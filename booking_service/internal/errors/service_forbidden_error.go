@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+type ServiceForbiddenError struct {
+	Message string
+	Details string
+}
+
+func (e *ServiceForbiddenError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s: %s", e.Message, e.Details)
+	}
+	return e.Message
+}
+
+func NewServiceForbiddenError(message string, details string) *ServiceForbiddenError {
+	return &ServiceForbiddenError{
+		Message: message,
+		Details: details,
+	}
+}
@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+type ServicePreconditionFailedError struct {
+	Message string
+	Details string
+}
+
+func (e *ServicePreconditionFailedError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s: %s", e.Message, e.Details)
+	}
+	return e.Message
+}
+
+func NewServicePreconditionFailedError(message string, details string) *ServicePreconditionFailedError {
+	return &ServicePreconditionFailedError{
+		Message: message,
+		Details: details,
+	}
+}
@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+type ServiceUnprocessableEntityError struct {
+	Message string
+	Details string
+}
+
+func (e *ServiceUnprocessableEntityError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s: %s", e.Message, e.Details)
+	}
+	return e.Message
+}
+
+func NewServiceUnprocessableEntityError(message string, details string) *ServiceUnprocessableEntityError {
+	return &ServiceUnprocessableEntityError{
+		Message: message,
+		Details: details,
+	}
+}
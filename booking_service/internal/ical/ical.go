@@ -0,0 +1,153 @@
+// Package ical reads the events of RFC 5545 calendars published by other booking channels
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCalendar is returned if the input is not an iCalendar object
+var ErrInvalidCalendar = errors.New("invalid calendar")
+
+// maxLineSize limits a single unfolded content line
+const maxLineSize = 1 << 20
+
+// Event is a VEVENT of a calendar, a booking made on another channel
+type Event struct {
+	UID       string
+	Summary   string
+	Start     time.Time
+	End       time.Time
+	Cancelled bool
+}
+
+// Parse reads the events of the calendar. All-day dates and times without a time zone are taken in loc.
+// An all-day event without an end lasts one day, events which do not end after their start are skipped
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: the calendar must start with BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+
+	var events []Event
+	var event *Event
+	var allDay bool
+	for number, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed line %d", ErrInvalidCalendar, number+1)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event, allDay = &Event{}, false
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event == nil {
+				return nil, fmt.Errorf("%w: END:VEVENT without BEGIN:VEVENT on line %d", ErrInvalidCalendar, number+1)
+			}
+			if event.End.IsZero() && allDay {
+				event.End = event.Start.AddDate(0, 0, 1)
+			}
+			if !event.Start.IsZero() && event.End.After(event.Start) {
+				if event.UID == "" {
+					// Feeds are expected to have UIDs, without one the event is identified by its dates
+					event.UID = event.Start.UTC().Format(time.RFC3339) + "/" + event.End.UTC().Format(time.RFC3339)
+				}
+				events = append(events, *event)
+			}
+			event = nil
+		case event == nil:
+			// Properties of the calendar and of other components are not needed
+		case name == "UID":
+			event.UID = value
+		case name == "SUMMARY":
+			event.Summary = unescapeText(value)
+		case name == "STATUS":
+			event.Cancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "DTSTART", name == "DTEND":
+			moment, date, err := parseMoment(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, number+1, err)
+			}
+			if name == "DTSTART" {
+				event.Start, allDay = moment, date
+			} else {
+				event.End = moment
+			}
+		}
+	}
+	if event != nil {
+		return nil, fmt.Errorf("%w: VEVENT is not closed", ErrInvalidCalendar)
+	}
+	return events, nil
+}
+
+// unfoldLines joins the continuation lines, which start with a space or a tab, to their content lines
+func unfoldLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitLine splits a content line such as DTSTART;VALUE=DATE:20250110 into its name, parameters and value
+func splitLine(line string) (string, map[string]string, string, bool) {
+	colon := strings.IndexByte(line, ':')
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// parseMoment parses a DATE or DATE-TIME value and reports whether it is an all-day date
+func parseMoment(params map[string]string, value string, loc *time.Location) (time.Time, bool, error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102") {
+		date, err := time.ParseInLocation("20060102", value, loc)
+		return date, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		moment, err := time.Parse("20060102T150405Z", value)
+		return moment, false, err
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		// An unknown zone, e.g. a Windows zone name, falls back to the default location
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	moment, err := time.ParseInLocation("20060102T150405", value, loc)
+	return moment, false, err
+}
+
+func unescapeText(text string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(text)
+}
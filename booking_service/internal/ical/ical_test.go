@@ -0,0 +1,69 @@
+package ical_test
+
+import (
+	"booking_service/internal/ical"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_AllDayEvents_DatesInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	calendar := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nUID:abc@channel\r\nSUMMARY:Reserved\r\n" +
+		"DTSTART;VALUE=DATE:20250110\r\nDTEND;VALUE=DATE:20250113\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:single@channel\r\nDTSTART;VALUE=DATE:20250120\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := ical.Parse(strings.NewReader(calendar), loc)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "abc@channel", events[0].UID)
+	assert.Equal(t, "Reserved", events[0].Summary)
+	assert.True(t, events[0].Start.Equal(time.Date(2025, 1, 10, 0, 0, 0, 0, loc)))
+	assert.True(t, events[0].End.Equal(time.Date(2025, 1, 13, 0, 0, 0, 0, loc)))
+	assert.True(t, events[1].End.Equal(time.Date(2025, 1, 21, 0, 0, 0, 0, loc)))
+}
+
+func TestParse_FoldedLinesAndTimeZones_Unfolded(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\n" +
+		"BEGIN:VEVENT\nUID:very-long-\n uid@channel\nSUMMARY:John\\, Jane\n" +
+		"DTSTART;TZID=Europe/Berlin:20250110T150000\nDTEND:20250112T090000Z\nSTATUS:CANCELLED\nEND:VEVENT\n" +
+		"END:VCALENDAR\n"
+
+	events, err := ical.Parse(strings.NewReader(calendar), time.UTC)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "very-long-uid@channel", events[0].UID)
+	assert.Equal(t, "John, Jane", events[0].Summary)
+	assert.True(t, events[0].Start.Equal(time.Date(2025, 1, 10, 14, 0, 0, 0, time.UTC)))
+	assert.True(t, events[0].End.Equal(time.Date(2025, 1, 12, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, events[0].Cancelled)
+}
+
+func TestParse_EventEndingBeforeStart_Skipped(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:broken\n" +
+		"DTSTART;VALUE=DATE:20250112\nDTEND;VALUE=DATE:20250110\nEND:VEVENT\nEND:VCALENDAR\n"
+
+	events, err := ical.Parse(strings.NewReader(calendar), time.UTC)
+
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestParse_NotCalendar_InvalidCalendar(t *testing.T) {
+	_, err := ical.Parse(strings.NewReader("<html>Not found</html>"), time.UTC)
+
+	assert.ErrorIs(t, err, ical.ErrInvalidCalendar)
+}
+
+func TestParse_UnclosedEvent_InvalidCalendar(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20250110\n"
+
+	_, err := ical.Parse(strings.NewReader(calendar), time.UTC)
+
+	assert.ErrorIs(t, err, ical.ErrInvalidCalendar)
+}
@@ -23,9 +23,63 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	// OutboxRelayLag Возраст самого старого неотправленного уведомления
+	OutboxRelayLag = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "outbox_relay_lag_seconds",
+			Help: "Age of the oldest notification waiting in the outbox in seconds",
+		},
+	)
+
+	// OutboxPendingMessages Количество неотправленных уведомлений
+	OutboxPendingMessages = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "outbox_pending_messages",
+			Help: "Number of notifications waiting in the outbox",
+		},
+	)
+
+	// OutboxPublishedTotal Количество отправленных уведомлений
+	OutboxPublishedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "outbox_published_total",
+			Help: "Total number of notifications published from the outbox",
+		},
+	)
+
+	// OutboxPublishFailuresTotal Количество неудачных попыток отправки
+	OutboxPublishFailuresTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "outbox_publish_failures_total",
+			Help: "Total number of failed attempts to publish notifications from the outbox",
+		},
+	)
+
+	// ExpiredHoldsTotal Количество истекших удержаний номеров
+	ExpiredHoldsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "booking_holds_expired_total",
+			Help: "Total number of holds released by the sweeper after they expired",
+		},
+	)
+
+	// WaitlistOffersTotal Количество предложений гостям из листа ожидания
+	WaitlistOffersTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "booking_waitlist_offers_total",
+			Help: "Total number of holds offered to waitlisted guests",
+		},
+	)
 )
 
 func Register() {
 	prometheus.MustRegister(HTTPRequestTotal)
 	prometheus.MustRegister(HTTPResponseDuration)
+	prometheus.MustRegister(OutboxRelayLag)
+	prometheus.MustRegister(OutboxPendingMessages)
+	prometheus.MustRegister(OutboxPublishedTotal)
+	prometheus.MustRegister(OutboxPublishFailuresTotal)
+	prometheus.MustRegister(ExpiredHoldsTotal)
+	prometheus.MustRegister(WaitlistOffersTotal)
 }
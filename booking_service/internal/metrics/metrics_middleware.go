@@ -32,3 +32,8 @@ func (sw *statusWriter) WriteHeader(code int) {
 	sw.statusCode = code
 	sw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the flusher of the wrapped writer, streamed responses need it
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
@@ -0,0 +1,159 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FeedTokenParameter is the query parameter carrying the feed token, calendar clients cannot send headers
+const FeedTokenParameter = "token"
+
+// CreateFeedTokenHandler issues a new feed token of the caller, revoking the previous one
+func CreateFeedTokenHandler(service services.ICalendarService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the create feed token handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		token, err := service.CreateFeedToken(caller)
+		if err != nil {
+			http.Error(w, "Failed to create feed token", http.StatusInternalServerError)
+			slog.Error("Failed to create feed token" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(token); err != nil {
+			slog.Error("Failed to encode response: " + err.Error())
+			return
+		}
+		slog.Info("The feed token was successfully created")
+	}
+}
+
+// GetHotelFeedHandler serves the bookings of a hotel as an iCalendar feed
+func GetHotelFeedHandler(service services.ICalendarService) http.HandlerFunc {
+	return calendarFeedHandler("hotel", func(r *http.Request, feedToken string) (*responses.CalendarFeedResponse, error) {
+		hotelID, err := uuid.Parse(mux.Vars(r)["hotel_id"])
+		if err != nil {
+			return nil, custom_errors.NewServiceBadRequestError("Invalid hotel ID", err.Error())
+		}
+		return service.GetHotelFeed(hotelID, feedToken)
+	})
+}
+
+// GetGuestFeedHandler serves the stays of the guest as an iCalendar feed
+func GetGuestFeedHandler(service services.ICalendarService) http.HandlerFunc {
+	return calendarFeedHandler("guest", func(r *http.Request, feedToken string) (*responses.CalendarFeedResponse, error) {
+		return service.GetGuestFeed(feedToken)
+	})
+}
+
+func calendarFeedHandler(
+	feed string,
+	handle func(r *http.Request, feedToken string) (*responses.CalendarFeedResponse, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the " + feed + " calendar feed handler")
+		calendar, err := handle(r, r.URL.Query().Get(FeedTokenParameter))
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidFeedToken) {
+				http.Error(w, "Invalid feed token", http.StatusUnauthorized)
+				slog.Error("Invalid feed token" + strconv.Itoa(http.StatusUnauthorized))
+			} else if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to get calendar feed", http.StatusInternalServerError)
+				slog.Error("Failed to get calendar feed" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		writeCalendar(w, calendar)
+		slog.Info("The " + feed + " calendar feed was successfully served")
+	}
+}
+
+// writeCalendar renders the feed as an RFC 5545 calendar. The UID of an event is derived from the rent,
+// so calendar clients update the event when the rent changes instead of adding a new one
+func writeCalendar(w io.Writer, calendar *responses.CalendarFeedResponse) {
+	const stampFormat = "20060102T150405Z"
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//booking_service//Bookings//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escapeCalendarText(calendar.Name),
+	}
+	for _, event := range calendar.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.RentID.String()+"@booking_service",
+			"DTSTAMP:"+event.CreatedAt.UTC().Format(stampFormat),
+			"DTSTART:"+event.Start.UTC().Format(stampFormat),
+			"DTEND:"+event.End.UTC().Format(stampFormat),
+			"SEQUENCE:"+strconv.Itoa(event.Version),
+			"STATUS:"+calendarStatus(event.Status),
+			"SUMMARY:"+escapeCalendarText(event.Summary),
+			"DESCRIPTION:"+escapeCalendarText(event.Description),
+			"END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		fmt.Fprint(w, foldCalendarLine(line)+"\r\n")
+	}
+}
+
+// calendarStatus maps the status of a rent to the status of its event
+func calendarStatus(status string) string {
+	switch status {
+	case services.StatusPending:
+		return "TENTATIVE"
+	case services.StatusCancelled, services.StatusNoShow:
+		return "CANCELLED"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldCalendarLine splits a content line longer than 75 octets, continuation lines start with a space.
+// Lines are split between characters, never inside a multibyte character
+func foldCalendarLine(line string) string {
+	const limit = 75
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
@@ -0,0 +1,126 @@
+package rest_test
+
+import (
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// region Mock Calendar Service
+type MockCalendarService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarService) CreateFeedToken(caller *user_service.UserData) (*responses.FeedTokenResponse, error) {
+	args := m.Called(caller)
+	return args.Get(0).(*responses.FeedTokenResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) GetHotelFeed(hotelID uuid.UUID, feedToken string) (*responses.CalendarFeedResponse, error) {
+	args := m.Called(hotelID, feedToken)
+	return args.Get(0).(*responses.CalendarFeedResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) GetGuestFeed(feedToken string) (*responses.CalendarFeedResponse, error) {
+	args := m.Called(feedToken)
+	return args.Get(0).(*responses.CalendarFeedResponse), args.Error(1)
+}
+
+func setupCalendarTestRouter(service *MockCalendarService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{CalendarService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestCreateFeedToken_CommonCase_Created(t *testing.T) {
+	mockService := new(MockCalendarService)
+	router := setupCalendarTestRouter(mockService)
+
+	mockService.On("CreateFeedToken", testCaller).Return(&responses.FeedTokenResponse{Token: "secret"}, nil)
+
+	req := httptest.NewRequest("POST", "/api/calendar/feed-token", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resBody responses.FeedTokenResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", resBody.Token)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetHotelFeed_CommonCase_Calendar(t *testing.T) {
+	mockService := new(MockCalendarService)
+	router := setupCalendarTestRouter(mockService)
+
+	hotelID := uuid.New()
+	rentID := uuid.New()
+	checkIn := time.Date(2025, 1, 10, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	feed := &responses.CalendarFeedResponse{Name: "Test Hotel", Events: []responses.CalendarEventResponse{{
+		RentID:      rentID,
+		Summary:     "Doe, John; family",
+		Description: "Rent " + rentID.String() + "\nRoom " + uuid.New().String() + "\nGuests: 2 adults, 1 children",
+		Start:       checkIn,
+		End:         checkIn.AddDate(0, 0, 2),
+		Status:      services.StatusCancelled,
+		Version:     4,
+		CreatedAt:   checkIn.AddDate(0, -1, 0),
+	}}}
+	mockService.On("GetHotelFeed", hotelID, "secret").Return(feed, nil)
+
+	req := httptest.NewRequest("GET", "/api/hotel/"+hotelID.String()+"/bookings.ics?token=secret", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	assert.Contains(t, body, "UID:"+rentID.String()+"@booking_service\r\n")
+	assert.Contains(t, body, "DTSTART:20250110T090000Z\r\n")
+	assert.Contains(t, body, "DTEND:20250112T090000Z\r\n")
+	assert.Contains(t, body, "SEQUENCE:4\r\n")
+	assert.Contains(t, body, "STATUS:CANCELLED\r\n")
+	assert.Contains(t, body, `SUMMARY:Doe\, John\; family`+"\r\n")
+	for _, line := range strings.Split(body, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGetGuestFeed_InvalidToken_Unauthorized(t *testing.T) {
+	mockService := new(MockCalendarService)
+	router := setupCalendarTestRouter(mockService)
+
+	mockService.On("GetGuestFeed", "revoked").Return((*responses.CalendarFeedResponse)(nil), services.ErrInvalidFeedToken)
+
+	req := httptest.NewRequest("GET", "/api/rent/mine.ics?token=revoked", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
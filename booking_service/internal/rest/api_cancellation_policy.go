@@ -0,0 +1,88 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+func SetCancellationPolicyHandler(service services.ICancellationPolicyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the cancellation policy setting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		var req requests.SetCancellationPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if req.FreeCancellationHours < 0 || req.PenaltyNights < 0 {
+			http.Error(w, "Cancellation policy values cannot be negative", http.StatusBadRequest)
+			slog.Error("Cancellation policy values cannot be negative" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if err := service.SetPolicy(hotelID, req, caller); err != nil {
+			if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to set cancellation policy", http.StatusInternalServerError)
+				slog.Error("Failed to set cancellation policy" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		slog.Info("The cancellation policy was successfully set")
+		slog.Info("Hotel ID: " + hotelID.String())
+	}
+}
+
+func GetCancellationPolicyHandler(service services.ICancellationPolicyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the cancellation policy getting handler")
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		policy, err := service.GetPolicy(hotelID)
+		if err != nil {
+			http.Error(w, "Failed to fetch cancellation policy", http.StatusInternalServerError)
+			slog.Error("Failed to fetch cancellation policy" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(policy); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The cancellation policy was successfully got")
+		slog.Info("Hotel ID: " + hotelID.String())
+	}
+}
@@ -0,0 +1,134 @@
+package rest_test
+
+import (
+	errors2 "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// region Mock Cancellation Policy Service
+type MockCancellationPolicyService struct {
+	mock.Mock
+}
+
+func (m *MockCancellationPolicyService) SetPolicy(hotelID uuid.UUID, req requests.SetCancellationPolicyRequest, caller *user_service.UserData) error {
+	args := m.Called(hotelID, req, caller)
+	return args.Error(0)
+}
+
+func (m *MockCancellationPolicyService) GetPolicy(hotelID uuid.UUID) (*responses.GetCancellationPolicyResponse, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(*responses.GetCancellationPolicyResponse), args.Error(1)
+}
+
+func setupCancellationPolicyTestRouter(service *MockCancellationPolicyService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{CancellationPolicyService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestSetCancellationPolicy_ValidRequest_NoContent(t *testing.T) {
+	mockService := new(MockCancellationPolicyService)
+	router := setupCancellationPolicyTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.SetCancellationPolicyRequest{FreeCancellationHours: 24, PenaltyNights: 2}
+	mockService.On("SetPolicy", hotelID, reqBody, testCaller).Return(nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID.String()+"/cancellation-policy", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSetCancellationPolicy_NegativeValues_BadRequest(t *testing.T) {
+	mockService := new(MockCancellationPolicyService)
+	router := setupCancellationPolicyTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.SetCancellationPolicyRequest{FreeCancellationHours: -1, PenaltyNights: 1})
+	req := httptest.NewRequest("PUT", "/api/hotel/"+uuid.New().String()+"/cancellation-policy", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSetCancellationPolicy_NotAuthenticated_Unauthorized(t *testing.T) {
+	mockService := new(MockCancellationPolicyService)
+	router := setupCancellationPolicyTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.SetCancellationPolicyRequest{FreeCancellationHours: 24, PenaltyNights: 1})
+	req := httptest.NewRequest("PUT", "/api/hotel/"+uuid.New().String()+"/cancellation-policy", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "SetPolicy")
+}
+
+func TestSetCancellationPolicy_HotelOfAnotherOwner_Forbidden(t *testing.T) {
+	mockService := new(MockCancellationPolicyService)
+	router := setupCancellationPolicyTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.SetCancellationPolicyRequest{FreeCancellationHours: 24, PenaltyNights: 1}
+	mockService.On("SetPolicy", hotelID, reqBody, testCaller).
+		Return(errors2.NewServiceForbiddenError("Access denied", "the hotel is administered by another owner"))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID.String()+"/cancellation-policy", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCancellationPolicy_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockCancellationPolicyService)
+	router := setupApiTestRouter(&server.ApiServices{CancellationPolicyService: mockService})
+
+	hotelID := uuid.New()
+	policy := &responses.GetCancellationPolicyResponse{HotelID: hotelID, FreeCancellationHours: 48, PenaltyNights: 1}
+	mockService.On("GetPolicy", hotelID).Return(policy, nil)
+
+	req := httptest.NewRequest("GET", "/api/hotel/"+hotelID.String()+"/cancellation-policy", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response responses.GetCancellationPolicyResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, *policy, response)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
@@ -0,0 +1,212 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxUploadedCalendarSize limits an .ics file uploaded for a channel
+const maxUploadedCalendarSize = 5 << 20
+
+func CreateChannelHandler(service services.IChannelService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the channel creation handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		hotelID, ok := parseChannelHotelID(w, r)
+		if !ok {
+			return
+		}
+
+		var req requests.CreateChannelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+		if strings.TrimSpace(req.Name) == "" {
+			http.Error(w, "Channel name cannot be empty", http.StatusBadRequest)
+			slog.Error("Channel name cannot be empty" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		channel, err := service.CreateChannel(hotelID, req, caller)
+		if err != nil {
+			writeChannelError(w, err, "Failed to create channel")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(channel); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The channel was successfully created")
+		slog.Info("Channel ID: " + channel.ID.String())
+	}
+}
+
+func GetChannelsHandler(service services.IChannelService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the channels getting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		hotelID, ok := parseChannelHotelID(w, r)
+		if !ok {
+			return
+		}
+
+		channels, err := service.GetChannels(hotelID, caller)
+		if err != nil {
+			writeChannelError(w, err, "Failed to fetch channels")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(channels); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The channels were successfully got")
+	}
+}
+
+// UploadChannelCalendarHandler takes the .ics file of a channel as the request body
+func UploadChannelCalendarHandler(service services.IChannelService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the channel calendar upload handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		hotelID, ok := parseChannelHotelID(w, r)
+		if !ok {
+			return
+		}
+		channelID, ok := parseChannelID(w, r)
+		if !ok {
+			return
+		}
+
+		calendar, err := io.ReadAll(io.LimitReader(r.Body, maxUploadedCalendarSize+1))
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+		if len(calendar) > maxUploadedCalendarSize {
+			http.Error(w, "Calendar is too large", http.StatusRequestEntityTooLarge)
+			slog.Error("Calendar is too large" + strconv.Itoa(http.StatusRequestEntityTooLarge))
+			return
+		}
+
+		channel, err := service.UploadChannelCalendar(hotelID, channelID, string(calendar), caller)
+		if err != nil {
+			writeChannelError(w, err, "Failed to upload channel calendar")
+			return
+		}
+
+		if channel == nil {
+			http.Error(w, "Channel not found", http.StatusNotFound)
+			slog.Error("Channel not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(channel); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The channel calendar was successfully uploaded")
+		slog.Info("Channel ID: " + channelID.String())
+	}
+}
+
+// DeleteChannelHandler removes the channel, the rooms blocked by its calendar become free
+func DeleteChannelHandler(service services.IChannelService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the channel deletion handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		hotelID, ok := parseChannelHotelID(w, r)
+		if !ok {
+			return
+		}
+		channelID, ok := parseChannelID(w, r)
+		if !ok {
+			return
+		}
+
+		channel, err := service.DeleteChannel(hotelID, channelID, caller)
+		if err != nil {
+			writeChannelError(w, err, "Failed to delete channel")
+			return
+		}
+
+		if channel == nil {
+			http.Error(w, "Channel not found", http.StatusNotFound)
+			slog.Error("Channel not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		slog.Info("The channel was successfully deleted")
+		slog.Info("Channel ID: " + channelID.String())
+	}
+}
+
+func parseChannelHotelID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	hotelID, err := uuid.Parse(mux.Vars(r)["hotel_id"])
+	if err != nil {
+		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+		slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
+		return uuid.Nil, false
+	}
+	return hotelID, true
+}
+
+func parseChannelID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	channelID, err := uuid.Parse(mux.Vars(r)["channel_id"])
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		slog.Error("Invalid channel ID" + strconv.Itoa(http.StatusBadRequest))
+		return uuid.Nil, false
+	}
+	return channelID, true
+}
+
+func writeChannelError(w http.ResponseWriter, err error, message string) {
+	if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+	} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+	} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+	} else {
+		http.Error(w, message, http.StatusInternalServerError)
+		slog.Error(message + strconv.Itoa(http.StatusInternalServerError))
+	}
+}
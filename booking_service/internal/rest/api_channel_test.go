@@ -0,0 +1,133 @@
+package rest_test
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// region Mock Channel Service
+type MockChannelService struct {
+	mock.Mock
+}
+
+func (m *MockChannelService) CreateChannel(hotelID uuid.UUID, request requests.CreateChannelRequest, caller *user_service.UserData) (*responses.ChannelResponse, error) {
+	args := m.Called(hotelID, request, caller)
+	return args.Get(0).(*responses.ChannelResponse), args.Error(1)
+}
+
+func (m *MockChannelService) GetChannels(hotelID uuid.UUID, caller *user_service.UserData) ([]responses.ChannelResponse, error) {
+	args := m.Called(hotelID, caller)
+	return args.Get(0).([]responses.ChannelResponse), args.Error(1)
+}
+
+func (m *MockChannelService) UploadChannelCalendar(hotelID uuid.UUID, channelID uuid.UUID, calendar string, caller *user_service.UserData) (*responses.ChannelResponse, error) {
+	args := m.Called(hotelID, channelID, calendar, caller)
+	return args.Get(0).(*responses.ChannelResponse), args.Error(1)
+}
+
+func (m *MockChannelService) DeleteChannel(hotelID uuid.UUID, channelID uuid.UUID, caller *user_service.UserData) (*responses.ChannelResponse, error) {
+	args := m.Called(hotelID, channelID, caller)
+	return args.Get(0).(*responses.ChannelResponse), args.Error(1)
+}
+
+func setupChannelTestRouter(service *MockChannelService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{ChannelService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestCreateChannel_CommonCase_Created(t *testing.T) {
+	mockService := new(MockChannelService)
+	router := setupChannelTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.CreateChannelRequest{Name: "Airbnb", URL: "https://example.com/calendar.ics"}
+	channel := &responses.ChannelResponse{ID: uuid.New(), HotelID: hotelID, Name: "Airbnb", URL: reqBody.URL}
+	mockService.On("CreateChannel", hotelID, reqBody, testCaller).Return(channel, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/hotel/"+hotelID.String()+"/channels", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resBody responses.ChannelResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, channel.ID, resBody.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateChannel_EmptyName_BadRequest(t *testing.T) {
+	mockService := new(MockChannelService)
+	router := setupChannelTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.CreateChannelRequest{Name: " "})
+	req := httptest.NewRequest("POST", "/api/hotel/"+uuid.New().String()+"/channels", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "CreateChannel")
+}
+
+func TestUploadChannelCalendar_InvalidCalendar_BadRequest(t *testing.T) {
+	mockService := new(MockChannelService)
+	router := setupChannelTestRouter(mockService)
+
+	hotelID := uuid.New()
+	channelID := uuid.New()
+	mockService.On("UploadChannelCalendar", hotelID, channelID, "not a calendar", testCaller).
+		Return((*responses.ChannelResponse)(nil), custom_errors.NewServiceBadRequestError("Invalid calendar", "invalid calendar"))
+
+	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID.String()+"/channels/"+channelID.String()+"/calendar",
+		strings.NewReader("not a calendar"))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteChannel_NotFound_NotFound(t *testing.T) {
+	mockService := new(MockChannelService)
+	router := setupChannelTestRouter(mockService)
+
+	hotelID := uuid.New()
+	channelID := uuid.New()
+	mockService.On("DeleteChannel", hotelID, channelID, testCaller).Return((*responses.ChannelResponse)(nil), nil)
+
+	req := httptest.NewRequest("DELETE", "/api/hotel/"+hotelID.String()+"/channels/"+channelID.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
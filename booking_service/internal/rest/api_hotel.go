@@ -1,39 +1,45 @@
 package rest
 
 import (
+	"booking_service/internal/auth"
 	custom_errors "booking_service/internal/errors"
 	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/user_service"
 	"booking_service/internal/services"
 	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 )
 
+const maxIdempotencyKeyLength = 255
+
+// mergePatchContentType is the media type of JSON merge patch (RFC 7396) bodies
+const mergePatchContentType = "application/merge-patch+json"
+
 func CreateRentHandler(service services.IBookingService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("Calling the rent creation handler")
-		// Get user who sent request
-		authHeader := r.Header.Get("Authorization")
-
-		if authHeader == "" {
-			http.Error(w, "Authorization header is missing", http.StatusUnauthorized)
+		caller, ok := getCaller(w, r)
+		if !ok {
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+		// Retries with the same key replay the first response instead of booking again
+		idempotencyKey := r.Header.Get("Idempotency-Key")
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			http.Error(w, "Idempotency key is too long", http.StatusBadRequest)
+			slog.Error("Idempotency key is too long" + strconv.Itoa(http.StatusBadRequest))
 			return
 		}
 
-		token := parts[1]
-
 		var req requests.CreateRentRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -41,17 +47,20 @@ func CreateRentHandler(service services.IBookingService) http.HandlerFunc {
 			return
 		}
 
-		if req.CheckOutDate.Before(req.CheckInDate) {
-			http.Error(w, "Check-out date cannot be before check-in date", http.StatusBadRequest)
-			slog.Error("Check-out date cannot be before check-in date" + strconv.Itoa(http.StatusBadRequest))
+		if !req.CheckOutDate.After(req.CheckInDate) {
+			http.Error(w, "Check-out date must be after check-in date", http.StatusBadRequest)
+			slog.Error("Check-out date must be after check-in date" + strconv.Itoa(http.StatusBadRequest))
 			return
 		}
 
-		rentID, err := service.CreateRent(req, token)
+		rentID, err := service.CreateRent(req, caller, idempotencyKey)
 		if err != nil {
 			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
 			} else {
 				http.Error(w, "Failed to create rent", http.StatusInternalServerError)
 				slog.Error("Failed to create rent" + strconv.Itoa(http.StatusInternalServerError))
@@ -71,9 +80,61 @@ func CreateRentHandler(service services.IBookingService) http.HandlerFunc {
 	}
 }
 
+// CreateHoldHandler blocks a free room for a few minutes while the guest completes the checkout
+func CreateHoldHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the hold creation handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		var req requests.CreateHoldRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if !req.CheckOutDate.After(req.CheckInDate) {
+			http.Error(w, "Check-out date must be after check-in date", http.StatusBadRequest)
+			slog.Error("Check-out date must be after check-in date" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		hold, err := service.CreateHold(req, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else {
+				http.Error(w, "Failed to create hold", http.StatusInternalServerError)
+				slog.Error("Failed to create hold" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(hold); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The hold was successfully created")
+		slog.Info("Hold ID: " + hold.HoldID.String())
+	}
+}
+
+// UpdateRentHandler changes the stay of the rent and returns the price difference. A change which raises
+// the price is answered with 409 and the difference until the guest confirms it
 func UpdateRentHandler(service services.IBookingService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("Calling the rent update handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
 		var req requests.UpdateRentRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -89,72 +150,187 @@ func UpdateRentHandler(service services.IBookingService) http.HandlerFunc {
 			return
 		}
 
-		if err := service.UpdateRent(rentID, req); err != nil {
-			http.Error(w, "Failed to update rent", http.StatusInternalServerError)
-			slog.Error("Failed to update rent" + strconv.Itoa(http.StatusInternalServerError))
+		version, ok := getIfMatchVersion(w, r)
+		if !ok {
+			return
+		}
+
+		modification, err := service.UpdateRent(rentID, req, caller, version)
+		if err != nil {
+			var unconfirmed *services.PriceDifferenceNotConfirmedError
+			if errors.As(err, &unconfirmed) {
+				writeUnconfirmedPriceDifference(w, unconfirmed)
+			} else if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else if errors.As(err, new(*custom_errors.ServicePreconditionFailedError)) {
+				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusPreconditionFailed))
+			} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+			} else {
+				http.Error(w, "Failed to update rent", http.StatusInternalServerError)
+				slog.Error("Failed to update rent" + strconv.Itoa(http.StatusInternalServerError))
+			}
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", formatETag(modification.Version))
+		if err := json.NewEncoder(w).Encode(modification); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
 		slog.Info("The rent was successfully updated")
 		slog.Info("Rent ID: " + rentID.String())
 	}
 }
 
+// writeUnconfirmedPriceDifference answers with the price of the change, so that the guest can confirm it
+func writeUnconfirmedPriceDifference(w http.ResponseWriter, err *services.PriceDifferenceNotConfirmedError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	if encodeErr := json.NewEncoder(w).Encode(err.Modification); encodeErr != nil {
+		slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+		return
+	}
+	slog.Error(err.Error() + strconv.Itoa(http.StatusConflict))
+}
+
+// PatchRentHandler updates only the fields of the rent present in the JSON merge patch
+func PatchRentHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the rent patch handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		if contentType != "" && !strings.HasPrefix(contentType, mergePatchContentType) &&
+			!strings.HasPrefix(contentType, "application/json") {
+			http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+			slog.Error("Unsupported content type" + strconv.Itoa(http.StatusUnsupportedMediaType))
+			return
+		}
+
+		patch, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		vars := mux.Vars(r)
+		rentID, err := uuid.Parse(vars["rent_id"])
+		if err != nil {
+			http.Error(w, "Invalid rent ID", http.StatusBadRequest)
+			slog.Error("Invalid rent ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		version, ok := getIfMatchVersion(w, r)
+		if !ok {
+			return
+		}
+
+		rent, err := service.PatchRent(rentID, patch, caller, version)
+		if err != nil {
+			var unconfirmed *services.PriceDifferenceNotConfirmedError
+			if errors.As(err, &unconfirmed) {
+				writeUnconfirmedPriceDifference(w, unconfirmed)
+			} else if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else if errors.As(err, new(*custom_errors.ServicePreconditionFailedError)) {
+				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusPreconditionFailed))
+			} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+			} else {
+				http.Error(w, "Failed to update rent", http.StatusInternalServerError)
+				slog.Error("Failed to update rent" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		if rent == nil {
+			http.Error(w, "Rent not found", http.StatusNotFound)
+			slog.Error("Rent not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", formatETag(rent.Version))
+		if err := json.NewEncoder(w).Encode(rent); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The rent was successfully patched")
+		slog.Info("Rent ID: " + rentID.String())
+	}
+}
+
 func GetRentsHandler(service services.IBookingService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("Calling the rents getting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
 		queryParams := r.URL.Query()
-		clientIDStr := queryParams.Get("client")
-		hotelIDStr := queryParams.Get("hotel")
-		from := queryParams.Get("from")
-		to := queryParams.Get("to")
-
-		// Helper function to parse UUID from string
-		parseUUID := func(s string) (uuid.UUID, error) {
-			if s == "" {
-				return uuid.Nil, nil
-			}
-			id, err := uuid.Parse(s)
-			if err != nil {
-				return uuid.Nil, err
-			}
-			return id, nil
+		filter, ok := parseRentFilter(w, queryParams)
+		if !ok {
+			return
 		}
+		limitStr := queryParams.Get("limit")
+		sortBy := queryParams.Get("sort")
+		sortOrder := queryParams.Get("order")
 
-		parseTime := func(s string) (*time.Time, error) {
-			if s == "" {
-				return nil, nil
-			}
-			timeValue, err := time.Parse(time.RFC3339, s)
-			if err != nil {
-				return nil, err
+		limit := 0
+		if limitStr != "" {
+			var errLimit error
+			limit, errLimit = strconv.Atoi(limitStr)
+			if errLimit != nil || limit <= 0 || limit > services.MaxRentsLimit {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				slog.Error("Invalid limit" + strconv.Itoa(http.StatusBadRequest))
+				return
 			}
-			return &timeValue, nil
 		}
 
-		clientID, errClient := parseUUID(clientIDStr)
-		hotelID, errHotel := parseUUID(hotelIDStr)
-		fromDate, errFrom := parseTime(from)
-		toDate, errTo := parseTime(to)
-
-		if errClient != nil || errHotel != nil || errFrom != nil || errTo != nil {
-			http.Error(w, "Invalid data (failed to parse)", http.StatusBadRequest)
-			slog.Error("Invalid data (failed to parse)" + strconv.Itoa(http.StatusBadRequest))
+		if (sortBy != "" && !services.IsValidSortBy(sortBy)) || (sortOrder != "" && !services.IsValidSortOrder(sortOrder)) {
+			http.Error(w, "Invalid sort", http.StatusBadRequest)
+			slog.Error("Invalid sort" + strconv.Itoa(http.StatusBadRequest))
 			return
 		}
 
-		filter := requests.RentFilter{
-			ClientID: clientID,
-			HotelID:  hotelID,
-			FromDate: fromDate,
-			ToDate:   toDate,
-		}
+		filter.Limit = limit
+		filter.Cursor = queryParams.Get("cursor")
+		filter.SortBy = sortBy
+		filter.SortOrder = sortOrder
 
-		rents, err := service.GetRents(filter)
+		rents, err := service.GetRents(filter, caller)
 		if err != nil {
-			http.Error(w, "Failed to fetch rents", http.StatusInternalServerError)
-			slog.Error("Failed to fetch rents" + strconv.Itoa(http.StatusInternalServerError))
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to fetch rents", http.StatusInternalServerError)
+				slog.Error("Failed to fetch rents" + strconv.Itoa(http.StatusInternalServerError))
+			}
 			return
 		}
 
@@ -169,9 +345,78 @@ func GetRentsHandler(service services.IBookingService) http.HandlerFunc {
 	}
 }
 
+// parseRentFilter reads the filter of the rents from the query, it writes the error if the query is invalid
+func parseRentFilter(w http.ResponseWriter, queryParams url.Values) (requests.RentFilter, bool) {
+	clientIDStr := queryParams.Get("client")
+	hotelIDStr := queryParams.Get("hotel")
+	from := queryParams.Get("from")
+	to := queryParams.Get("to")
+	dateMode := queryParams.Get("mode")
+	status := queryParams.Get("status")
+
+	// Helper function to parse UUID from string
+	parseUUID := func(s string) (uuid.UUID, error) {
+		if s == "" {
+			return uuid.Nil, nil
+		}
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return id, nil
+	}
+
+	parseTime := func(s string) (*time.Time, error) {
+		if s == "" {
+			return nil, nil
+		}
+		timeValue, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, err
+		}
+		return &timeValue, nil
+	}
+
+	clientID, errClient := parseUUID(clientIDStr)
+	hotelID, errHotel := parseUUID(hotelIDStr)
+	fromDate, errFrom := parseTime(from)
+	toDate, errTo := parseTime(to)
+
+	if errClient != nil || errHotel != nil || errFrom != nil || errTo != nil {
+		http.Error(w, "Invalid data (failed to parse)", http.StatusBadRequest)
+		slog.Error("Invalid data (failed to parse)" + strconv.Itoa(http.StatusBadRequest))
+		return requests.RentFilter{}, false
+	}
+
+	if status != "" && !services.IsValidStatus(status) {
+		http.Error(w, "Invalid rent status", http.StatusBadRequest)
+		slog.Error("Invalid rent status" + strconv.Itoa(http.StatusBadRequest))
+		return requests.RentFilter{}, false
+	}
+
+	if dateMode != "" && !services.IsValidDateMode(dateMode) {
+		http.Error(w, "Invalid date mode", http.StatusBadRequest)
+		slog.Error("Invalid date mode" + strconv.Itoa(http.StatusBadRequest))
+		return requests.RentFilter{}, false
+	}
+
+	return requests.RentFilter{
+		ClientID: clientID,
+		HotelID:  hotelID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+		DateMode: dateMode,
+		Status:   status,
+	}, true
+}
+
 func GetRentByIDHandler(service services.IBookingService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("Calling the rent getting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
 		vars := mux.Vars(r)
 		rentID, err := uuid.Parse(vars["rent_id"])
 		if err != nil {
@@ -180,10 +425,15 @@ func GetRentByIDHandler(service services.IBookingService) http.HandlerFunc {
 			return
 		}
 
-		rent, err := service.GetRentByID(rentID)
+		rent, err := service.GetRentByID(rentID, caller)
 		if err != nil {
-			http.Error(w, "Failed to fetch rent", http.StatusInternalServerError)
-			slog.Error("Failed to fetch rent" + strconv.Itoa(http.StatusInternalServerError))
+			if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to fetch rent", http.StatusInternalServerError)
+				slog.Error("Failed to fetch rent" + strconv.Itoa(http.StatusInternalServerError))
+			}
 			return
 		}
 
@@ -194,6 +444,7 @@ func GetRentByIDHandler(service services.IBookingService) http.HandlerFunc {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", formatETag(rent.Version))
 		if err := json.NewEncoder(w).Encode(rent); err != nil {
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
@@ -203,3 +454,129 @@ func GetRentByIDHandler(service services.IBookingService) http.HandlerFunc {
 		slog.Info("Rent ID: " + rentID.String())
 	}
 }
+
+func CancelRentHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the rent cancellation handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		vars := mux.Vars(r)
+		rentID, err := uuid.Parse(vars["rent_id"])
+		if err != nil {
+			http.Error(w, "Invalid rent ID", http.StatusBadRequest)
+			slog.Error("Invalid rent ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		// The cancellation reason is optional, so the body may be empty
+		var req requests.CancelRentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		cancellation, err := service.CancelRent(rentID, req, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to cancel rent", http.StatusInternalServerError)
+				slog.Error("Failed to cancel rent" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		if cancellation == nil {
+			http.Error(w, "Rent not found", http.StatusNotFound)
+			slog.Error("Rent not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cancellation); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The rent was successfully cancelled")
+		slog.Info("Rent ID: " + rentID.String())
+	}
+}
+
+func TransitionRentHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the rent transition handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		vars := mux.Vars(r)
+		rentID, err := uuid.Parse(vars["rent_id"])
+		if err != nil {
+			http.Error(w, "Invalid rent ID", http.StatusBadRequest)
+			slog.Error("Invalid rent ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		var req requests.TransitionRentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		transition, err := service.TransitionRent(rentID, req, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+			} else {
+				http.Error(w, "Failed to change rent status", http.StatusInternalServerError)
+				slog.Error("Failed to change rent status" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		if transition == nil {
+			http.Error(w, "Rent not found", http.StatusNotFound)
+			slog.Error("Rent not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(transition); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The rent status was successfully changed")
+		slog.Info("Rent ID: " + rentID.String())
+	}
+}
+
+// getCaller gets the user resolved by the auth middleware, writing 401 if the request is not authenticated
+func getCaller(w http.ResponseWriter, r *http.Request) (*user_service.UserData, bool) {
+	caller := auth.UserFromContext(r.Context())
+	if caller == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		slog.Error("Unauthorized" + strconv.Itoa(http.StatusUnauthorized))
+		return nil, false
+	}
+	return caller, true
+}
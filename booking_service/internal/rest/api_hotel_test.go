@@ -6,8 +6,10 @@ import (
 	"booking_service/internal/rest/dtos/requests"
 	"booking_service/internal/rest/dtos/responses"
 	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
 	"booking_service/internal/services"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
@@ -26,31 +28,100 @@ type MockBookingService struct {
 	mock.Mock
 }
 
-func (m *MockBookingService) CreateRent(req requests.CreateRentRequest, token string) (uuid.UUID, error) {
-	args := m.Called(req, token)
+func (m *MockBookingService) CreateRent(req requests.CreateRentRequest, caller *user_service.UserData, idempotencyKey string) (uuid.UUID, error) {
+	args := m.Called(req, caller, idempotencyKey)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *MockBookingService) UpdateRent(id uuid.UUID, req requests.UpdateRentRequest) error {
-	args := m.Called(id, req)
-	return args.Error(0)
+func (m *MockBookingService) CreateHold(req requests.CreateHoldRequest, caller *user_service.UserData) (*responses.CreateHoldResponse, error) {
+	args := m.Called(req, caller)
+	return args.Get(0).(*responses.CreateHoldResponse), args.Error(1)
 }
 
-func (m *MockBookingService) GetRents(filter requests.RentFilter) (*responses.GetRentsResponse, error) {
-	args := m.Called(filter)
+func (m *MockBookingService) CreateGroupRent(req requests.CreateGroupRentRequest, caller *user_service.UserData) (*responses.GetRentGroupResponse, error) {
+	args := m.Called(req, caller)
+	return args.Get(0).(*responses.GetRentGroupResponse), args.Error(1)
+}
+
+func (m *MockBookingService) GetGroupRent(groupID uuid.UUID, caller *user_service.UserData) (*responses.GetRentGroupResponse, error) {
+	args := m.Called(groupID, caller)
+	return args.Get(0).(*responses.GetRentGroupResponse), args.Error(1)
+}
+
+func (m *MockBookingService) UpdateRent(id uuid.UUID, req requests.UpdateRentRequest, caller *user_service.UserData, version int) (*responses.RentModificationResponse, error) {
+	args := m.Called(id, req, caller, version)
+	return args.Get(0).(*responses.RentModificationResponse), args.Error(1)
+}
+
+func (m *MockBookingService) PatchRent(id uuid.UUID, patch []byte, caller *user_service.UserData, version int) (*responses.GetRentResponse, error) {
+	args := m.Called(id, patch, caller, version)
+	return args.Get(0).(*responses.GetRentResponse), args.Error(1)
+}
+
+func (m *MockBookingService) GetRents(filter requests.RentFilter, caller *user_service.UserData) (*responses.GetRentsResponse, error) {
+	args := m.Called(filter, caller)
 	return args.Get(0).(*responses.GetRentsResponse), args.Error(1)
 }
 
-func (m *MockBookingService) GetRentByID(id uuid.UUID) (*responses.GetRentResponse, error) {
-	args := m.Called(id)
+// ExportRents writes the rents passed to Return before returning its error
+func (m *MockBookingService) ExportRents(ctx context.Context, filter requests.RentFilter, caller *user_service.UserData, write func(rent *responses.ExportedRentResponse) error) error {
+	args := m.Called(filter, caller)
+	for _, rent := range args.Get(0).([]responses.ExportedRentResponse) {
+		if err := write(&rent); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockBookingService) GetRentByID(id uuid.UUID, caller *user_service.UserData) (*responses.GetRentResponse, error) {
+	args := m.Called(id, caller)
 	return args.Get(0).(*responses.GetRentResponse), args.Error(1)
 }
 
+func (m *MockBookingService) TransitionRent(id uuid.UUID, req requests.TransitionRentRequest, caller *user_service.UserData) (*responses.RentTransitionResponse, error) {
+	args := m.Called(id, req, caller)
+	return args.Get(0).(*responses.RentTransitionResponse), args.Error(1)
+}
+
+func (m *MockBookingService) CancelRent(id uuid.UUID, req requests.CancelRentRequest, caller *user_service.UserData) (*responses.CancelRentResponse, error) {
+	args := m.Called(id, req, caller)
+	return args.Get(0).(*responses.CancelRentResponse), args.Error(1)
+}
+
+// endregion
+
+// region Mock User Service Bridge
+type MockUserServiceBridge struct {
+	mock.Mock
+}
+
+func (m *MockUserServiceBridge) GetUserContactData(token string) (*user_service.UserData, error) {
+	args := m.Called(token)
+	return args.Get(0).(*user_service.UserData), args.Error(1)
+}
+
+func (m *MockUserServiceBridge) GetUserContactDataByID(userID uuid.UUID) (*user_service.UserData, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*user_service.UserData), args.Error(1)
+}
+
 // endregion
 
 // region Helpers
+
+// testCaller is the user authenticated by the "token" bearer token
+var testCaller = &user_service.UserData{Id: uuid.New(), Email: "guest@example.com", Role: user_service.RoleGuest}
+
 func setupTestRouter(service services.IBookingService) *mux.Router {
-	return server.SetupApiRouter(&config.ServerConfig{Prefix: "/api"}, service)
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	userBridgeMock.On("GetUserContactData", mock.Anything).Return((*user_service.UserData)(nil), user_service.ErrInvalidToken).Maybe()
+	return setupApiTestRouter(&server.ApiServices{BookingService: service, UserServiceBridge: userBridgeMock})
+}
+
+func setupApiTestRouter(apiServices *server.ApiServices) *mux.Router {
+	return server.SetupApiRouter(&config.ServerConfig{Prefix: "/api"}, apiServices)
 }
 
 // endregion
@@ -71,7 +142,7 @@ func TestCreateRent_CommonCase_Ok(t *testing.T) {
 	}
 
 	rentID := uuid.New()
-	mockService.On("CreateRent", reqBody, userToken).Return(rentID, nil)
+	mockService.On("CreateRent", reqBody, testCaller, "").Return(rentID, nil)
 
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest("POST", "/api/rent", bytes.NewReader(body))
@@ -88,6 +159,49 @@ func TestCreateRent_CommonCase_Ok(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateHoldHandler_CommonCase_Created(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	reqBody := requests.CreateHoldRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	}
+	hold := &responses.CreateHoldResponse{HoldID: uuid.New(), ExpiresAt: checkInDate.Add(10 * time.Minute)}
+	mockService.On("CreateHold", reqBody, testCaller).Return(hold, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/hold", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resBody responses.CreateHoldResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, hold.HoldID, resBody.HoldID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateHoldHandler_EmptyStay_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.CreateHoldRequest{HotelID: uuid.New()})
+	req := httptest.NewRequest("POST", "/api/rent/hold", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "CreateHold")
+}
+
 func TestCreateRent_InvalidBody_BadRequest(t *testing.T) {
 	mockService := new(MockBookingService)
 	router := setupTestRouter(mockService)
@@ -129,6 +243,28 @@ func TestCreateRent_CheckOutLessThanCheckIn_ReturnBadRequest(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateRent_CheckOutOnCheckIn_ReturnBadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	reqBody := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate,
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "CreateRent")
+}
+
 func TestCreateRent_NotExistingHotel_ReturnNotFound(t *testing.T) {
 	mockService := new(MockBookingService)
 	router := setupTestRouter(mockService)
@@ -142,7 +278,7 @@ func TestCreateRent_NotExistingHotel_ReturnNotFound(t *testing.T) {
 		CheckOutDate: checkOutDate,
 	}
 
-	mockService.On("CreateRent", mock.Anything, userToken).Return(uuid.Nil, errors2.NewServiceBadRequestError("service error", ""))
+	mockService.On("CreateRent", mock.Anything, testCaller, "").Return(uuid.Nil, errors2.NewServiceBadRequestError("service error", ""))
 
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest("POST", "/api/rent", bytes.NewReader(body))
@@ -155,6 +291,60 @@ func TestCreateRent_NotExistingHotel_ReturnNotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateRent_IdempotencyKey_PassedToService(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+	userToken := "token"
+
+	checkInDate := time.Now().Truncate(time.Second)
+	reqBody := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	}
+
+	rentID := uuid.New()
+	mockService.On("CreateRent", reqBody, testCaller, "retry-key").Return(rentID, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent", bytes.NewReader(body))
+	req.Header.Set("Authorization", "bearer "+userToken)
+	req.Header.Set("Idempotency-Key", "retry-key")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateRent_IdempotencyKeyReused_UnprocessableEntity(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+	userToken := "token"
+
+	checkInDate := time.Now().Truncate(time.Second)
+	reqBody := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	}
+
+	mockService.On("CreateRent", mock.Anything, testCaller, "retry-key").
+		Return(uuid.Nil, errors2.NewServiceUnprocessableEntityError("Idempotency key reused", ""))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent", bytes.NewReader(body))
+	req.Header.Set("Authorization", "bearer "+userToken)
+	req.Header.Set("Idempotency-Key", "retry-key")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGetRents_CommonCase_Ok(t *testing.T) {
 	mockService := new(MockBookingService)
 	router := setupTestRouter(mockService)
@@ -178,7 +368,7 @@ func TestGetRents_CommonCase_Ok(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetRents", mock.Anything).Return(&rents, nil)
+	mockService.On("GetRents", mock.Anything, testCaller).Return(&rents, nil)
 
 	request := fmt.Sprintf("/api/rent?client=%s&hotel=%s&from=%s&to=%s",
 		clientID.String(), hotelId.String(),
@@ -186,6 +376,7 @@ func TestGetRents_CommonCase_Ok(t *testing.T) {
 		url.QueryEscape(checkInDate.Format(time.RFC3339)))
 
 	req := httptest.NewRequest("GET", request, nil)
+	req.Header.Set("Authorization", "Bearer token")
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -212,11 +403,12 @@ func TestGetRents_CommonCaseWithSomeEmptyFields_Ok(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetRents", mock.Anything).Return(&rents, nil)
+	mockService.On("GetRents", mock.Anything, testCaller).Return(&rents, nil)
 
 	request := fmt.Sprintf("/api/rent?client=%s", clientID.String())
 
 	req := httptest.NewRequest("GET", request, nil)
+	req.Header.Set("Authorization", "Bearer token")
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -237,7 +429,7 @@ func TestGetRents_FilterOutAllRents_ReturnEmptyRentsArray(t *testing.T) {
 	hotelId := uuid.New()
 	checkInDate := time.Now().Truncate(time.Second)
 
-	mockService.On("GetRents", mock.Anything).Return(&responses.GetRentsResponse{Rents: []responses.GetRentResponse{}}, nil)
+	mockService.On("GetRents", mock.Anything, testCaller).Return(&responses.GetRentsResponse{Rents: []responses.GetRentResponse{}}, nil)
 
 	request := fmt.Sprintf("/api/rent?client=%s&hotel=%s&from=%s&to=%s",
 		clientID.String(), hotelId.String(),
@@ -245,6 +437,7 @@ func TestGetRents_FilterOutAllRents_ReturnEmptyRentsArray(t *testing.T) {
 		url.QueryEscape(checkInDate.Format(time.RFC3339)))
 
 	req := httptest.NewRequest("GET", request, nil)
+	req.Header.Set("Authorization", "Bearer token")
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -271,14 +464,80 @@ func TestUpdateRentHandler_ValidRequest_Ok(t *testing.T) {
 	}
 	reqBody, _ := json.Marshal(updateRequest)
 
-	mockService.On("UpdateRent", rentID, updateRequest).Return(nil)
+	modification := &responses.RentModificationResponse{RentID: rentID, PriceDifference: -500_00, Version: 2}
+	mockService.On("UpdateRent", rentID, updateRequest, testCaller, 1).Return(modification, nil)
 
 	req := httptest.NewRequest("PUT", "/api/rent/"+rentID.String(), bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
 
-	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	var resBody responses.RentModificationResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, -500_00, resBody.PriceDifference)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateRentHandler_PriceDifferenceNotConfirmed_Conflict(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	rentID := uuid.New()
+	updateRequest := requests.UpdateRentRequest{
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(72 * time.Hour),
+	}
+	reqBody, _ := json.Marshal(updateRequest)
+
+	modification := &responses.RentModificationResponse{RentID: rentID, PriceDifference: 2000_00, Currency: "RUB", Version: 2}
+	mockService.On("UpdateRent", rentID, updateRequest, testCaller, 1).
+		Return((*responses.RentModificationResponse)(nil), &services.PriceDifferenceNotConfirmedError{Modification: modification})
+
+	req := httptest.NewRequest("PUT", "/api/rent/"+rentID.String(), bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var resBody responses.RentModificationResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, 2000_00, resBody.PriceDifference)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateRentHandler_NoFreeRooms_StatusBadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	checkOutDate := checkInDate.Add(72 * time.Hour)
+
+	rentID := uuid.New()
+	updateRequest := requests.UpdateRentRequest{
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkOutDate,
+	}
+	reqBody, _ := json.Marshal(updateRequest)
+
+	mockService.On("UpdateRent", rentID, mock.Anything, testCaller, 1).Return((*responses.RentModificationResponse)(nil), errors2.NewServiceBadRequestError("No rooms available", ""))
+
+	req := httptest.NewRequest("PUT", "/api/rent/"+rentID.String(), bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -289,6 +548,8 @@ func TestUpdateRentHandler_InvalidRequestBody_StatusBadRequest(t *testing.T) {
 	reqBody := []byte("{invalid_json}")
 
 	req := httptest.NewRequest("PUT", "/api/rent/some-rent-id", bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -311,6 +572,8 @@ func TestUpdateRentHandler_InvalidRentID_StatsBadRequest(t *testing.T) {
 	reqBody, _ := json.Marshal(updateRequest)
 
 	req := httptest.NewRequest("PUT", "/api/rent/invalid-rent-id", bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -331,16 +594,19 @@ func TestGetRentByIDHandler_ValidRentID(t *testing.T) {
 		NightPrice:   1000,
 		CheckInDate:  checkInDate,
 		CheckOutDate: checkInDate.Add(72 * time.Hour),
+		Version:      3,
 	}
 
-	mockService.On("GetRentByID", rentID).Return(expectedRent, nil)
+	mockService.On("GetRentByID", rentID, testCaller).Return(expectedRent, nil)
 
 	req := httptest.NewRequest("GET", "/api/rent/"+rentID.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
 
 	var resBody responses.GetRentResponse
 	err := json.NewDecoder(rec.Body).Decode(&resBody)
@@ -355,6 +621,7 @@ func TestGetRentByIDHandler_InvalidRentID(t *testing.T) {
 	router := setupTestRouter(mockService)
 
 	req := httptest.NewRequest("GET", "/api/rent/invalid-id", nil)
+	req.Header.Set("Authorization", "Bearer token")
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -370,9 +637,10 @@ func TestGetRentByIDHandler_RentNotFound(t *testing.T) {
 	router := setupTestRouter(mockService)
 
 	rentID := uuid.New()
-	mockService.On("GetRentByID", rentID).Return((*responses.GetRentResponse)(nil), nil)
+	mockService.On("GetRentByID", rentID, testCaller).Return((*responses.GetRentResponse)(nil), nil)
 
 	req := httptest.NewRequest("GET", "/api/rent/"+rentID.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -383,4 +651,501 @@ func TestGetRentByIDHandler_RentNotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCancelRentHandler_ValidRequest_Ok(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	reqBody := requests.CancelRentRequest{Reason: "Change of plans"}
+	cancellation := &responses.CancelRentResponse{
+		RentID:        rentID,
+		CancelledBy:   uuid.New(),
+		Reason:        reqBody.Reason,
+		TotalAmount:   2000_00,
+		PenaltyAmount: 1000_00,
+		RefundAmount:  1000_00,
+	}
+	mockService.On("CancelRent", rentID, reqBody, testCaller).Return(cancellation, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/cancel", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response responses.CancelRentResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, cancellation.RefundAmount, response.RefundAmount)
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelRentHandler_EmptyBody_Ok(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("CancelRent", rentID, requests.CancelRentRequest{}, testCaller).
+		Return(&responses.CancelRentResponse{RentID: rentID}, nil)
+
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/cancel", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelRentHandler_MissingAuthorization_Unauthorized(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("POST", "/api/rent/"+uuid.New().String()+"/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelRentHandler_AlreadyCancelled_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("CancelRent", rentID, requests.CancelRentRequest{}, testCaller).
+		Return((*responses.CancelRentResponse)(nil),
+			errors2.NewServiceBadRequestError("Rent cannot be cancelled", "the rent is already cancelled"))
+
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/cancel", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelRentHandler_RentNotFound(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("CancelRent", rentID, requests.CancelRentRequest{}, testCaller).
+		Return((*responses.CancelRentResponse)(nil), nil)
+
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/cancel", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransitionRentHandler_ValidRequest_Created(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	reqBody := requests.TransitionRentRequest{Status: "checked_in"}
+	transition := &responses.RentTransitionResponse{
+		ID:         uuid.New(),
+		RentID:     rentID,
+		FromStatus: "confirmed",
+		ToStatus:   "checked_in",
+		ChangedBy:  uuid.New(),
+	}
+	mockService.On("TransitionRent", rentID, reqBody, testCaller).Return(transition, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/transitions", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response responses.RentTransitionResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "checked_in", response.ToStatus)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransitionRentHandler_ForbiddenTransition_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	reqBody := requests.TransitionRentRequest{Status: "checked_out"}
+	mockService.On("TransitionRent", rentID, reqBody, testCaller).
+		Return((*responses.RentTransitionResponse)(nil),
+			errors2.NewServiceBadRequestError("Invalid status transition", "rent cannot move from pending to checked_out"))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/transitions", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransitionRentHandler_RentNotFound(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	reqBody := requests.TransitionRentRequest{Status: "confirmed"}
+	mockService.On("TransitionRent", rentID, reqBody, testCaller).Return((*responses.RentTransitionResponse)(nil), nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/transitions", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_InvalidStatus_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent?status=archived", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_InvalidDateMode_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent?from=2024-12-01T00:00:00Z&mode=during", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "GetRents")
+}
+
+func TestGetRents_DateMode_PassedToService(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	fromDate := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	expectedFilter := requests.RentFilter{FromDate: &fromDate, DateMode: services.DateModeStartsIn}
+	mockService.On("GetRents", expectedFilter, testCaller).
+		Return(&responses.GetRentsResponse{Rents: []responses.GetRentResponse{}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/rent?from=2024-12-01T00:00:00Z&mode=starts_in", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_PaginationParams_PassedToService(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	nextCursor := "next"
+	expectedFilter := requests.RentFilter{Limit: 10, Cursor: "abc", SortBy: services.SortByCreatedAt, SortOrder: services.SortOrderDesc}
+	mockService.On("GetRents", expectedFilter, testCaller).
+		Return(&responses.GetRentsResponse{Rents: []responses.GetRentResponse{}, NextCursor: &nextCursor}, nil)
+
+	req := httptest.NewRequest("GET", "/api/rent?limit=10&cursor=abc&sort=created_at&order=desc", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resBody responses.GetRentsResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, &nextCursor, resBody.NextCursor)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_InvalidLimit_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent?limit=100000", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_InvalidSort_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent?sort=price", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_InvalidCursor_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("GetRents", mock.Anything, testCaller).
+		Return((*responses.GetRentsResponse)(nil), errors2.NewServiceBadRequestError("Invalid cursor", ""))
+
+	req := httptest.NewRequest("GET", "/api/rent?cursor=broken", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRents_MissingAuthorization_Unauthorized(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "GetRents")
+}
+
+func TestGetRentByIDHandler_InvalidToken_Unauthorized(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent/"+uuid.New().String(), nil)
+	req.Header.Set("Authorization", "Bearer expired")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "GetRentByID")
+}
+
+func TestGetRentByIDHandler_RentOfAnotherClient_Forbidden(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("GetRentByID", rentID, testCaller).
+		Return((*responses.GetRentResponse)(nil), errors2.NewServiceForbiddenError("Access denied", ""))
+
+	req := httptest.NewRequest("GET", "/api/rent/"+rentID.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateRentHandler_RentOfAnotherClient_Forbidden(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	rentID := uuid.New()
+	updateRequest := requests.UpdateRentRequest{
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	}
+	reqBody, _ := json.Marshal(updateRequest)
+
+	mockService.On("UpdateRent", rentID, updateRequest, testCaller, 1).
+		Return((*responses.RentModificationResponse)(nil), errors2.NewServiceForbiddenError("Access denied", ""))
+
+	req := httptest.NewRequest("PUT", "/api/rent/"+rentID.String(), bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchRentHandler_ValidPatch_ReturnRent(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	patch := []byte(`{"check_out_date":"2025-01-05T12:00:00Z"}`)
+	patchedRent := &responses.GetRentResponse{ID: rentID, ClientID: testCaller.Id}
+	mockService.On("PatchRent", rentID, patch, testCaller, 1).Return(patchedRent, nil)
+
+	req := httptest.NewRequest("PATCH", "/api/rent/"+rentID.String(), bytes.NewReader(patch))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resBody responses.GetRentResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, rentID, resBody.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchRentHandler_InvalidMergedRent_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	patch := []byte(`{"hotel_id":null}`)
+	mockService.On("PatchRent", rentID, patch, testCaller, 1).
+		Return((*responses.GetRentResponse)(nil), errors2.NewServiceBadRequestError("Invalid rent", "hotel ID is required"))
+
+	req := httptest.NewRequest("PATCH", "/api/rent/"+rentID.String(), bytes.NewReader(patch))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchRentHandler_RentNotFound(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	rentID := uuid.New()
+	patch := []byte(`{}`)
+	mockService.On("PatchRent", rentID, patch, testCaller, 1).Return((*responses.GetRentResponse)(nil), nil)
+
+	req := httptest.NewRequest("PATCH", "/api/rent/"+rentID.String(), bytes.NewReader(patch))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchRentHandler_UnsupportedContentType(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("PATCH", "/api/rent/"+uuid.New().String(), bytes.NewReader([]byte("check_out_date=tomorrow")))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	mockService.AssertNotCalled(t, "PatchRent")
+}
+
+func TestUpdateRentHandler_MissingIfMatch_PreconditionRequired(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	reqBody, _ := json.Marshal(requests.UpdateRentRequest{
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	})
+
+	req := httptest.NewRequest("PUT", "/api/rent/"+uuid.New().String(), bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	mockService.AssertNotCalled(t, "UpdateRent")
+}
+
+func TestUpdateRentHandler_StaleVersion_PreconditionFailed(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Truncate(time.Second)
+	rentID := uuid.New()
+	updateRequest := requests.UpdateRentRequest{
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	}
+	reqBody, _ := json.Marshal(updateRequest)
+
+	mockService.On("UpdateRent", rentID, updateRequest, testCaller, 2).
+		Return((*responses.RentModificationResponse)(nil), errors2.NewServicePreconditionFailedError("Rent was modified", ""))
+
+	req := httptest.NewRequest("PUT", "/api/rent/"+rentID.String(), bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", `"2"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchRentHandler_MalformedIfMatch_PreconditionFailed(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("PATCH", "/api/rent/"+uuid.New().String(), bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("If-Match", "abc")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	mockService.AssertNotCalled(t, "PatchRent")
+}
+
 // endregion
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func AddRoomsHandler(service services.IInventoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the rooms adding handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		var req requests.AddRoomsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if len(req.RoomNumbers) == 0 {
+			http.Error(w, "At least one room number is required", http.StatusBadRequest)
+			slog.Error("At least one room number is required" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		for _, roomNumber := range req.RoomNumbers {
+			if strings.TrimSpace(roomNumber) == "" {
+				http.Error(w, "Room number cannot be empty", http.StatusBadRequest)
+				slog.Error("Room number cannot be empty" + strconv.Itoa(http.StatusBadRequest))
+				return
+			}
+		}
+
+		roomIDs, err := service.AddRooms(hotelID, req, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to add rooms", http.StatusInternalServerError)
+				slog.Error("Failed to add rooms" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(roomIDs); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The rooms were successfully added")
+		slog.Info("Hotel ID: " + hotelID.String())
+	}
+}
+
+func GetRoomsHandler(service services.IInventoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the rooms getting handler")
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		rooms, err := service.GetRooms(hotelID)
+		if err != nil {
+			http.Error(w, "Failed to fetch rooms", http.StatusInternalServerError)
+			slog.Error("Failed to fetch rooms" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rooms); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The rooms were successfully got")
+		slog.Info("Hotel ID: " + hotelID.String())
+	}
+}
@@ -0,0 +1,173 @@
+package rest_test
+
+import (
+	errors2 "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// region Mock Inventory Service
+type MockInventoryService struct {
+	mock.Mock
+}
+
+func (m *MockInventoryService) AddRooms(hotelID uuid.UUID, req requests.AddRoomsRequest, caller *user_service.UserData) ([]uuid.UUID, error) {
+	args := m.Called(hotelID, req, caller)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockInventoryService) GetRooms(hotelID uuid.UUID) (*responses.GetRoomsResponse, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(*responses.GetRoomsResponse), args.Error(1)
+}
+
+func setupInventoryTestRouter(service *MockInventoryService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{InventoryService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestAddRooms_CommonCase_Created(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.AddRoomsRequest{RoomNumbers: []string{"101", "102"}}
+	roomIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	mockService.On("AddRooms", hotelID, reqBody, testCaller).Return(roomIDs, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/hotel/"+hotelID.String()+"/inventory", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var returnedIDs []uuid.UUID
+	err := json.NewDecoder(rec.Body).Decode(&returnedIDs)
+	assert.NoError(t, err)
+	assert.Equal(t, roomIDs, returnedIDs)
+	mockService.AssertExpectations(t)
+}
+
+func TestAddRooms_EmptyRoomNumbers_BadRequest(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.AddRoomsRequest{})
+	req := httptest.NewRequest("POST", "/api/hotel/"+uuid.New().String()+"/inventory", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAddRooms_DuplicateRoom_BadRequest(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.AddRoomsRequest{RoomNumbers: []string{"101"}}
+	mockService.On("AddRooms", hotelID, reqBody, testCaller).
+		Return([]uuid.UUID(nil), errors2.NewServiceBadRequestError("Room already exists", "101"))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/hotel/"+hotelID.String()+"/inventory", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAddRooms_NotAuthenticated_Unauthorized(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.AddRoomsRequest{RoomNumbers: []string{"101"}})
+	req := httptest.NewRequest("POST", "/api/hotel/"+uuid.New().String()+"/inventory", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "AddRooms")
+}
+
+func TestAddRooms_HotelOfAnotherOwner_Forbidden(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.AddRoomsRequest{RoomNumbers: []string{"101"}}
+	mockService.On("AddRooms", hotelID, reqBody, testCaller).
+		Return([]uuid.UUID(nil), errors2.NewServiceForbiddenError("Access denied", "the hotel is administered by another owner"))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/hotel/"+hotelID.String()+"/inventory", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRooms_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	hotelID := uuid.New()
+	rooms := &responses.GetRoomsResponse{
+		Rooms: []responses.GetRoomResponse{{ID: uuid.New(), HotelID: hotelID, RoomNumber: "101"}},
+	}
+	mockService.On("GetRooms", hotelID).Return(rooms, nil)
+
+	req := httptest.NewRequest("GET", "/api/hotel/"+hotelID.String()+"/inventory", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resBody responses.GetRoomsResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, rooms, &resBody)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRooms_InvalidHotelID_BadRequest(t *testing.T) {
+	mockService := new(MockInventoryService)
+	router := setupInventoryTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/hotel/invalid-id/inventory", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// endregion
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"bytes"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Formats of the rendered invoices
+const (
+	InvoiceFormatHTML = "html"
+	InvoiceFormatPDF  = "pdf"
+)
+
+// GetInvoiceHandler renders the invoice of a rent, issuing it on the first request
+func GetInvoiceHandler(service services.IInvoiceService) http.HandlerFunc {
+	return invoiceDocumentHandler("invoice",
+		func(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error) {
+			return service.GetInvoice(rentID, caller)
+		})
+}
+
+// IssueCreditNoteHandler cancels the invoice of a rent and renders the credit note
+func IssueCreditNoteHandler(service services.IInvoiceService) http.HandlerFunc {
+	return invoiceDocumentHandler("credit note",
+		func(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error) {
+			return service.IssueCreditNote(rentID, caller)
+		})
+}
+
+func invoiceDocumentHandler(
+	document string,
+	handle func(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the " + document + " handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		rentID, err := uuid.Parse(vars["rent_id"])
+		if err != nil {
+			http.Error(w, "Invalid rent ID", http.StatusBadRequest)
+			slog.Error("Invalid rent ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+		format, ok := invoiceFormat(r)
+		if !ok {
+			http.Error(w, "Invalid invoice format", http.StatusBadRequest)
+			slog.Error("Invalid invoice format" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		invoice, err := handle(rentID, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+			} else {
+				http.Error(w, "Failed to get "+document, http.StatusInternalServerError)
+				slog.Error("Failed to get " + document + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		if invoice == nil {
+			http.Error(w, "Rent not found", http.StatusNotFound)
+			slog.Error("Rent not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		// The document is rendered in full before it is sent, so a failure still returns an error status
+		var body bytes.Buffer
+		write, contentType := writeInvoiceHTML, "text/html; charset=utf-8"
+		if format == InvoiceFormatPDF {
+			write, contentType = writeInvoicePDF, "application/pdf"
+		}
+		if err := write(&body, invoice); err != nil {
+			http.Error(w, "Failed to render "+document, http.StatusInternalServerError)
+			slog.Error("Failed to render " + document + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `inline; filename="`+invoice.Number+"."+format+`"`)
+		if _, err := io.Copy(w, &body); err != nil {
+			slog.Error("Failed to write " + document + ": " + err.Error())
+			return
+		}
+		slog.Info("The " + document + " " + invoice.Number + " was successfully rendered")
+	}
+}
+
+// invoiceFormat takes the format from the format query parameter, then from the Accept header, HTML by default
+func invoiceFormat(r *http.Request) (string, bool) {
+	switch format := r.URL.Query().Get("format"); format {
+	case InvoiceFormatHTML, InvoiceFormatPDF:
+		return format, true
+	case "":
+	default:
+		return "", false
+	}
+	if strings.Contains(r.Header.Get("Accept"), "application/pdf") {
+		return InvoiceFormatPDF, true
+	}
+	return InvoiceFormatHTML, true
+}
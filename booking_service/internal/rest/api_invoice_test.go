@@ -0,0 +1,145 @@
+package rest_test
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// region Mock Invoice Service
+type MockInvoiceService struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceService) GetInvoice(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error) {
+	args := m.Called(rentID, caller)
+	return args.Get(0).(*responses.InvoiceResponse), args.Error(1)
+}
+
+func (m *MockInvoiceService) IssueCreditNote(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error) {
+	args := m.Called(rentID, caller)
+	return args.Get(0).(*responses.InvoiceResponse), args.Error(1)
+}
+
+func setupInvoiceTestRouter(service *MockInvoiceService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{InvoiceService: service, UserServiceBridge: userBridgeMock})
+}
+
+func newTestInvoice(rentID uuid.UUID) *responses.InvoiceResponse {
+	return &responses.InvoiceResponse{
+		ID:           uuid.New(),
+		Number:       "INV-1A2B3C4D-000001",
+		Kind:         "invoice",
+		RentID:       rentID,
+		IssuedAt:     time.Date(2024, 12, 21, 10, 0, 0, 0, time.UTC),
+		HotelID:      uuid.New(),
+		HotelName:    "Hotel <Sunrise>",
+		GuestID:      testCaller.Id,
+		GuestName:    "John (Jack) Doe",
+		CheckInDate:  time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+		Lines: []responses.InvoiceLineResponse{
+			{Description: "Accommodation", Quantity: 2, UnitPrice: 1000_00, Amount: 2000_00},
+			{Description: "Service fee", Quantity: 1, UnitPrice: 500_00, Amount: 500_00},
+		},
+		Total:    2500_00,
+		Currency: "RUB",
+	}
+}
+
+// endregion
+
+// region Tests
+
+func TestGetInvoice_DefaultFormat_Html(t *testing.T) {
+	mockService := new(MockInvoiceService)
+	router := setupInvoiceTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("GetInvoice", rentID, testCaller).Return(newTestInvoice(rentID), nil)
+
+	req := httptest.NewRequest("GET", "/api/rent/"+rentID.String()+"/invoice", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "Invoice INV-1A2B3C4D-000001")
+	assert.Contains(t, body, "Hotel &lt;Sunrise&gt;")
+	assert.Contains(t, body, "<td>2000.00</td>")
+	assert.Contains(t, body, "<th>2500.00</th>")
+	mockService.AssertExpectations(t)
+}
+
+func TestGetInvoice_PdfRequested_Pdf(t *testing.T) {
+	mockService := new(MockInvoiceService)
+	router := setupInvoiceTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("GetInvoice", rentID, testCaller).Return(newTestInvoice(rentID), nil)
+
+	req := httptest.NewRequest("GET", "/api/rent/"+rentID.String()+"/invoice", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Accept", "application/pdf")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(body, "%%EOF\n"))
+	assert.Contains(t, body, `(John \(Jack\) Doe) Tj`)
+	assert.Contains(t, body, "(Total: 2500.00 RUB) Tj")
+	mockService.AssertExpectations(t)
+}
+
+func TestGetInvoice_UnknownFormat_BadRequest(t *testing.T) {
+	mockService := new(MockInvoiceService)
+	router := setupInvoiceTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent/"+uuid.New().String()+"/invoice?format=docx", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "GetInvoice", mock.Anything, mock.Anything)
+}
+
+func TestIssueCreditNote_NoInvoice_UnprocessableEntity(t *testing.T) {
+	mockService := new(MockInvoiceService)
+	router := setupInvoiceTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("IssueCreditNote", rentID, testCaller).Return((*responses.InvoiceResponse)(nil),
+		custom_errors.NewServiceUnprocessableEntityError("Credit note cannot be issued", "the rent has no invoice to credit"))
+
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/invoice/credit-note?format=pdf", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
@@ -0,0 +1,130 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/payment_provider"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// PaymentSignatureHeader carries the signature of the webhooks sent by the payment provider
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+// maxWebhookSize limits the body of a payment webhook
+const maxWebhookSize = 64 << 10
+
+// AuthorizePaymentHandler pays for a pending rent, the rent is confirmed once the payment is authorized
+func AuthorizePaymentHandler(service services.IPaymentService) http.HandlerFunc {
+	return paymentOperationHandler("authorization",
+		func(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+			return service.AuthorizePayment(rentID, caller)
+		})
+}
+
+// CapturePaymentHandler charges the authorized payment of a rent
+func CapturePaymentHandler(service services.IPaymentService) http.HandlerFunc {
+	return paymentOperationHandler("capture",
+		func(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+			return service.CapturePayment(rentID, caller)
+		})
+}
+
+// RefundPaymentHandler refunds the payment of a cancelled rent
+func RefundPaymentHandler(service services.IPaymentService) http.HandlerFunc {
+	return paymentOperationHandler("refund",
+		func(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+			return service.RefundPayment(rentID, caller)
+		})
+}
+
+func paymentOperationHandler(
+	operation string,
+	handle func(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the payment " + operation + " handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		rentID, err := uuid.Parse(vars["rent_id"])
+		if err != nil {
+			http.Error(w, "Invalid rent ID", http.StatusBadRequest)
+			slog.Error("Invalid rent ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		payment, err := handle(rentID, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+			} else if errors.Is(err, payment_provider.ErrTimeout) {
+				// The provider reports the outcome later with a webhook
+				http.Error(w, "Payment provider timed out", http.StatusGatewayTimeout)
+				slog.Error("Payment provider timed out" + strconv.Itoa(http.StatusGatewayTimeout))
+			} else {
+				http.Error(w, "Failed to process payment "+operation, http.StatusInternalServerError)
+				slog.Error("Failed to process payment " + operation + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		if payment == nil {
+			http.Error(w, "Rent not found", http.StatusNotFound)
+			slog.Error("Rent not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(payment); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The payment " + operation + " was successfully processed")
+		slog.Info("Rent ID: " + rentID.String())
+	}
+}
+
+// PaymentWebhookHandler receives the outcomes of payments from the provider, it is authenticated by the signature
+func PaymentWebhookHandler(service services.IPaymentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the payment webhook handler")
+		payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookSize))
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if err := service.HandleWebhook(payload, r.Header.Get(PaymentSignatureHeader)); err != nil {
+			if errors.Is(err, payment_provider.ErrInvalidSignature) {
+				http.Error(w, "Invalid signature", http.StatusUnauthorized)
+				slog.Error("Invalid signature" + strconv.Itoa(http.StatusUnauthorized))
+			} else if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else {
+				http.Error(w, "Failed to handle payment webhook", http.StatusInternalServerError)
+				slog.Error("Failed to handle payment webhook" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		slog.Info("The payment webhook was successfully handled")
+	}
+}
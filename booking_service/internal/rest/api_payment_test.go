@@ -0,0 +1,130 @@
+package rest_test
+
+import (
+	"booking_service/internal/rest"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/payment_provider"
+	"booking_service/internal/service_interaction/user_service"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// region Mock Payment Service
+type MockPaymentService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentService) AuthorizePayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	args := m.Called(rentID, caller)
+	return args.Get(0).(*responses.PaymentResponse), args.Error(1)
+}
+
+func (m *MockPaymentService) CapturePayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	args := m.Called(rentID, caller)
+	return args.Get(0).(*responses.PaymentResponse), args.Error(1)
+}
+
+func (m *MockPaymentService) RefundPayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	args := m.Called(rentID, caller)
+	return args.Get(0).(*responses.PaymentResponse), args.Error(1)
+}
+
+func (m *MockPaymentService) HandleWebhook(payload []byte, signature string) error {
+	args := m.Called(payload, signature)
+	return args.Error(0)
+}
+
+func setupPaymentTestRouter(service *MockPaymentService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{PaymentService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestAuthorizePayment_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockPaymentService)
+	router := setupPaymentTestRouter(mockService)
+
+	rentID := uuid.New()
+	payment := &responses.PaymentResponse{RentID: rentID, PaymentID: "fake_" + rentID.String(), PaymentStatus: "authorized", RentStatus: "confirmed"}
+	mockService.On("AuthorizePayment", rentID, testCaller).Return(payment, nil)
+
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/payment", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resBody responses.PaymentResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, "confirmed", resBody.RentStatus)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthorizePayment_ProviderTimeout_GatewayTimeout(t *testing.T) {
+	mockService := new(MockPaymentService)
+	router := setupPaymentTestRouter(mockService)
+
+	rentID := uuid.New()
+	mockService.On("AuthorizePayment", rentID, testCaller).
+		Return((*responses.PaymentResponse)(nil), fmt.Errorf("failed to authorize payment: %w", payment_provider.ErrTimeout))
+
+	req := httptest.NewRequest("POST", "/api/rent/"+rentID.String()+"/payment", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentWebhook_CommonCase_NoContent(t *testing.T) {
+	mockService := new(MockPaymentService)
+	router := setupPaymentTestRouter(mockService)
+
+	payload := []byte(`{"payment_id":"fake_1","reference":"1","status":"authorized"}`)
+	mockService.On("HandleWebhook", payload, "signature").Return(nil)
+
+	req := httptest.NewRequest("POST", "/api/payments/webhook", bytes.NewReader(payload))
+	req.Header.Set(rest.PaymentSignatureHeader, "signature")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentWebhook_InvalidSignature_Unauthorized(t *testing.T) {
+	mockService := new(MockPaymentService)
+	router := setupPaymentTestRouter(mockService)
+
+	payload := []byte(`{"payment_id":"fake_1","reference":"1","status":"captured"}`)
+	mockService.On("HandleWebhook", payload, "forged").Return(payment_provider.ErrInvalidSignature)
+
+	req := httptest.NewRequest("POST", "/api/payments/webhook", bytes.NewReader(payload))
+	req.Header.Set(rest.PaymentSignatureHeader, "forged")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
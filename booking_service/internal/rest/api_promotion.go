@@ -0,0 +1,247 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func CreatePromoCodeHandler(service services.IPromotionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the promo code creation handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		req, ok := decodePromoCodeRequest(w, r)
+		if !ok {
+			return
+		}
+
+		promoCode, err := service.CreatePromoCode(req, caller)
+		if err != nil {
+			writePromoCodeError(w, err, "Failed to create promo code")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(promoCode); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The promo code was successfully created")
+		slog.Info("Promo code ID: " + promoCode.ID.String())
+	}
+}
+
+// GetPromoCodesHandler lists the codes of the hotel given in the query, or the global codes without it
+func GetPromoCodesHandler(service services.IPromotionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the promo codes getting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		var hotelID *uuid.UUID
+		if hotelIDStr := r.URL.Query().Get("hotel"); hotelIDStr != "" {
+			id, err := uuid.Parse(hotelIDStr)
+			if err != nil {
+				http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+				slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
+				return
+			}
+			hotelID = &id
+		}
+
+		promoCodes, err := service.GetPromoCodes(hotelID, caller)
+		if err != nil {
+			writePromoCodeError(w, err, "Failed to fetch promo codes")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(promoCodes); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The promo codes were successfully got")
+	}
+}
+
+func GetPromoCodeHandler(service services.IPromotionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the promo code getting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		promoCodeID, ok := parsePromoCodeID(w, r)
+		if !ok {
+			return
+		}
+
+		promoCode, err := service.GetPromoCode(promoCodeID, caller)
+		if err != nil {
+			writePromoCodeError(w, err, "Failed to fetch promo code")
+			return
+		}
+
+		if promoCode == nil {
+			http.Error(w, "Promo code not found", http.StatusNotFound)
+			slog.Error("Promo code not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(promoCode); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The promo code was successfully got")
+		slog.Info("Promo code ID: " + promoCodeID.String())
+	}
+}
+
+func UpdatePromoCodeHandler(service services.IPromotionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the promo code update handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		promoCodeID, ok := parsePromoCodeID(w, r)
+		if !ok {
+			return
+		}
+
+		req, ok := decodePromoCodeRequest(w, r)
+		if !ok {
+			return
+		}
+
+		promoCode, err := service.UpdatePromoCode(promoCodeID, req, caller)
+		if err != nil {
+			writePromoCodeError(w, err, "Failed to update promo code")
+			return
+		}
+
+		if promoCode == nil {
+			http.Error(w, "Promo code not found", http.StatusNotFound)
+			slog.Error("Promo code not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(promoCode); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The promo code was successfully updated")
+		slog.Info("Promo code ID: " + promoCodeID.String())
+	}
+}
+
+func DeletePromoCodeHandler(service services.IPromotionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the promo code deletion handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		promoCodeID, ok := parsePromoCodeID(w, r)
+		if !ok {
+			return
+		}
+
+		promoCode, err := service.DeletePromoCode(promoCodeID, caller)
+		if err != nil {
+			writePromoCodeError(w, err, "Failed to delete promo code")
+			return
+		}
+
+		if promoCode == nil {
+			http.Error(w, "Promo code not found", http.StatusNotFound)
+			slog.Error("Promo code not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		slog.Info("The promo code was successfully deleted")
+		slog.Info("Promo code ID: " + promoCodeID.String())
+	}
+}
+
+func parsePromoCodeID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	promoCodeID, err := uuid.Parse(mux.Vars(r)["promo_code_id"])
+	if err != nil {
+		http.Error(w, "Invalid promo code ID", http.StatusBadRequest)
+		slog.Error("Invalid promo code ID" + strconv.Itoa(http.StatusBadRequest))
+		return uuid.Nil, false
+	}
+	return promoCodeID, true
+}
+
+// decodePromoCodeRequest reads the terms of a promo code and checks that they make sense
+func decodePromoCodeRequest(w http.ResponseWriter, r *http.Request) (requests.SetPromoCodeRequest, bool) {
+	var req requests.SetPromoCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+		return req, false
+	}
+
+	var message string
+	switch {
+	case strings.TrimSpace(req.Code) == "":
+		message = "Promo code cannot be empty"
+	case req.DiscountType != services.DiscountTypePercent && req.DiscountType != services.DiscountTypeFixed:
+		message = "Discount type must be " + services.DiscountTypePercent + " or " + services.DiscountTypeFixed
+	case req.DiscountValue <= 0:
+		message = "Discount value must be positive"
+	case req.DiscountType == services.DiscountTypePercent && req.DiscountValue > 100:
+		message = "Discount percentage cannot exceed 100"
+	case req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidUntil.After(*req.ValidFrom):
+		message = "Promo code must be valid until a moment after it becomes valid"
+	case req.MaxUses != nil && *req.MaxUses <= 0:
+		message = "Usage limit must be positive"
+	case req.MinNights < 0:
+		message = "Minimum stay cannot be negative"
+	}
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		slog.Error(message + strconv.Itoa(http.StatusBadRequest))
+		return req, false
+	}
+	return req, true
+}
+
+func writePromoCodeError(w http.ResponseWriter, err error, message string) {
+	if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+	} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+	} else if errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		slog.Error(err.Error() + strconv.Itoa(http.StatusUnprocessableEntity))
+	} else {
+		http.Error(w, message, http.StatusInternalServerError)
+		slog.Error(message + strconv.Itoa(http.StatusInternalServerError))
+	}
+}
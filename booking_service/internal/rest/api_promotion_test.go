@@ -0,0 +1,133 @@
+package rest_test
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// region Mock Promotion Service
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockPromotionService) CreatePromoCode(req requests.SetPromoCodeRequest, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	args := m.Called(req, caller)
+	return args.Get(0).(*responses.PromoCodeResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) GetPromoCodes(hotelID *uuid.UUID, caller *user_service.UserData) ([]responses.PromoCodeResponse, error) {
+	args := m.Called(hotelID, caller)
+	return args.Get(0).([]responses.PromoCodeResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) GetPromoCode(promoCodeID uuid.UUID, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	args := m.Called(promoCodeID, caller)
+	return args.Get(0).(*responses.PromoCodeResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) UpdatePromoCode(promoCodeID uuid.UUID, req requests.SetPromoCodeRequest, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	args := m.Called(promoCodeID, req, caller)
+	return args.Get(0).(*responses.PromoCodeResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) DeletePromoCode(promoCodeID uuid.UUID, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	args := m.Called(promoCodeID, caller)
+	return args.Get(0).(*responses.PromoCodeResponse), args.Error(1)
+}
+
+func setupPromotionTestRouter(service *MockPromotionService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{PromotionService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestCreatePromoCode_CommonCase_Created(t *testing.T) {
+	mockService := new(MockPromotionService)
+	router := setupPromotionTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.SetPromoCodeRequest{Code: "WINTER", HotelID: &hotelID, DiscountType: "percent", DiscountValue: 15}
+	promoCode := &responses.PromoCodeResponse{ID: uuid.New(), Code: "WINTER", HotelID: &hotelID, DiscountType: "percent", DiscountValue: 15}
+	mockService.On("CreatePromoCode", reqBody, testCaller).Return(promoCode, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/promo-codes", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resBody responses.PromoCodeResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, promoCode.ID, resBody.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreatePromoCode_PercentAboveHundred_BadRequest(t *testing.T) {
+	mockService := new(MockPromotionService)
+	router := setupPromotionTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.SetPromoCodeRequest{Code: "ALL", DiscountType: "percent", DiscountValue: 150})
+	req := httptest.NewRequest("POST", "/api/promo-codes", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "CreatePromoCode", mock.Anything, mock.Anything)
+}
+
+func TestGetPromoCodes_GlobalCodesByGuest_Forbidden(t *testing.T) {
+	mockService := new(MockPromotionService)
+	router := setupPromotionTestRouter(mockService)
+
+	mockService.On("GetPromoCodes", (*uuid.UUID)(nil), testCaller).
+		Return([]responses.PromoCodeResponse(nil), custom_errors.NewServiceForbiddenError("Access denied", "administrators only"))
+
+	req := httptest.NewRequest("GET", "/api/promo-codes", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeletePromoCode_NotFound(t *testing.T) {
+	mockService := new(MockPromotionService)
+	router := setupPromotionTestRouter(mockService)
+
+	promoCodeID := uuid.New()
+	mockService.On("DeletePromoCode", promoCodeID, testCaller).Return((*responses.PromoCodeResponse)(nil), nil)
+
+	req := httptest.NewRequest("DELETE", "/api/promo-codes/"+promoCodeID.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
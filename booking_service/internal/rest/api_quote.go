@@ -0,0 +1,71 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func QuoteHandler(service services.IPricingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the quote handler")
+		queryParams := r.URL.Query()
+
+		hotelID, errHotel := uuid.Parse(queryParams.Get("hotel"))
+		fromDate, errFrom := time.Parse(time.RFC3339, queryParams.Get("from"))
+		toDate, errTo := time.Parse(time.RFC3339, queryParams.Get("to"))
+
+		var roomTypeID *uuid.UUID
+		var errRoomType error
+		if roomTypeIDStr := queryParams.Get("room_type"); roomTypeIDStr != "" {
+			var id uuid.UUID
+			id, errRoomType = uuid.Parse(roomTypeIDStr)
+			roomTypeID = &id
+		}
+
+		if errHotel != nil || errFrom != nil || errTo != nil || errRoomType != nil {
+			http.Error(w, "Invalid data (failed to parse)", http.StatusBadRequest)
+			slog.Error("Invalid data (failed to parse)" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if !toDate.After(fromDate) {
+			http.Error(w, "Check-out date must be after check-in date", http.StatusBadRequest)
+			slog.Error("Check-out date must be after check-in date" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		quote, err := service.Quote(requests.QuoteRequest{
+			HotelID:      hotelID,
+			RoomTypeID:   roomTypeID,
+			CheckInDate:  fromDate,
+			CheckOutDate: toDate,
+		})
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else {
+				http.Error(w, "Failed to quote stay", http.StatusInternalServerError)
+				slog.Error("Failed to quote stay" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(quote); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The stay was successfully quoted")
+		slog.Info("Hotel ID: " + hotelID.String())
+	}
+}
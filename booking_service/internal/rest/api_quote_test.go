@@ -0,0 +1,111 @@
+package rest_test
+
+import (
+	errors2 "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// region Mock Pricing Service
+type MockPricingService struct {
+	mock.Mock
+}
+
+func (m *MockPricingService) Quote(req requests.QuoteRequest) (*responses.QuoteResponse, error) {
+	args := m.Called(req)
+	return args.Get(0).(*responses.QuoteResponse), args.Error(1)
+}
+
+// endregion
+
+// region Tests
+
+func TestQuote_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockPricingService)
+	router := setupApiTestRouter(&server.ApiServices{PricingService: mockService})
+
+	hotelID := uuid.New()
+	from := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	quote := &responses.QuoteResponse{
+		HotelID:    hotelID,
+		NightPrice: 1000_00,
+		Nights:     2,
+		Breakdown: []responses.QuoteNightResponse{
+			{Date: "2024-12-20", Price: 1000_00},
+			{Date: "2024-12-21", Price: 1000_00},
+		},
+		Subtotal: 2000_00,
+		Taxes:    20_00,
+		Total:    2020_00,
+		Currency: "RUB",
+	}
+	mockService.On("Quote", requests.QuoteRequest{HotelID: hotelID, CheckInDate: from, CheckOutDate: to}).Return(quote, nil)
+
+	query := url.Values{}
+	query.Set("hotel", hotelID.String())
+	query.Set("from", from.Format(time.RFC3339))
+	query.Set("to", to.Format(time.RFC3339))
+	req := httptest.NewRequest("GET", "/api/rent/quote?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response responses.QuoteResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Total, response.Total)
+	assert.Len(t, response.Breakdown, 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestQuote_MissingDates_BadRequest(t *testing.T) {
+	mockService := new(MockPricingService)
+	router := setupApiTestRouter(&server.ApiServices{PricingService: mockService})
+
+	req := httptest.NewRequest("GET", "/api/rent/quote?hotel="+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestQuote_UnknownRoomType_BadRequest(t *testing.T) {
+	mockService := new(MockPricingService)
+	router := setupApiTestRouter(&server.ApiServices{PricingService: mockService})
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	from := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	mockService.On("Quote", requests.QuoteRequest{HotelID: hotelID, RoomTypeID: &roomTypeID, CheckInDate: from, CheckOutDate: to}).
+		Return((*responses.QuoteResponse)(nil), errors2.NewServiceBadRequestError("Room type not found", roomTypeID.String()))
+
+	query := url.Values{}
+	query.Set("hotel", hotelID.String())
+	query.Set("room_type", roomTypeID.String())
+	query.Set("from", from.Format(time.RFC3339))
+	query.Set("to", to.Format(time.RFC3339))
+	req := httptest.NewRequest("GET", "/api/rent/quote?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
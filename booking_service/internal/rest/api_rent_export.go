@@ -0,0 +1,204 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/services"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the rents export
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportFlushInterval is the number of rents after which the export is flushed to the client
+const exportFlushInterval = 100
+
+var exportedRentColumns = []string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date",
+	"check_out_date", "status", "payment_status", "night_price", "nights", "currency", "total_amount", "adults",
+	"children", "primary_guest_name", "created_at", "version"}
+
+// ExportRentsHandler streams the rents of the hotels of the owner as CSV or NDJSON. The rents are
+// filtered like the rents list, but are not paginated
+func ExportRentsHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the rents export handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		queryParams := r.URL.Query()
+		format := queryParams.Get("format")
+		if format == "" {
+			format = ExportFormatCSV
+		}
+		if format != ExportFormatCSV && format != ExportFormatNDJSON {
+			http.Error(w, "Invalid export format", http.StatusBadRequest)
+			slog.Error("Invalid export format" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+		filter, ok := parseRentFilter(w, queryParams)
+		if !ok {
+			return
+		}
+
+		exporter := &rentExporter{w: w, format: format}
+		if err := service.ExportRents(r.Context(), filter, caller, exporter.write); err != nil {
+			if exporter.started {
+				// The status is already sent, the client gets a truncated export
+				slog.Error("Failed to export rents: " + err.Error())
+				return
+			}
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to export rents", http.StatusInternalServerError)
+				slog.Error("Failed to export rents" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+		if err := exporter.finish(); err != nil {
+			slog.Error("Failed to export rents: " + err.Error())
+			return
+		}
+
+		slog.Info("The rents were successfully exported")
+	}
+}
+
+// rentExporter writes the rents in the format of the export. The response is started with the first rent,
+// so an error of the service before it is still answered with its status
+type rentExporter struct {
+	w       http.ResponseWriter
+	format  string
+	csv     *csv.Writer
+	json    *json.Encoder
+	written int
+	started bool
+}
+
+func (e *rentExporter) start() error {
+	e.started = true
+	if e.format == ExportFormatCSV {
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	e.w.Header().Set("Content-Disposition", `attachment; filename="rents.`+e.format+`"`)
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.format == ExportFormatCSV {
+		e.csv = csv.NewWriter(e.w)
+		return e.csv.Write(exportedRentColumns)
+	}
+	e.json = json.NewEncoder(e.w)
+	return nil
+}
+
+func (e *rentExporter) write(rent *responses.ExportedRentResponse) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if e.csv != nil {
+		err = e.csv.Write(exportedRentRecord(rent))
+	} else {
+		err = e.json.Encode(rent)
+	}
+	if err != nil {
+		return err
+	}
+	e.written++
+	if e.written%exportFlushInterval == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// finish starts the response of an empty export and sends what is left
+func (e *rentExporter) finish() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *rentExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := http.NewResponseController(e.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// exportedRentRecord renders the rent as a CSV record in the order of exportedRentColumns,
+// missing values are left empty
+func exportedRentRecord(rent *responses.ExportedRentResponse) []string {
+	optionalInt := func(value *int) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.Itoa(*value)
+	}
+	roomTypeID := ""
+	if rent.RoomTypeID != nil {
+		roomTypeID = rent.RoomTypeID.String()
+	}
+	currency := ""
+	if rent.Currency != nil {
+		currency = *rent.Currency
+	}
+
+	return []string{
+		rent.ID.String(),
+		rent.HotelID.String(),
+		rent.RoomID.String(),
+		roomTypeID,
+		rent.ClientID.String(),
+		rent.CheckInDate.Format(time.RFC3339),
+		rent.CheckOutDate.Format(time.RFC3339),
+		rent.Status,
+		rent.PaymentStatus,
+		optionalInt(rent.NightPrice),
+		optionalInt(rent.Nights),
+		currency,
+		optionalInt(rent.TotalAmount),
+		strconv.Itoa(rent.Adults),
+		strconv.Itoa(rent.Children),
+		spreadsheetText(rent.PrimaryGuestName),
+		rent.CreatedAt.Format(time.RFC3339),
+		strconv.Itoa(rent.Version),
+	}
+}
+
+// spreadsheetText keeps a text entered by a guest from being taken for a formula when the export is
+// opened in a spreadsheet
+func spreadsheetText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
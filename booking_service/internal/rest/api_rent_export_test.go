@@ -0,0 +1,151 @@
+package rest_test
+
+import (
+	errors2 "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/services"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newExportedRents(hotelID uuid.UUID) []responses.ExportedRentResponse {
+	nightPrice, nights, currency, totalAmount := 1000_00, 2, services.DefaultCurrency, 2000_00
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	return []responses.ExportedRentResponse{
+		{
+			ID: uuid.New(), HotelID: hotelID, RoomID: uuid.New(), ClientID: uuid.New(),
+			CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Status: services.StatusConfirmed,
+			PaymentStatus: "paid", NightPrice: &nightPrice, Nights: &nights, Currency: &currency,
+			TotalAmount: &totalAmount, Adults: 2, PrimaryGuestName: "=HYPERLINK(\"http://example.com\")",
+			CreatedAt: checkIn.AddDate(0, 0, -7), Version: 1,
+		},
+		// A rent booked before prices were stored with rents
+		{
+			ID: uuid.New(), HotelID: hotelID, RoomID: uuid.New(), ClientID: uuid.New(),
+			CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), Status: services.StatusCheckedOut,
+			PaymentStatus: "unpaid", Adults: 1, CreatedAt: checkIn.AddDate(0, -1, 0), Version: 3,
+		},
+	}
+}
+
+func TestExportRents_CSV_RentsStreamedWithStoredPrices(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+	fromDate := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	rents := newExportedRents(hotelID)
+	expectedFilter := requests.RentFilter{HotelID: hotelID, FromDate: &fromDate, DateMode: services.DateModeStartsIn}
+	mockService.On("ExportRents", expectedFilter, testCaller).Return(rents, nil)
+
+	req := httptest.NewRequest("GET",
+		"/api/rent/export?format=csv&hotel="+hotelID.String()+"&from=2024-12-01T00:00:00Z&mode=starts_in", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="rents.csv"`, rec.Header().Get("Content-Disposition"))
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, []string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date",
+		"check_out_date", "status", "payment_status", "night_price", "nights", "currency", "total_amount", "adults",
+		"children", "primary_guest_name", "created_at", "version"}, records[0])
+	assert.Equal(t, rents[0].ID.String(), records[1][0])
+	assert.Equal(t, "2024-12-20T14:00:00Z", records[1][5])
+	assert.Equal(t, []string{"100000", "2", services.DefaultCurrency, "200000"}, records[1][9:13])
+	assert.Equal(t, `'=HYPERLINK("http://example.com")`, records[1][15])
+	assert.Equal(t, []string{"", "", "", ""}, records[2][9:13])
+	mockService.AssertExpectations(t)
+}
+
+func TestExportRents_NDJSON_OneRentPerLine(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+	rents := newExportedRents(hotelID)
+	mockService.On("ExportRents", requests.RentFilter{}, testCaller).Return(rents, nil)
+
+	req := httptest.NewRequest("GET", "/api/rent/export?format=ndjson", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.Len(t, lines, 2)
+	assert.Equal(t, rents[0].ID.String(), lines[0]["id"])
+	assert.Equal(t, float64(2000_00), lines[0]["total_amount"])
+	assert.Nil(t, lines[1]["total_amount"])
+	mockService.AssertExpectations(t)
+}
+
+func TestExportRents_NoRents_OnlyHeader(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("ExportRents", requests.RentFilter{}, testCaller).Return([]responses.ExportedRentResponse{}, nil)
+
+	req := httptest.NewRequest("GET", "/api/rent/export", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestExportRents_InvalidFormat_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("GET", "/api/rent/export?format=xlsx", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "ExportRents")
+}
+
+func TestExportRents_Guest_Forbidden(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("ExportRents", requests.RentFilter{}, testCaller).
+		Return([]responses.ExportedRentResponse{}, errors2.NewServiceForbiddenError("Access denied", "only hotel owners may export rents"))
+
+	req := httptest.NewRequest("GET", "/api/rent/export", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
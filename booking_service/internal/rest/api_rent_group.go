@@ -0,0 +1,115 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// maxGroupLines limits how many rooms one group reservation may book
+const maxGroupLines = 20
+
+// CreateGroupRentHandler books several rooms of a hotel under one reservation, all of them or none
+func CreateGroupRentHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the group rent creation handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		var req requests.CreateGroupRentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if len(req.Lines) == 0 || len(req.Lines) > maxGroupLines {
+			http.Error(w, "A group must have from 1 to "+strconv.Itoa(maxGroupLines)+" rooms", http.StatusBadRequest)
+			slog.Error("A group must have from 1 to " + strconv.Itoa(maxGroupLines) + " rooms" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		for _, line := range req.Lines {
+			if !line.CheckOutDate.After(line.CheckInDate) {
+				http.Error(w, "Check-out date must be after check-in date", http.StatusBadRequest)
+				slog.Error("Check-out date must be after check-in date" + strconv.Itoa(http.StatusBadRequest))
+				return
+			}
+		}
+
+		group, err := service.CreateGroupRent(req, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else {
+				http.Error(w, "Failed to create group rent", http.StatusInternalServerError)
+				slog.Error("Failed to create group rent" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(group); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The group rent was successfully created")
+		slog.Info("Group ID: " + group.ID.String())
+	}
+}
+
+func GetGroupRentHandler(service services.IBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the group rent getting handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		groupID, err := uuid.Parse(vars["group_id"])
+		if err != nil {
+			http.Error(w, "Invalid group ID", http.StatusBadRequest)
+			slog.Error("Invalid group ID" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		group, err := service.GetGroupRent(groupID, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceForbiddenError)) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusForbidden))
+			} else {
+				http.Error(w, "Failed to fetch group rent", http.StatusInternalServerError)
+				slog.Error("Failed to fetch group rent" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		if group == nil {
+			http.Error(w, "Group rent not found", http.StatusNotFound)
+			slog.Error("Group rent not found" + strconv.Itoa(http.StatusNotFound))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(group); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The group rent was successfully got")
+		slog.Info("Group ID: " + groupID.String())
+	}
+}
@@ -0,0 +1,100 @@
+package rest_test
+
+import (
+	errors2 "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateGroupRent_CommonCase_Created(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	reqBody := requests.CreateGroupRentRequest{
+		HotelID: uuid.New(),
+		Lines: []requests.GroupRentLine{
+			{CheckInDate: checkInDate, CheckOutDate: checkInDate.Add(48 * time.Hour)},
+			{CheckInDate: checkInDate, CheckOutDate: checkInDate.Add(24 * time.Hour)},
+		},
+	}
+	group := &responses.GetRentGroupResponse{ID: uuid.New(), HotelID: reqBody.HotelID, TotalAmount: 3000_00}
+	mockService.On("CreateGroupRent", reqBody, testCaller).Return(group, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/group", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resBody responses.GetRentGroupResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, group.ID, resBody.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateGroupRent_NoLines_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	body, _ := json.Marshal(requests.CreateGroupRentRequest{HotelID: uuid.New()})
+	req := httptest.NewRequest("POST", "/api/rent/group", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "CreateGroupRent")
+}
+
+func TestCreateGroupRent_NoRoomsForLine_BadRequest(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	checkInDate := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	reqBody := requests.CreateGroupRentRequest{
+		HotelID: uuid.New(),
+		Lines:   []requests.GroupRentLine{{CheckInDate: checkInDate, CheckOutDate: checkInDate.Add(24 * time.Hour)}},
+	}
+	mockService.On("CreateGroupRent", reqBody, testCaller).
+		Return((*responses.GetRentGroupResponse)(nil), errors2.NewServiceBadRequestError("No rooms available", "line 1"))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/group", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetGroupRent_NotFound(t *testing.T) {
+	mockService := new(MockBookingService)
+	router := setupTestRouter(mockService)
+
+	groupID := uuid.New()
+	mockService.On("GetGroupRent", groupID, testCaller).Return((*responses.GetRentGroupResponse)(nil), nil)
+
+	req := httptest.NewRequest("GET", "/api/rent/group/"+groupID.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
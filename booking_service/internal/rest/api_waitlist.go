@@ -0,0 +1,66 @@
+package rest
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// JoinWaitlistHandler registers the interest of the guest in a sold-out stay, a hold is offered
+// to the guest when a room becomes free
+func JoinWaitlistHandler(service services.IWaitlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("Calling the waitlist joining handler")
+		caller, ok := getCaller(w, r)
+		if !ok {
+			return
+		}
+
+		var req requests.JoinWaitlistRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			slog.Error("Invalid request body" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if !req.CheckOutDate.After(req.CheckInDate) {
+			http.Error(w, "Check-out date must be after check-in date", http.StatusBadRequest)
+			slog.Error("Check-out date must be after check-in date" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		if !req.CheckInDate.After(time.Now()) {
+			http.Error(w, "Check-in date must be in the future", http.StatusBadRequest)
+			slog.Error("Check-in date must be in the future" + strconv.Itoa(http.StatusBadRequest))
+			return
+		}
+
+		entry, err := service.JoinWaitlist(req, caller)
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				slog.Error(err.Error() + strconv.Itoa(http.StatusBadRequest))
+			} else {
+				http.Error(w, "Failed to join waitlist", http.StatusInternalServerError)
+				slog.Error("Failed to join waitlist" + strconv.Itoa(http.StatusInternalServerError))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(entry); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			slog.Error("Failed to encode response" + strconv.Itoa(http.StatusInternalServerError))
+			return
+		}
+		slog.Info("The waitlist was successfully joined")
+		slog.Info("Waitlist entry ID: " + entry.ID.String())
+	}
+}
@@ -0,0 +1,106 @@
+package rest_test
+
+import (
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/server"
+	"booking_service/internal/service_interaction/user_service"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// region Mock Waitlist Service
+type MockWaitlistService struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistService) JoinWaitlist(req requests.JoinWaitlistRequest, caller *user_service.UserData) (*responses.WaitlistEntryResponse, error) {
+	args := m.Called(req, caller)
+	return args.Get(0).(*responses.WaitlistEntryResponse), args.Error(1)
+}
+
+func (m *MockWaitlistService) OfferFreedCapacity(hotelID uuid.UUID) (int, error) {
+	args := m.Called(hotelID)
+	return args.Int(0), args.Error(1)
+}
+
+func setupWaitlistTestRouter(service *MockWaitlistService) *mux.Router {
+	userBridgeMock := &MockUserServiceBridge{}
+	userBridgeMock.On("GetUserContactData", "token").Return(testCaller, nil).Maybe()
+	return setupApiTestRouter(&server.ApiServices{WaitlistService: service, UserServiceBridge: userBridgeMock})
+}
+
+// endregion
+
+// region Tests
+
+func TestJoinWaitlist_CommonCase_Created(t *testing.T) {
+	mockService := new(MockWaitlistService)
+	router := setupWaitlistTestRouter(mockService)
+
+	checkInDate := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	reqBody := requests.JoinWaitlistRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	}
+	entry := &responses.WaitlistEntryResponse{ID: uuid.New(), HotelID: reqBody.HotelID, Status: "waiting"}
+	mockService.On("JoinWaitlist", reqBody, testCaller).Return(entry, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/rent/waitlist", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resBody responses.WaitlistEntryResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, entry.ID, resBody.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestJoinWaitlist_PastStay_BadRequest(t *testing.T) {
+	mockService := new(MockWaitlistService)
+	router := setupWaitlistTestRouter(mockService)
+
+	checkInDate := time.Now().Add(-48 * time.Hour)
+	body, _ := json.Marshal(requests.JoinWaitlistRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  checkInDate,
+		CheckOutDate: checkInDate.Add(24 * time.Hour),
+	})
+	req := httptest.NewRequest("POST", "/api/rent/waitlist", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "JoinWaitlist")
+}
+
+func TestJoinWaitlist_Unauthenticated_Unauthorized(t *testing.T) {
+	mockService := new(MockWaitlistService)
+	router := setupWaitlistTestRouter(mockService)
+
+	req := httptest.NewRequest("POST", "/api/rent/waitlist", bytes.NewReader([]byte("{}")))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "JoinWaitlist")
+}
+
+// endregion
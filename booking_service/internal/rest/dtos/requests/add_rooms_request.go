@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/google/uuid"
+
+type AddRoomsRequest struct {
+	RoomTypeID  *uuid.UUID `json:"room_type_id,omitempty"`
+	RoomNumbers []string   `json:"room_numbers"`
+}
@@ -0,0 +1,5 @@
+package requests
+
+type CancelRentRequest struct {
+	Reason string `json:"reason"`
+}
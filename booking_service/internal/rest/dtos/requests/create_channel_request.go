@@ -0,0 +1,11 @@
+package requests
+
+import "github.com/google/uuid"
+
+type CreateChannelRequest struct {
+	Name string `json:"name"`
+	// URL is the iCal feed of the hotel on the channel, it is omitted if the owner uploads the calendar
+	URL string `json:"url,omitempty"`
+	// RoomTypeID limits the blocked rooms to one room type, any room of the hotel is blocked without it
+	RoomTypeID *uuid.UUID `json:"room_type_id,omitempty"`
+}
@@ -0,0 +1,24 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CreateGroupRentRequest books several rooms of the hotel under one reservation
+type CreateGroupRentRequest struct {
+	HotelID uuid.UUID       `json:"hotel_id"`
+	Lines   []GroupRentLine `json:"lines"`
+}
+
+// GroupRentLine is one room of the group, the rooms of a group may have different stays
+type GroupRentLine struct {
+	RoomTypeID   *uuid.UUID `json:"room_type_id,omitempty"`
+	CheckInDate  time.Time  `json:"check_in_date"`
+	CheckOutDate time.Time  `json:"check_out_date"`
+	// Adults defaults to one, the primary guest is counted among the adults
+	Adults           int      `json:"adults,omitempty"`
+	Children         int      `json:"children,omitempty"`
+	PrimaryGuestName string   `json:"primary_guest_name,omitempty"`
+	AdditionalGuests []string `json:"additional_guests,omitempty"`
+}
@@ -6,7 +6,17 @@ import (
 )
 
 type CreateRentRequest struct {
-	HotelID      uuid.UUID `json:"hotel_id"`
-	CheckInDate  time.Time `json:"check_in_date"`
-	CheckOutDate time.Time `json:"check_out_date"`
+	HotelID      uuid.UUID  `json:"hotel_id"`
+	RoomTypeID   *uuid.UUID `json:"room_type_id,omitempty"`
+	CheckInDate  time.Time  `json:"check_in_date"`
+	CheckOutDate time.Time  `json:"check_out_date"`
+	// Adults defaults to one, the primary guest is counted among the adults
+	Adults           int      `json:"adults,omitempty"`
+	Children         int      `json:"children,omitempty"`
+	PrimaryGuestName string   `json:"primary_guest_name,omitempty"`
+	AdditionalGuests []string `json:"additional_guests,omitempty"`
+	// PromoCode is applied to the price of the nights, it cannot be used with a hold
+	PromoCode string `json:"promo_code,omitempty"`
+	// HoldID converts the hold into the rent, the stay may then be omitted
+	HoldID *uuid.UUID `json:"hold_id,omitempty"`
 }
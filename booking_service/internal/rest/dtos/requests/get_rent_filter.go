@@ -8,6 +8,16 @@ import (
 type RentFilter struct {
 	ClientID uuid.UUID
 	HotelID  uuid.UUID
+	// HotelIDs limits the rents to several hotels, nil means any hotel
+	HotelIDs []uuid.UUID
 	FromDate *time.Time
 	ToDate   *time.Time
+	// DateMode tells how the stay of a rent is matched against the FromDate-ToDate window, overlaps by default
+	DateMode string
+	Status   string
+	// Limit is the page size, Cursor is the next_cursor of the previous page
+	Limit     int
+	Cursor    string
+	SortBy    string
+	SortOrder string
 }
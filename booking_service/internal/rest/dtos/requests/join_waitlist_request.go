@@ -0,0 +1,13 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type JoinWaitlistRequest struct {
+	HotelID      uuid.UUID  `json:"hotel_id"`
+	RoomTypeID   *uuid.UUID `json:"room_type_id,omitempty"`
+	CheckInDate  time.Time  `json:"check_in_date"`
+	CheckOutDate time.Time  `json:"check_out_date"`
+}
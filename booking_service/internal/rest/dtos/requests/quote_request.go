@@ -0,0 +1,22 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type QuoteRequest struct {
+	HotelID      uuid.UUID
+	RoomTypeID   *uuid.UUID
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+	// Discount is taken off the price of the nights before taxes are calculated
+	Discount *QuoteDiscount
+}
+
+// QuoteDiscount is the discount of a promo code which was checked to be valid for the stay
+type QuoteDiscount struct {
+	PromoCode string
+	Type      string
+	Value     int
+}
@@ -0,0 +1,6 @@
+package requests
+
+type SetCancellationPolicyRequest struct {
+	FreeCancellationHours int `json:"free_cancellation_hours"`
+	PenaltyNights         int `json:"penalty_nights"`
+}
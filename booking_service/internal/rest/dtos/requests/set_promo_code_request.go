@@ -0,0 +1,19 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type SetPromoCodeRequest struct {
+	Code string `json:"code"`
+	// HotelID is omitted for a global code, it cannot be changed once the code is created
+	HotelID       *uuid.UUID `json:"hotel_id,omitempty"`
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue int        `json:"discount_value"`
+	ValidFrom     *time.Time `json:"valid_from,omitempty"`
+	ValidUntil    *time.Time `json:"valid_until,omitempty"`
+	// MaxUses is omitted for a code which may be used any number of times
+	MaxUses   *int `json:"max_uses,omitempty"`
+	MinNights int  `json:"min_nights,omitempty"`
+}
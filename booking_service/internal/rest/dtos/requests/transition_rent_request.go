@@ -0,0 +1,6 @@
+package requests
+
+type TransitionRentRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
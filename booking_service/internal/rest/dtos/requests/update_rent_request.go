@@ -7,7 +7,8 @@ import (
 
 type UpdateRentRequest struct {
 	HotelID      uuid.UUID `json:"hotel_id,omitempty"`
-	ClientID     uuid.UUID `json:"client_id,omitempty"`
 	CheckInDate  time.Time `json:"check_in_date,omitempty"`
 	CheckOutDate time.Time `json:"check_out_date,omitempty"`
+	// ConfirmedPriceDifference is the extra amount the guest agreed to pay, it is required if the change raises the price
+	ConfirmedPriceDifference *int `json:"confirmed_price_difference,omitempty"`
 }
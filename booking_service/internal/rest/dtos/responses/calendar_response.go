@@ -0,0 +1,26 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type FeedTokenResponse struct {
+	Token string `json:"token"`
+}
+
+type CalendarEventResponse struct {
+	RentID      uuid.UUID
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Status      string
+	Version     int
+	CreatedAt   time.Time
+}
+
+type CalendarFeedResponse struct {
+	Name   string
+	Events []CalendarEventResponse
+}
@@ -0,0 +1,16 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CancelRentResponse struct {
+	RentID        uuid.UUID `json:"rent_id"`
+	CancelledAt   time.Time `json:"cancelled_at"`
+	CancelledBy   uuid.UUID `json:"cancelled_by"`
+	Reason        string    `json:"reason"`
+	TotalAmount   int       `json:"total_amount"`
+	PenaltyAmount int       `json:"penalty_amount"`
+	RefundAmount  int       `json:"refund_amount"`
+}
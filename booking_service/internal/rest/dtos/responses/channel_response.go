@@ -0,0 +1,18 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ChannelResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	HotelID      uuid.UUID  `json:"hotel_id"`
+	RoomTypeID   *uuid.UUID `json:"room_type_id,omitempty"`
+	Name         string     `json:"name"`
+	URL          string     `json:"url,omitempty"`
+	Uploaded     bool       `json:"uploaded"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
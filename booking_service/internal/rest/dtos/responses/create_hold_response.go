@@ -0,0 +1,13 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateHoldResponse struct {
+	HoldID      uuid.UUID `json:"hold_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	TotalAmount int       `json:"total_amount"`
+	Currency    string    `json:"currency"`
+}
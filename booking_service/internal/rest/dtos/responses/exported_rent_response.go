@@ -0,0 +1,29 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ExportedRentResponse is a rent as it is stored. The price fields are empty for the rents
+// booked before prices were stored with them, an export does not price them at the current prices
+type ExportedRentResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	HotelID          uuid.UUID  `json:"hotel_id"`
+	RoomID           uuid.UUID  `json:"room_id"`
+	RoomTypeID       *uuid.UUID `json:"room_type_id"`
+	ClientID         uuid.UUID  `json:"client_id"`
+	CheckInDate      time.Time  `json:"check_in_date"`
+	CheckOutDate     time.Time  `json:"check_out_date"`
+	Status           string     `json:"status"`
+	PaymentStatus    string     `json:"payment_status"`
+	NightPrice       *int       `json:"night_price"`
+	Nights           *int       `json:"nights"`
+	Currency         *string    `json:"currency"`
+	TotalAmount      *int       `json:"total_amount"`
+	Adults           int        `json:"adults"`
+	Children         int        `json:"children"`
+	PrimaryGuestName string     `json:"primary_guest_name"`
+	CreatedAt        time.Time  `json:"created_at"`
+	Version          int        `json:"version"`
+}
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/google/uuid"
+
+type GetCancellationPolicyResponse struct {
+	HotelID               uuid.UUID `json:"hotel_id"`
+	FreeCancellationHours int       `json:"free_cancellation_hours"`
+	PenaltyNights         int       `json:"penalty_nights"`
+}
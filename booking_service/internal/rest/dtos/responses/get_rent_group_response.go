@@ -0,0 +1,16 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type GetRentGroupResponse struct {
+	ID          uuid.UUID         `json:"id"`
+	HotelID     uuid.UUID         `json:"hotel_id"`
+	ClientID    uuid.UUID         `json:"client_id"`
+	Rents       []GetRentResponse `json:"rents"`
+	TotalAmount int               `json:"total_amount"`
+	Currency    string            `json:"currency"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
@@ -6,10 +6,23 @@ import (
 )
 
 type GetRentResponse struct {
-	ID           uuid.UUID `json:"id"`
-	HotelID      uuid.UUID `json:"hotel_id"`
-	ClientID     uuid.UUID `json:"client_id"`
-	NightPrice   int       `json:"night_price"`
-	CheckInDate  time.Time `json:"check_in_date"`
-	CheckOutDate time.Time `json:"check_out_date"`
+	ID               uuid.UUID  `json:"id"`
+	HotelID          uuid.UUID  `json:"hotel_id"`
+	RoomID           uuid.UUID  `json:"room_id"`
+	RoomTypeID       *uuid.UUID `json:"room_type_id,omitempty"`
+	ClientID         uuid.UUID  `json:"client_id"`
+	NightPrice       int        `json:"night_price"`
+	Nights           int        `json:"nights"`
+	Currency         string     `json:"currency"`
+	TotalAmount      int        `json:"total_amount"`
+	CheckInDate      time.Time  `json:"check_in_date"`
+	CheckOutDate     time.Time  `json:"check_out_date"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"created_at"`
+	Version          int        `json:"version"`
+	Adults           int        `json:"adults"`
+	Children         int        `json:"children"`
+	PrimaryGuestName string     `json:"primary_guest_name,omitempty"`
+	AdditionalGuests []string   `json:"additional_guests,omitempty"`
+	PaymentStatus    string     `json:"payment_status"`
 }
@@ -2,4 +2,6 @@ package responses
 
 type GetRentsResponse struct {
 	Rents []GetRentResponse `json:"rents"`
+	// NextCursor points to the next page, it is null on the last page
+	NextCursor *string `json:"next_cursor"`
 }
@@ -0,0 +1,10 @@
+package responses
+
+import "github.com/google/uuid"
+
+type GetRoomResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	HotelID    uuid.UUID  `json:"hotel_id"`
+	RoomTypeID *uuid.UUID `json:"room_type_id,omitempty"`
+	RoomNumber string     `json:"room_number"`
+}
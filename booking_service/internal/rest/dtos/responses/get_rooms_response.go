@@ -0,0 +1,5 @@
+package responses
+
+type GetRoomsResponse struct {
+	Rooms []GetRoomResponse `json:"rooms"`
+}
@@ -0,0 +1,34 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type InvoiceLineResponse struct {
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int    `json:"unit_price"`
+	Amount      int    `json:"amount"`
+}
+
+type InvoiceResponse struct {
+	ID                uuid.UUID             `json:"id"`
+	Number            string                `json:"number"`
+	Kind              string                `json:"kind"`
+	CreditedInvoiceID *uuid.UUID            `json:"credited_invoice_id,omitempty"`
+	CreditedNumber    string                `json:"credited_number,omitempty"`
+	RentID            uuid.UUID             `json:"rent_id"`
+	IssuedAt          time.Time             `json:"issued_at"`
+	HotelID           uuid.UUID             `json:"hotel_id"`
+	HotelName         string                `json:"hotel_name"`
+	GuestID           uuid.UUID             `json:"guest_id"`
+	GuestName         string                `json:"guest_name,omitempty"`
+	GuestEmail        string                `json:"guest_email,omitempty"`
+	CheckInDate       time.Time             `json:"check_in_date"`
+	CheckOutDate      time.Time             `json:"check_out_date"`
+	Lines             []InvoiceLineResponse `json:"lines"`
+	Taxes             int                   `json:"taxes"`
+	Total             int                   `json:"total"`
+	Currency          string                `json:"currency"`
+}
@@ -0,0 +1,12 @@
+package responses
+
+import "github.com/google/uuid"
+
+type PaymentResponse struct {
+	RentID        uuid.UUID `json:"rent_id"`
+	PaymentID     string    `json:"payment_id,omitempty"`
+	PaymentStatus string    `json:"payment_status"`
+	RentStatus    string    `json:"rent_status"`
+	Amount        int       `json:"amount"`
+	Currency      string    `json:"currency"`
+}
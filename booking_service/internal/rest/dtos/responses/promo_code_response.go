@@ -0,0 +1,20 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PromoCodeResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Code          string     `json:"code"`
+	HotelID       *uuid.UUID `json:"hotel_id,omitempty"`
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue int        `json:"discount_value"`
+	ValidFrom     *time.Time `json:"valid_from,omitempty"`
+	ValidUntil    *time.Time `json:"valid_until,omitempty"`
+	MaxUses       *int       `json:"max_uses,omitempty"`
+	UsedCount     int        `json:"used_count"`
+	MinNights     int        `json:"min_nights"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type QuoteNightResponse struct {
+	Date  string `json:"date"`
+	Price int    `json:"price"`
+}
+
+type QuoteResponse struct {
+	HotelID      uuid.UUID            `json:"hotel_id"`
+	RoomTypeID   *uuid.UUID           `json:"room_type_id,omitempty"`
+	CheckInDate  time.Time            `json:"check_in_date"`
+	CheckOutDate time.Time            `json:"check_out_date"`
+	NightPrice   int                  `json:"night_price"`
+	Nights       int                  `json:"nights"`
+	Breakdown    []QuoteNightResponse `json:"breakdown"`
+	Subtotal     int                  `json:"subtotal"`
+	PromoCode    string               `json:"promo_code,omitempty"`
+	Discount     int                  `json:"discount,omitempty"`
+	Taxes        int                  `json:"taxes"`
+	Fees         int                  `json:"fees"`
+	Total        int                  `json:"total"`
+	Currency     string               `json:"currency"`
+}
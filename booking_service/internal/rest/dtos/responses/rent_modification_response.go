@@ -0,0 +1,25 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RentStayResponse is the stay of a rent before or after it was modified
+type RentStayResponse struct {
+	HotelID      uuid.UUID `json:"hotel_id"`
+	CheckInDate  time.Time `json:"check_in_date"`
+	CheckOutDate time.Time `json:"check_out_date"`
+	Nights       int       `json:"nights"`
+	TotalAmount  int       `json:"total_amount"`
+}
+
+type RentModificationResponse struct {
+	RentID   uuid.UUID        `json:"rent_id"`
+	Before   RentStayResponse `json:"before"`
+	After    RentStayResponse `json:"after"`
+	Currency string           `json:"currency"`
+	// PriceDifference is what the guest pays extra, it is negative if the stay became cheaper
+	PriceDifference int `json:"price_difference"`
+	Version         int `json:"version"`
+}
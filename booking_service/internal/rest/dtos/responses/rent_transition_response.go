@@ -0,0 +1,16 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RentTransitionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	RentID     uuid.UUID `json:"rent_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  uuid.UUID `json:"changed_by"`
+	Reason     string    `json:"reason"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
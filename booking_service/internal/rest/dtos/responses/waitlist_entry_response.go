@@ -0,0 +1,23 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type WaitlistEntryResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	HotelID      uuid.UUID  `json:"hotel_id"`
+	RoomTypeID   *uuid.UUID `json:"room_type_id,omitempty"`
+	CheckInDate  time.Time  `json:"check_in_date"`
+	CheckOutDate time.Time  `json:"check_out_date"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// WaitlistOfferResponse is the hold offered to a waitlisted guest when a room became free
+type WaitlistOfferResponse struct {
+	EntryID   uuid.UUID `json:"entry_id"`
+	HoldID    uuid.UUID `json:"hold_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
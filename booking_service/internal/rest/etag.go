@@ -0,0 +1,32 @@
+package rest
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// formatETag makes the strong entity tag of a resource version
+func formatETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// getIfMatchVersion reads the version the client has seen from the If-Match header, writing 428 if the
+// header is missing and 412 if it cannot match any version
+func getIfMatchVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+		slog.Error("If-Match header is required" + strconv.Itoa(http.StatusPreconditionRequired))
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.Trim(ifMatch, `"`))
+	if err != nil || !strings.HasPrefix(ifMatch, `"`) || !strings.HasSuffix(ifMatch, `"`) {
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+		slog.Error("Precondition failed" + strconv.Itoa(http.StatusPreconditionFailed))
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/services"
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": formatMoney,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Invoice.Number}}</title>
+</head>
+<body>
+<h1>{{.Title}} {{.Invoice.Number}}</h1>
+<p>Issued on {{.Invoice.IssuedAt.Format "2006-01-02"}}</p>
+{{if .Invoice.CreditedNumber}}<p>Cancels invoice {{.Invoice.CreditedNumber}}</p>{{end}}
+<h2>{{.Invoice.HotelName}}</h2>
+<p>Hotel {{.Invoice.HotelID}}</p>
+<h2>Billed to</h2>
+<p>{{if .Invoice.GuestName}}{{.Invoice.GuestName}}<br>{{end}}{{if .Invoice.GuestEmail}}{{.Invoice.GuestEmail}}<br>{{end}}Guest {{.Invoice.GuestID}}</p>
+<p>Rent {{.Invoice.RentID}}, {{.Invoice.CheckInDate.Format "2006-01-02"}} - {{.Invoice.CheckOutDate.Format "2006-01-02"}}</p>
+<table>
+<tr><th>Description</th><th>Quantity</th><th>Unit price</th><th>Amount</th></tr>
+{{range .Invoice.Lines}}<tr><td>{{.Description}}</td><td>{{.Quantity}}</td><td>{{money .UnitPrice}}</td><td>{{money .Amount}}</td></tr>
+{{end}}<tr><th colspan="3">Total, {{.Invoice.Currency}}</th><th>{{money .Invoice.Total}}</th></tr>
+</table>
+</body>
+</html>
+`))
+
+// writeInvoiceHTML renders the invoice as an HTML page
+func writeInvoiceHTML(w io.Writer, invoice *responses.InvoiceResponse) error {
+	return invoiceTemplate.Execute(w, struct {
+		Title   string
+		Invoice *responses.InvoiceResponse
+	}{Title: invoiceTitle(invoice), Invoice: invoice})
+}
+
+// writeInvoicePDF renders the invoice as a one page PDF document. The text is set in the standard
+// Helvetica font, which needs no embedding but covers only Latin-1, other characters are replaced
+func writeInvoicePDF(w io.Writer, invoice *responses.InvoiceResponse) error {
+	text := []string{
+		invoiceTitle(invoice) + " " + invoice.Number,
+		"Issued on " + invoice.IssuedAt.Format("2006-01-02"),
+	}
+	if invoice.CreditedNumber != "" {
+		text = append(text, "Cancels invoice "+invoice.CreditedNumber)
+	}
+	text = append(text, "", invoice.HotelName, "Hotel "+invoice.HotelID.String(), "", "Billed to")
+	if invoice.GuestName != "" {
+		text = append(text, invoice.GuestName)
+	}
+	if invoice.GuestEmail != "" {
+		text = append(text, invoice.GuestEmail)
+	}
+	text = append(text, "Guest "+invoice.GuestID.String(),
+		fmt.Sprintf("Rent %s, %s - %s", invoice.RentID,
+			invoice.CheckInDate.Format("2006-01-02"), invoice.CheckOutDate.Format("2006-01-02")), "")
+	for _, line := range invoice.Lines {
+		text = append(text, fmt.Sprintf("%s: %d x %s = %s",
+			line.Description, line.Quantity, formatMoney(line.UnitPrice), formatMoney(line.Amount)))
+	}
+	text = append(text, "", fmt.Sprintf("Total: %s %s", formatMoney(invoice.Total), invoice.Currency))
+
+	var content bytes.Buffer
+	content.WriteString("BT\n/F1 11 Tf\n14 TL\n50 790 Td\n")
+	for _, line := range text {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDFText(line))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var document bytes.Buffer
+	document.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = document.Len()
+		fmt.Fprintf(&document, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := document.Len()
+	fmt.Fprintf(&document, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&document, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&document, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(document.Bytes())
+	return err
+}
+
+func invoiceTitle(invoice *responses.InvoiceResponse) string {
+	if invoice.Kind == services.InvoiceKindCreditNote {
+		return "Credit note"
+	}
+	return "Invoice"
+}
+
+// formatMoney prints an amount in minor units, e.g. 1234_56 as 1234.56
+func formatMoney(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// escapePDFText escapes a string literal of a PDF content stream and encodes it in Latin-1
+func escapePDFText(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r < 0x20 || r > 0xff:
+			escaped.WriteByte('?')
+		default:
+			escaped.WriteByte(byte(r))
+		}
+	}
+	return escaped.String()
+}
@@ -6,9 +6,11 @@ import (
 	"booking_service/internal/metrics"
 	"booking_service/internal/service_interaction/hotel_service"
 	"booking_service/internal/service_interaction/notification_service"
+	"booking_service/internal/service_interaction/payment_provider"
 	"booking_service/internal/service_interaction/user_service"
 	"booking_service/internal/services"
 	"booking_service/internal/tracing"
+	"fmt"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"log/slog"
 	"os"
@@ -16,7 +18,11 @@ import (
 
 type CommonConfiguration struct {
 	ServerConfig   *config.ServerConfig
-	BookingService services.IBookingService
+	ApiServices    *ApiServices
+	OutboxRelay    *services.OutboxRelay
+	HoldSweeper    *services.HoldSweeper
+	RefundRelay    *services.RefundRelay
+	ChannelSyncer  *services.ChannelSyncer
 	TracerProvider *trace.TracerProvider
 }
 
@@ -70,17 +76,90 @@ func NewCommonConfiguration() (*CommonConfiguration, error) {
 		notificationServiceKafkaTopic)
 	slog.Info("Connection to kafka broker with notification service established")
 
+	// setup payment provider, the secret signs the webhooks of the provider
+	paymentProvider, err := newPaymentProvider(&cfg.Payments, os.Getenv("payment_webhook_secret"))
+	if err != nil {
+		slog.Info("Failed to set up payment provider")
+		return nil, err
+	}
+	slog.Info("Payment provider " + cfg.Payments.Provider + " set up")
+
 	// setup metrics
 	metrics.Register()
 	slog.Info("Metrics registered")
 
-	bookingService := services.NewBookingService(db, hotelServiceBridge, userServiceBridge, notificationServiceBridge)
+	cancellationPolicyService := services.NewCancellationPolicyService(db, hotelServiceBridge)
+	slog.Info("Cancellation policy service taken up")
+
+	pricingService := services.NewPricingService(hotelServiceBridge, &cfg.Pricing)
+	slog.Info("Pricing service taken up")
+
+	promotionService := services.NewPromotionService(db, hotelServiceBridge, &cfg.Promotions)
+	slog.Info("Promotion service taken up")
+
+	paymentService := services.NewPaymentService(db, hotelServiceBridge, userServiceBridge, paymentProvider)
+	slog.Info("Payment service taken up")
+
+	invoiceService := services.NewInvoiceService(db, hotelServiceBridge, userServiceBridge)
+	slog.Info("Invoice service taken up")
+
+	calendarService := services.NewCalendarService(db, hotelServiceBridge)
+	slog.Info("Calendar service taken up")
+
+	waitlistService := services.NewWaitlistService(db, hotelServiceBridge, pricingService, &cfg.Holds)
+	slog.Info("Waitlist service taken up")
+
+	bookingService := services.NewBookingService(db, hotelServiceBridge,
+		cancellationPolicyService, pricingService, &cfg.Idempotency, &cfg.Holds, waitlistService)
 	slog.Info("Booking service taken up")
 
+	inventoryService := services.NewInventoryService(db, hotelServiceBridge, waitlistService)
+	slog.Info("Inventory service taken up")
+
+	outboxRelay := services.NewOutboxRelay(db, notificationServiceBridge, &cfg.Outbox)
+	slog.Info("Outbox relay taken up")
+
+	holdSweeper := services.NewHoldSweeper(db, &cfg.Holds, waitlistService)
+	slog.Info("Hold sweeper taken up")
+
+	refundRelay := services.NewRefundRelay(db, paymentProvider, &cfg.Payments)
+	slog.Info("Refund relay taken up")
+
+	channelService := services.NewChannelService(db, hotelServiceBridge, waitlistService)
+	slog.Info("Channel service taken up")
+
+	channelSyncer := services.NewChannelSyncer(db, &cfg.Channels, waitlistService)
+	slog.Info("Channel syncer taken up")
+
 	slog.Info("Common configuration was successfully created")
 	return &CommonConfiguration{
-		ServerConfig:   cfg,
-		BookingService: bookingService,
+		ServerConfig: cfg,
+		ApiServices: &ApiServices{
+			BookingService:            bookingService,
+			InventoryService:          inventoryService,
+			WaitlistService:           waitlistService,
+			CancellationPolicyService: cancellationPolicyService,
+			PricingService:            pricingService,
+			PromotionService:          promotionService,
+			PaymentService:            paymentService,
+			InvoiceService:            invoiceService,
+			CalendarService:           calendarService,
+			ChannelService:            channelService,
+			UserServiceBridge:         userServiceBridge,
+		},
+		OutboxRelay:    outboxRelay,
+		HoldSweeper:    holdSweeper,
+		RefundRelay:    refundRelay,
+		ChannelSyncer:  channelSyncer,
 		TracerProvider: tracerProvider,
 	}, nil
 }
+
+func newPaymentProvider(cfg *config.PaymentConfig, webhookSecret string) (payment_provider.IPaymentProvider, error) {
+	switch cfg.Provider {
+	case "fake":
+		return payment_provider.NewFakeProvider(cfg.FakeOutcome, webhookSecret)
+	default:
+		return nil, fmt.Errorf("unknown payment provider: %q", cfg.Provider)
+	}
+}
@@ -2,7 +2,6 @@ package server
 
 import (
 	"booking_service/internal/config"
-	"booking_service/internal/services"
 	"context"
 	"errors"
 	"fmt"
@@ -14,8 +13,8 @@ import (
 	"time"
 )
 
-func NewServer(cfg *config.ServerConfig, bookingService services.IBookingService) {
-	router := SetupApiRouter(cfg, bookingService)
+func NewServer(cfg *config.ServerConfig, apiServices *ApiServices) {
+	router := SetupApiRouter(cfg, apiServices)
 
 	// Server configuration
 	srv := &http.Server{
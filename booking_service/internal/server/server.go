@@ -1,16 +1,33 @@
 package server
 
 import (
+	"booking_service/internal/auth"
 	"booking_service/internal/config"
 	"booking_service/internal/metrics"
 	"booking_service/internal/rest"
+	"booking_service/internal/service_interaction/user_service"
 	"booking_service/internal/services"
 	"booking_service/internal/tracing"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func SetupApiRouter(cfg *config.ServerConfig, bookingService services.IBookingService) *mux.Router {
+// ApiServices holds the services which handle requests of the REST API
+type ApiServices struct {
+	BookingService            services.IBookingService
+	InventoryService          services.IInventoryService
+	WaitlistService           services.IWaitlistService
+	CancellationPolicyService services.ICancellationPolicyService
+	PricingService            services.IPricingService
+	PromotionService          services.IPromotionService
+	PaymentService            services.IPaymentService
+	InvoiceService            services.IInvoiceService
+	CalendarService           services.ICalendarService
+	ChannelService            services.IChannelService
+	UserServiceBridge         user_service.IUserServiceBridge
+}
+
+func SetupApiRouter(cfg *config.ServerConfig, apiServices *ApiServices) *mux.Router {
 	router := mux.NewRouter()
 
 	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
@@ -19,10 +36,80 @@ func SetupApiRouter(cfg *config.ServerConfig, bookingService services.IBookingSe
 	apiRouter.Use(metrics.MetricsMiddleware)
 	apiRouter.Use(tracing.TracingMiddleware)
 
-	apiRouter.HandleFunc("/rent", rest.CreateRentHandler(bookingService)).Methods("POST")
-	apiRouter.HandleFunc("/rent/{rent_id}", rest.UpdateRentHandler(bookingService)).Methods("PUT")
-	apiRouter.HandleFunc("/rent", rest.GetRentsHandler(bookingService)).Methods("GET")
-	apiRouter.HandleFunc("/rent/{rent_id}", rest.GetRentByIDHandler(bookingService)).Methods("GET")
+	// Rents are accessible only to their clients and to the owners of their hotels
+	authenticated := auth.AuthMiddleware(apiServices.UserServiceBridge)
+	bookingService := apiServices.BookingService
+	apiRouter.Handle("/rent", authenticated(rest.CreateRentHandler(bookingService))).Methods("POST")
+	apiRouter.Handle("/rent/hold", authenticated(rest.CreateHoldHandler(bookingService))).Methods("POST")
+	apiRouter.Handle("/rent/group", authenticated(rest.CreateGroupRentHandler(bookingService))).Methods("POST")
+	apiRouter.Handle("/rent/group/{group_id}", authenticated(rest.GetGroupRentHandler(bookingService))).Methods("GET")
+	apiRouter.Handle("/rent/waitlist",
+		authenticated(rest.JoinWaitlistHandler(apiServices.WaitlistService))).Methods("POST")
+	apiRouter.Handle("/rent/{rent_id}", authenticated(rest.UpdateRentHandler(bookingService))).Methods("PUT")
+	apiRouter.Handle("/rent/{rent_id}", authenticated(rest.PatchRentHandler(bookingService))).Methods("PATCH")
+	apiRouter.Handle("/rent", authenticated(rest.GetRentsHandler(bookingService))).Methods("GET")
+	// Registered before /rent/{rent_id} so that "quote" is not taken for a rent ID
+	apiRouter.HandleFunc("/rent/quote", rest.QuoteHandler(apiServices.PricingService)).Methods("GET")
+	// Calendar clients authenticate with the feed token in the URL, registered before /rent/{rent_id} as well
+	calendarService := apiServices.CalendarService
+	apiRouter.HandleFunc("/rent/mine.ics", rest.GetGuestFeedHandler(calendarService)).Methods("GET")
+	// Owners export the rents of their hotels, the route is registered before /rent/{rent_id} too
+	apiRouter.Handle("/rent/export", authenticated(rest.ExportRentsHandler(bookingService))).Methods("GET")
+	apiRouter.Handle("/rent/{rent_id}", authenticated(rest.GetRentByIDHandler(bookingService))).Methods("GET")
+	apiRouter.Handle("/rent/{rent_id}/cancel", authenticated(rest.CancelRentHandler(bookingService))).Methods("POST")
+	apiRouter.Handle("/rent/{rent_id}/transitions",
+		authenticated(rest.TransitionRentHandler(bookingService))).Methods("POST")
+
+	// Guests pay for their rents, owners capture and refund the payments. The provider reports the outcomes
+	// of payments with signed webhooks, so the webhook is not authenticated by a token
+	paymentService := apiServices.PaymentService
+	apiRouter.Handle("/rent/{rent_id}/payment",
+		authenticated(rest.AuthorizePaymentHandler(paymentService))).Methods("POST")
+	apiRouter.Handle("/rent/{rent_id}/payment/capture",
+		authenticated(rest.CapturePaymentHandler(paymentService))).Methods("POST")
+	apiRouter.Handle("/rent/{rent_id}/payment/refund",
+		authenticated(rest.RefundPaymentHandler(paymentService))).Methods("POST")
+	apiRouter.HandleFunc("/payments/webhook", rest.PaymentWebhookHandler(paymentService)).Methods("POST")
+
+	// Invoices are rendered for the guest and the owner, only the owner corrects them with credit notes
+	invoiceService := apiServices.InvoiceService
+	apiRouter.Handle("/rent/{rent_id}/invoice", authenticated(rest.GetInvoiceHandler(invoiceService))).Methods("GET")
+	apiRouter.Handle("/rent/{rent_id}/invoice/credit-note",
+		authenticated(rest.IssueCreditNoteHandler(invoiceService))).Methods("POST")
+
+	apiRouter.HandleFunc("/hotel/{hotel_id}/bookings.ics", rest.GetHotelFeedHandler(calendarService)).Methods("GET")
+	apiRouter.Handle("/calendar/feed-token", authenticated(rest.CreateFeedTokenHandler(calendarService))).Methods("POST")
+
+	// The owner registers the calendars of the hotel on other channels, their bookings block the rooms here
+	channelService := apiServices.ChannelService
+	apiRouter.Handle("/hotel/{hotel_id}/channels",
+		authenticated(rest.CreateChannelHandler(channelService))).Methods("POST")
+	apiRouter.Handle("/hotel/{hotel_id}/channels", authenticated(rest.GetChannelsHandler(channelService))).Methods("GET")
+	apiRouter.Handle("/hotel/{hotel_id}/channels/{channel_id}/calendar",
+		authenticated(rest.UploadChannelCalendarHandler(channelService))).Methods("PUT")
+	apiRouter.Handle("/hotel/{hotel_id}/channels/{channel_id}",
+		authenticated(rest.DeleteChannelHandler(channelService))).Methods("DELETE")
+
+	inventoryService := apiServices.InventoryService
+	apiRouter.Handle("/hotel/{hotel_id}/inventory", authenticated(rest.AddRoomsHandler(inventoryService))).Methods("POST")
+	apiRouter.HandleFunc("/hotel/{hotel_id}/inventory", rest.GetRoomsHandler(inventoryService)).Methods("GET")
+
+	cancellationPolicyService := apiServices.CancellationPolicyService
+	apiRouter.Handle("/hotel/{hotel_id}/cancellation-policy",
+		authenticated(rest.SetCancellationPolicyHandler(cancellationPolicyService))).Methods("PUT")
+	apiRouter.HandleFunc("/hotel/{hotel_id}/cancellation-policy",
+		rest.GetCancellationPolicyHandler(cancellationPolicyService)).Methods("GET")
+
+	// Promo codes of a hotel are managed by its owner, global promo codes by the administrators
+	promotionService := apiServices.PromotionService
+	apiRouter.Handle("/promo-codes", authenticated(rest.CreatePromoCodeHandler(promotionService))).Methods("POST")
+	apiRouter.Handle("/promo-codes", authenticated(rest.GetPromoCodesHandler(promotionService))).Methods("GET")
+	apiRouter.Handle("/promo-codes/{promo_code_id}",
+		authenticated(rest.GetPromoCodeHandler(promotionService))).Methods("GET")
+	apiRouter.Handle("/promo-codes/{promo_code_id}",
+		authenticated(rest.UpdatePromoCodeHandler(promotionService))).Methods("PUT")
+	apiRouter.Handle("/promo-codes/{promo_code_id}",
+		authenticated(rest.DeletePromoCodeHandler(promotionService))).Methods("DELETE")
 
 	return router
 }
@@ -9,8 +9,13 @@ import (
 
 func TestSetupApiRouter(t *testing.T) {
 	serverConfig := &config.ServerConfig{}
-	bookingService := &services.BookingService{}
-	router := SetupApiRouter(serverConfig, bookingService)
+	apiServices := &ApiServices{
+		BookingService:            &services.BookingService{},
+		InventoryService:          &services.InventoryService{},
+		CancellationPolicyService: &services.CancellationPolicyService{},
+		PricingService:            &services.PricingService{},
+	}
+	router := SetupApiRouter(serverConfig, apiServices)
 
 	assert.NotNil(t, router)
 }
@@ -111,6 +111,635 @@ func (x *GetHotelPriceResponse) GetPrice() int32 {
 	return 0
 }
 
+type GetHotelPricesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HotelId []string `protobuf:"bytes,1,rep,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+}
+
+func (x *GetHotelPricesRequest) Reset() {
+	*x = GetHotelPricesRequest{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelPricesRequest) ProtoMessage() {}
+
+func (x *GetHotelPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelPricesRequest.ProtoReflect.Descriptor instead.
+func (*GetHotelPricesRequest) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetHotelPricesRequest) GetHotelId() []string {
+	if x != nil {
+		return x.HotelId
+	}
+	return nil
+}
+
+type HotelPrice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HotelId string `protobuf:"bytes,1,opt,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+	Price   int32  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+}
+
+func (x *HotelPrice) Reset() {
+	*x = HotelPrice{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotelPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotelPrice) ProtoMessage() {}
+
+func (x *HotelPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotelPrice.ProtoReflect.Descriptor instead.
+func (*HotelPrice) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HotelPrice) GetHotelId() string {
+	if x != nil {
+		return x.HotelId
+	}
+	return ""
+}
+
+func (x *HotelPrice) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+// Hotels which do not exist are missing from the response
+type GetHotelPricesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prices []*HotelPrice `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+}
+
+func (x *GetHotelPricesResponse) Reset() {
+	*x = GetHotelPricesResponse{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelPricesResponse) ProtoMessage() {}
+
+func (x *GetHotelPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelPricesResponse.ProtoReflect.Descriptor instead.
+func (*GetHotelPricesResponse) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetHotelPricesResponse) GetPrices() []*HotelPrice {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+type GetHotelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HotelId string `protobuf:"bytes,1,opt,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+}
+
+func (x *GetHotelRequest) Reset() {
+	*x = GetHotelRequest{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelRequest) ProtoMessage() {}
+
+func (x *GetHotelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelRequest.ProtoReflect.Descriptor instead.
+func (*GetHotelRequest) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetHotelRequest) GetHotelId() string {
+	if x != nil {
+		return x.HotelId
+	}
+	return ""
+}
+
+type GetHotelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	HotelName       string `protobuf:"bytes,2,opt,name=hotel_name,json=hotelName,proto3" json:"hotel_name,omitempty"`
+	NightPrice      int32  `protobuf:"varint,3,opt,name=night_price,json=nightPrice,proto3" json:"night_price,omitempty"`
+	AdministratorId string `protobuf:"bytes,4,opt,name=administrator_id,json=administratorId,proto3" json:"administrator_id,omitempty"`
+}
+
+func (x *GetHotelResponse) Reset() {
+	*x = GetHotelResponse{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelResponse) ProtoMessage() {}
+
+func (x *GetHotelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelResponse.ProtoReflect.Descriptor instead.
+func (*GetHotelResponse) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetHotelResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetHotelResponse) GetHotelName() string {
+	if x != nil {
+		return x.HotelName
+	}
+	return ""
+}
+
+func (x *GetHotelResponse) GetNightPrice() int32 {
+	if x != nil {
+		return x.NightPrice
+	}
+	return 0
+}
+
+func (x *GetHotelResponse) GetAdministratorId() string {
+	if x != nil {
+		return x.AdministratorId
+	}
+	return ""
+}
+
+type GetHotelsByAdministratorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AdministratorId string `protobuf:"bytes,1,opt,name=administrator_id,json=administratorId,proto3" json:"administrator_id,omitempty"`
+}
+
+func (x *GetHotelsByAdministratorRequest) Reset() {
+	*x = GetHotelsByAdministratorRequest{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelsByAdministratorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelsByAdministratorRequest) ProtoMessage() {}
+
+func (x *GetHotelsByAdministratorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelsByAdministratorRequest.ProtoReflect.Descriptor instead.
+func (*GetHotelsByAdministratorRequest) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetHotelsByAdministratorRequest) GetAdministratorId() string {
+	if x != nil {
+		return x.AdministratorId
+	}
+	return ""
+}
+
+type GetHotelsByAdministratorResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HotelId []string `protobuf:"bytes,1,rep,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+}
+
+func (x *GetHotelsByAdministratorResponse) Reset() {
+	*x = GetHotelsByAdministratorResponse{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHotelsByAdministratorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHotelsByAdministratorResponse) ProtoMessage() {}
+
+func (x *GetHotelsByAdministratorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHotelsByAdministratorResponse.ProtoReflect.Descriptor instead.
+func (*GetHotelsByAdministratorResponse) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetHotelsByAdministratorResponse) GetHotelId() []string {
+	if x != nil {
+		return x.HotelId
+	}
+	return nil
+}
+
+type RoomType struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	HotelId          string `protobuf:"bytes,2,opt,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+	Name             string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Capacity         int32  `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	BedConfiguration string `protobuf:"bytes,5,opt,name=bed_configuration,json=bedConfiguration,proto3" json:"bed_configuration,omitempty"`
+	RoomsCount       int32  `protobuf:"varint,6,opt,name=rooms_count,json=roomsCount,proto3" json:"rooms_count,omitempty"`
+	NightPrice       int32  `protobuf:"varint,7,opt,name=night_price,json=nightPrice,proto3" json:"night_price,omitempty"`
+}
+
+func (x *RoomType) Reset() {
+	*x = RoomType{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomType) ProtoMessage() {}
+
+func (x *RoomType) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomType.ProtoReflect.Descriptor instead.
+func (*RoomType) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RoomType) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RoomType) GetHotelId() string {
+	if x != nil {
+		return x.HotelId
+	}
+	return ""
+}
+
+func (x *RoomType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomType) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *RoomType) GetBedConfiguration() string {
+	if x != nil {
+		return x.BedConfiguration
+	}
+	return ""
+}
+
+func (x *RoomType) GetRoomsCount() int32 {
+	if x != nil {
+		return x.RoomsCount
+	}
+	return 0
+}
+
+func (x *RoomType) GetNightPrice() int32 {
+	if x != nil {
+		return x.NightPrice
+	}
+	return 0
+}
+
+type GetRoomTypesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HotelId string `protobuf:"bytes,1,opt,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+}
+
+func (x *GetRoomTypesRequest) Reset() {
+	*x = GetRoomTypesRequest{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomTypesRequest) ProtoMessage() {}
+
+func (x *GetRoomTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomTypesRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomTypesRequest) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetRoomTypesRequest) GetHotelId() string {
+	if x != nil {
+		return x.HotelId
+	}
+	return ""
+}
+
+type GetRoomTypesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomTypes []*RoomType `protobuf:"bytes,1,rep,name=room_types,json=roomTypes,proto3" json:"room_types,omitempty"`
+}
+
+func (x *GetRoomTypesResponse) Reset() {
+	*x = GetRoomTypesResponse{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomTypesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomTypesResponse) ProtoMessage() {}
+
+func (x *GetRoomTypesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomTypesResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomTypesResponse) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetRoomTypesResponse) GetRoomTypes() []*RoomType {
+	if x != nil {
+		return x.RoomTypes
+	}
+	return nil
+}
+
+type GetRoomTypeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HotelId    string `protobuf:"bytes,1,opt,name=hotel_id,json=hotelId,proto3" json:"hotel_id,omitempty"`
+	RoomTypeId string `protobuf:"bytes,2,opt,name=room_type_id,json=roomTypeId,proto3" json:"room_type_id,omitempty"`
+}
+
+func (x *GetRoomTypeRequest) Reset() {
+	*x = GetRoomTypeRequest{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomTypeRequest) ProtoMessage() {}
+
+func (x *GetRoomTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomTypeRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomTypeRequest) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetRoomTypeRequest) GetHotelId() string {
+	if x != nil {
+		return x.HotelId
+	}
+	return ""
+}
+
+func (x *GetRoomTypeRequest) GetRoomTypeId() string {
+	if x != nil {
+		return x.RoomTypeId
+	}
+	return ""
+}
+
+type GetRoomTypeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomType *RoomType `protobuf:"bytes,1,opt,name=room_type,json=roomType,proto3" json:"room_type,omitempty"`
+}
+
+func (x *GetRoomTypeResponse) Reset() {
+	*x = GetRoomTypeResponse{}
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomTypeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomTypeResponse) ProtoMessage() {}
+
+func (x *GetRoomTypeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomTypeResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomTypeResponse) Descriptor() ([]byte, []int) {
+	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetRoomTypeResponse) GetRoomType() *RoomType {
+	if x != nil {
+		return x.RoomType
+	}
+	return nil
+}
+
 var File_booking_service_internal_service_interaction_hotel_service_proto protoreflect.FileDescriptor
 
 var file_booking_service_internal_service_interaction_hotel_service_proto_rawDesc = []byte{
@@ -127,19 +756,118 @@ var file_booking_service_internal_service_interaction_hotel_service_proto_rawDes
 	0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x2d, 0x0a, 0x15, 0x47, 0x65,
 	0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
 	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x32, 0x76, 0x0a, 0x0c, 0x48, 0x6f, 0x74,
-	0x65, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x66, 0x0a, 0x0d, 0x47, 0x65, 0x74,
-	0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x29, 0x2e, 0x73, 0x65, 0x72,
+	0x28, 0x05, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x22, 0x32, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x3d, 0x0a,
+	0x0a, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x68,
+	0x6f, 0x74, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68,
+	0x6f, 0x74, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x22, 0x51, 0x0a, 0x16,
+	0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x70, 0x72, 0x69, 0x63, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x48, 0x6f, 0x74,
+	0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x06, 0x70, 0x72, 0x69, 0x63, 0x65, 0x73, 0x22,
+	0x2c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x8d, 0x01,
+	0x0a, 0x10, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x22, 0x4c, 0x0a,
+	0x1f, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x73, 0x42, 0x79, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x29, 0x0a, 0x10, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x22, 0x3d, 0x0a, 0x20, 0x47,
+	0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x73, 0x42, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x69,
+	0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x19, 0x0a, 0x08, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x49, 0x64, 0x22, 0xd4, 0x01, 0x0a, 0x08, 0x52,
+	0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x74, 0x65, 0x6c,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65, 0x6c,
+	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x12, 0x2b, 0x0a, 0x11, 0x62, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x62,
+	0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x69, 0x63,
+	0x65, 0x22, 0x30, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x74, 0x65,
+	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65,
+	0x6c, 0x49, 0x64, 0x22, 0x54, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79,
+	0x70, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0a, 0x72,
+	0x6f, 0x6f, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09,
+	0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x12, 0x47, 0x65, 0x74,
+	0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x68, 0x6f, 0x74, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x72, 0x6f,
+	0x6f, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x49, 0x64, 0x22, 0x51, 0x0a, 0x13,
+	0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x52, 0x6f, 0x6f,
+	0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x32,
+	0x8b, 0x05, 0x0a, 0x0c, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x66, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63,
+	0x65, 0x12, 0x29, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x48,
+	0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x12, 0x2a, 0x2e, 0x73, 0x65, 0x72,
 	0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
+	0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74,
+	0x48, 0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x12,
+	0x24, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
 	0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x48,
-	0x6f, 0x74, 0x65, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x42, 0x5a, 0x40, 0x62, 0x6f, 0x6f, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x65,
+	0x6f, 0x74, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x87, 0x01, 0x0a,
+	0x18, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x73, 0x42, 0x79, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x34, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x73, 0x42, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x35, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x74, 0x65, 0x6c, 0x73, 0x42,
+	0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f,
+	0x6d, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x28, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74,
+	0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x29, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79,
+	0x70, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x0b, 0x47,
+	0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x27, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f,
+	0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x42, 0x5a,
+	0x40, 0x62, 0x6f, 0x6f, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x3b, 0x73, 0x65,
 	0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x3b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -154,19 +882,44 @@ func file_booking_service_internal_service_interaction_hotel_service_proto_rawDe
 	return file_booking_service_internal_service_interaction_hotel_service_proto_rawDescData
 }
 
-var file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_booking_service_internal_service_interaction_hotel_service_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_booking_service_internal_service_interaction_hotel_service_proto_goTypes = []any{
-	(*GetHotelPriceRequest)(nil),  // 0: service_interaction.GetHotelPriceRequest
-	(*GetHotelPriceResponse)(nil), // 1: service_interaction.GetHotelPriceResponse
+	(*GetHotelPriceRequest)(nil),             // 0: service_interaction.GetHotelPriceRequest
+	(*GetHotelPriceResponse)(nil),            // 1: service_interaction.GetHotelPriceResponse
+	(*GetHotelPricesRequest)(nil),            // 2: service_interaction.GetHotelPricesRequest
+	(*HotelPrice)(nil),                       // 3: service_interaction.HotelPrice
+	(*GetHotelPricesResponse)(nil),           // 4: service_interaction.GetHotelPricesResponse
+	(*GetHotelRequest)(nil),                  // 5: service_interaction.GetHotelRequest
+	(*GetHotelResponse)(nil),                 // 6: service_interaction.GetHotelResponse
+	(*GetHotelsByAdministratorRequest)(nil),  // 7: service_interaction.GetHotelsByAdministratorRequest
+	(*GetHotelsByAdministratorResponse)(nil), // 8: service_interaction.GetHotelsByAdministratorResponse
+	(*RoomType)(nil),                         // 9: service_interaction.RoomType
+	(*GetRoomTypesRequest)(nil),              // 10: service_interaction.GetRoomTypesRequest
+	(*GetRoomTypesResponse)(nil),             // 11: service_interaction.GetRoomTypesResponse
+	(*GetRoomTypeRequest)(nil),               // 12: service_interaction.GetRoomTypeRequest
+	(*GetRoomTypeResponse)(nil),              // 13: service_interaction.GetRoomTypeResponse
 }
 var file_booking_service_internal_service_interaction_hotel_service_proto_depIdxs = []int32{
-	0, // 0: service_interaction.HotelService.GetHotelPrice:input_type -> service_interaction.GetHotelPriceRequest
-	1, // 1: service_interaction.HotelService.GetHotelPrice:output_type -> service_interaction.GetHotelPriceResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3,  // 0: service_interaction.GetHotelPricesResponse.prices:type_name -> service_interaction.HotelPrice
+	9,  // 1: service_interaction.GetRoomTypesResponse.room_types:type_name -> service_interaction.RoomType
+	9,  // 2: service_interaction.GetRoomTypeResponse.room_type:type_name -> service_interaction.RoomType
+	0,  // 3: service_interaction.HotelService.GetHotelPrice:input_type -> service_interaction.GetHotelPriceRequest
+	2,  // 4: service_interaction.HotelService.GetHotelPrices:input_type -> service_interaction.GetHotelPricesRequest
+	5,  // 5: service_interaction.HotelService.GetHotel:input_type -> service_interaction.GetHotelRequest
+	7,  // 6: service_interaction.HotelService.GetHotelsByAdministrator:input_type -> service_interaction.GetHotelsByAdministratorRequest
+	10, // 7: service_interaction.HotelService.GetRoomTypes:input_type -> service_interaction.GetRoomTypesRequest
+	12, // 8: service_interaction.HotelService.GetRoomType:input_type -> service_interaction.GetRoomTypeRequest
+	1,  // 9: service_interaction.HotelService.GetHotelPrice:output_type -> service_interaction.GetHotelPriceResponse
+	4,  // 10: service_interaction.HotelService.GetHotelPrices:output_type -> service_interaction.GetHotelPricesResponse
+	6,  // 11: service_interaction.HotelService.GetHotel:output_type -> service_interaction.GetHotelResponse
+	8,  // 12: service_interaction.HotelService.GetHotelsByAdministrator:output_type -> service_interaction.GetHotelsByAdministratorResponse
+	11, // 13: service_interaction.HotelService.GetRoomTypes:output_type -> service_interaction.GetRoomTypesResponse
+	13, // 14: service_interaction.HotelService.GetRoomType:output_type -> service_interaction.GetRoomTypeResponse
+	9,  // [9:15] is the sub-list for method output_type
+	3,  // [3:9] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_booking_service_internal_service_interaction_hotel_service_proto_init() }
@@ -180,7 +933,7 @@ func file_booking_service_internal_service_interaction_hotel_service_proto_init(
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_booking_service_internal_service_interaction_hotel_service_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	HotelService_GetHotelPrice_FullMethodName = "/service_interaction.HotelService/GetHotelPrice"
+	HotelService_GetHotelPrice_FullMethodName            = "/service_interaction.HotelService/GetHotelPrice"
+	HotelService_GetHotelPrices_FullMethodName           = "/service_interaction.HotelService/GetHotelPrices"
+	HotelService_GetHotel_FullMethodName                 = "/service_interaction.HotelService/GetHotel"
+	HotelService_GetHotelsByAdministrator_FullMethodName = "/service_interaction.HotelService/GetHotelsByAdministrator"
+	HotelService_GetRoomTypes_FullMethodName             = "/service_interaction.HotelService/GetRoomTypes"
+	HotelService_GetRoomType_FullMethodName              = "/service_interaction.HotelService/GetRoomType"
 )
 
 // HotelServiceClient is the client API for HotelService service.
@@ -27,6 +32,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type HotelServiceClient interface {
 	GetHotelPrice(ctx context.Context, in *GetHotelPriceRequest, opts ...grpc.CallOption) (*GetHotelPriceResponse, error)
+	GetHotelPrices(ctx context.Context, in *GetHotelPricesRequest, opts ...grpc.CallOption) (*GetHotelPricesResponse, error)
+	GetHotel(ctx context.Context, in *GetHotelRequest, opts ...grpc.CallOption) (*GetHotelResponse, error)
+	GetHotelsByAdministrator(ctx context.Context, in *GetHotelsByAdministratorRequest, opts ...grpc.CallOption) (*GetHotelsByAdministratorResponse, error)
+	GetRoomTypes(ctx context.Context, in *GetRoomTypesRequest, opts ...grpc.CallOption) (*GetRoomTypesResponse, error)
+	GetRoomType(ctx context.Context, in *GetRoomTypeRequest, opts ...grpc.CallOption) (*GetRoomTypeResponse, error)
 }
 
 type hotelServiceClient struct {
@@ -47,11 +57,66 @@ func (c *hotelServiceClient) GetHotelPrice(ctx context.Context, in *GetHotelPric
 	return out, nil
 }
 
+func (c *hotelServiceClient) GetHotelPrices(ctx context.Context, in *GetHotelPricesRequest, opts ...grpc.CallOption) (*GetHotelPricesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHotelPricesResponse)
+	err := c.cc.Invoke(ctx, HotelService_GetHotelPrices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hotelServiceClient) GetHotel(ctx context.Context, in *GetHotelRequest, opts ...grpc.CallOption) (*GetHotelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHotelResponse)
+	err := c.cc.Invoke(ctx, HotelService_GetHotel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hotelServiceClient) GetHotelsByAdministrator(ctx context.Context, in *GetHotelsByAdministratorRequest, opts ...grpc.CallOption) (*GetHotelsByAdministratorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHotelsByAdministratorResponse)
+	err := c.cc.Invoke(ctx, HotelService_GetHotelsByAdministrator_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hotelServiceClient) GetRoomTypes(ctx context.Context, in *GetRoomTypesRequest, opts ...grpc.CallOption) (*GetRoomTypesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomTypesResponse)
+	err := c.cc.Invoke(ctx, HotelService_GetRoomTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hotelServiceClient) GetRoomType(ctx context.Context, in *GetRoomTypeRequest, opts ...grpc.CallOption) (*GetRoomTypeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomTypeResponse)
+	err := c.cc.Invoke(ctx, HotelService_GetRoomType_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HotelServiceServer is the server API for HotelService service.
 // All implementations must embed UnimplementedHotelServiceServer
 // for forward compatibility.
 type HotelServiceServer interface {
 	GetHotelPrice(context.Context, *GetHotelPriceRequest) (*GetHotelPriceResponse, error)
+	GetHotelPrices(context.Context, *GetHotelPricesRequest) (*GetHotelPricesResponse, error)
+	GetHotel(context.Context, *GetHotelRequest) (*GetHotelResponse, error)
+	GetHotelsByAdministrator(context.Context, *GetHotelsByAdministratorRequest) (*GetHotelsByAdministratorResponse, error)
+	GetRoomTypes(context.Context, *GetRoomTypesRequest) (*GetRoomTypesResponse, error)
+	GetRoomType(context.Context, *GetRoomTypeRequest) (*GetRoomTypeResponse, error)
 	mustEmbedUnimplementedHotelServiceServer()
 }
 
@@ -65,6 +130,21 @@ type UnimplementedHotelServiceServer struct{}
 func (UnimplementedHotelServiceServer) GetHotelPrice(context.Context, *GetHotelPriceRequest) (*GetHotelPriceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetHotelPrice not implemented")
 }
+func (UnimplementedHotelServiceServer) GetHotelPrices(context.Context, *GetHotelPricesRequest) (*GetHotelPricesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHotelPrices not implemented")
+}
+func (UnimplementedHotelServiceServer) GetHotel(context.Context, *GetHotelRequest) (*GetHotelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHotel not implemented")
+}
+func (UnimplementedHotelServiceServer) GetHotelsByAdministrator(context.Context, *GetHotelsByAdministratorRequest) (*GetHotelsByAdministratorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHotelsByAdministrator not implemented")
+}
+func (UnimplementedHotelServiceServer) GetRoomTypes(context.Context, *GetRoomTypesRequest) (*GetRoomTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomTypes not implemented")
+}
+func (UnimplementedHotelServiceServer) GetRoomType(context.Context, *GetRoomTypeRequest) (*GetRoomTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomType not implemented")
+}
 func (UnimplementedHotelServiceServer) mustEmbedUnimplementedHotelServiceServer() {}
 func (UnimplementedHotelServiceServer) testEmbeddedByValue()                      {}
 
@@ -104,6 +184,96 @@ func _HotelService_GetHotelPrice_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HotelService_GetHotelPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHotelPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HotelServiceServer).GetHotelPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HotelService_GetHotelPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HotelServiceServer).GetHotelPrices(ctx, req.(*GetHotelPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HotelService_GetHotel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHotelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HotelServiceServer).GetHotel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HotelService_GetHotel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HotelServiceServer).GetHotel(ctx, req.(*GetHotelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HotelService_GetHotelsByAdministrator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHotelsByAdministratorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HotelServiceServer).GetHotelsByAdministrator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HotelService_GetHotelsByAdministrator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HotelServiceServer).GetHotelsByAdministrator(ctx, req.(*GetHotelsByAdministratorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HotelService_GetRoomTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HotelServiceServer).GetRoomTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HotelService_GetRoomTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HotelServiceServer).GetRoomTypes(ctx, req.(*GetRoomTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HotelService_GetRoomType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HotelServiceServer).GetRoomType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HotelService_GetRoomType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HotelServiceServer).GetRoomType(ctx, req.(*GetRoomTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HotelService_ServiceDesc is the grpc.ServiceDesc for HotelService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +285,26 @@ var HotelService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetHotelPrice",
 			Handler:    _HotelService_GetHotelPrice_Handler,
 		},
+		{
+			MethodName: "GetHotelPrices",
+			Handler:    _HotelService_GetHotelPrices_Handler,
+		},
+		{
+			MethodName: "GetHotel",
+			Handler:    _HotelService_GetHotel_Handler,
+		},
+		{
+			MethodName: "GetHotelsByAdministrator",
+			Handler:    _HotelService_GetHotelsByAdministrator_Handler,
+		},
+		{
+			MethodName: "GetRoomTypes",
+			Handler:    _HotelService_GetRoomTypes_Handler,
+		},
+		{
+			MethodName: "GetRoomType",
+			Handler:    _HotelService_GetRoomType_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "booking_service/internal/service_interaction/hotel_service.proto",
@@ -3,16 +3,40 @@ package hotel_service
 import (
 	gen2 "booking_service/internal/service_interaction/hotel_service/gen"
 	"context"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
 )
 
+type RoomTypeData struct {
+	ID               uuid.UUID `json:"id"`
+	HotelID          uuid.UUID `json:"hotel_id"`
+	Name             string    `json:"name"`
+	Capacity         int       `json:"capacity"`
+	BedConfiguration string    `json:"bed_configuration"`
+	RoomsCount       int       `json:"rooms_count"`
+	NightPrice       int       `json:"night_price"`
+}
+
+type HotelData struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	NightPrice      int       `json:"night_price"`
+	AdministratorID uuid.UUID `json:"administrator_id"`
+}
+
 type IHotelServiceBridge interface {
 	GetHotelPrice(hotelId uuid.UUID) (int, error)
+	GetHotelPrices(hotelIds []uuid.UUID) (map[uuid.UUID]int, error)
+	GetRoomType(hotelId uuid.UUID, roomTypeId uuid.UUID) (*RoomTypeData, error)
+	GetHotel(hotelId uuid.UUID) (*HotelData, error)
+	GetHotelsByAdministrator(administratorId uuid.UUID) ([]uuid.UUID, error)
 }
 
 type HotelServiceBridge struct {
@@ -42,3 +66,120 @@ func (h *HotelServiceBridge) GetHotelPrice(hotelId uuid.UUID) (int, error) {
 	}
 	return int(response.Price), nil
 }
+
+// GetHotelPrices returns the prices of several hotels in one call, hotels which do not exist are missing from the map
+func (h *HotelServiceBridge) GetHotelPrices(hotelIds []uuid.UUID) (map[uuid.UUID]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+	defer cancel()
+
+	request := &gen2.GetHotelPricesRequest{HotelId: make([]string, 0, len(hotelIds))}
+	for _, hotelId := range hotelIds {
+		request.HotelId = append(request.HotelId, hotelId.String())
+	}
+	slog.Info("Sending request to get prices of " + strconv.Itoa(len(hotelIds)) + " hotels")
+	response, err := h.GrpcClient.GetHotelPrices(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[uuid.UUID]int, len(response.Prices))
+	for _, hotelPrice := range response.Prices {
+		hotelId, err := uuid.Parse(hotelPrice.HotelId)
+		if err != nil {
+			return nil, err
+		}
+		prices[hotelId] = int(hotelPrice.Price)
+	}
+	return prices, nil
+}
+
+// GetHotel returns nil without error if there is no hotel with the given id
+func (h *HotelServiceBridge) GetHotel(hotelId uuid.UUID) (*HotelData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+	defer cancel()
+
+	request := &gen2.GetHotelRequest{HotelId: hotelId.String()}
+	slog.Info("Sending request to get hotel with id " + hotelId.String())
+	response, err := h.GrpcClient.GetHotel(ctx, request)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	id, err := uuid.Parse(response.Id)
+	if err != nil {
+		return nil, err
+	}
+	administratorID, err := uuid.Parse(response.AdministratorId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HotelData{
+		ID:              id,
+		Name:            response.HotelName,
+		NightPrice:      int(response.NightPrice),
+		AdministratorID: administratorID,
+	}, nil
+}
+
+// GetHotelsByAdministrator returns the ids of the hotels the user administers
+func (h *HotelServiceBridge) GetHotelsByAdministrator(administratorId uuid.UUID) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+	defer cancel()
+
+	request := &gen2.GetHotelsByAdministratorRequest{AdministratorId: administratorId.String()}
+	slog.Info("Sending request to get hotels of administrator with id " + administratorId.String())
+	response, err := h.GrpcClient.GetHotelsByAdministrator(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hotelIds := make([]uuid.UUID, 0, len(response.HotelId))
+	for _, hotelId := range response.HotelId {
+		id, err := uuid.Parse(hotelId)
+		if err != nil {
+			return nil, err
+		}
+		hotelIds = append(hotelIds, id)
+	}
+	return hotelIds, nil
+}
+
+// GetRoomType returns nil without error if the hotel has no room type with the given id
+func (h *HotelServiceBridge) GetRoomType(hotelId uuid.UUID, roomTypeId uuid.UUID) (*RoomTypeData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+	defer cancel()
+
+	request := &gen2.GetRoomTypeRequest{HotelId: hotelId.String(), RoomTypeId: roomTypeId.String()}
+	slog.Info("Sending request to get room type with id " + roomTypeId.String())
+	response, err := h.GrpcClient.GetRoomType(ctx, request)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	roomType := response.RoomType
+	id, err := uuid.Parse(roomType.Id)
+	if err != nil {
+		return nil, err
+	}
+	hotelID, err := uuid.Parse(roomType.HotelId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoomTypeData{
+		ID:               id,
+		HotelID:          hotelID,
+		Name:             roomType.Name,
+		Capacity:         int(roomType.Capacity),
+		BedConfiguration: roomType.BedConfiguration,
+		RoomsCount:       int(roomType.RoomsCount),
+		NightPrice:       int(roomType.NightPrice),
+	}, nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"testing"
 )
 
@@ -21,6 +23,31 @@ func (m *MockHotelServiceClient) GetHotelPrice(ctx context.Context, in *gen.GetH
 	return args.Get(0).(*gen.GetHotelPriceResponse), args.Error(1)
 }
 
+func (m *MockHotelServiceClient) GetHotelPrices(ctx context.Context, in *gen.GetHotelPricesRequest, opts ...grpc.CallOption) (*gen.GetHotelPricesResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*gen.GetHotelPricesResponse), args.Error(1)
+}
+
+func (m *MockHotelServiceClient) GetHotel(ctx context.Context, in *gen.GetHotelRequest, opts ...grpc.CallOption) (*gen.GetHotelResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*gen.GetHotelResponse), args.Error(1)
+}
+
+func (m *MockHotelServiceClient) GetHotelsByAdministrator(ctx context.Context, in *gen.GetHotelsByAdministratorRequest, opts ...grpc.CallOption) (*gen.GetHotelsByAdministratorResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*gen.GetHotelsByAdministratorResponse), args.Error(1)
+}
+
+func (m *MockHotelServiceClient) GetRoomTypes(ctx context.Context, in *gen.GetRoomTypesRequest, opts ...grpc.CallOption) (*gen.GetRoomTypesResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*gen.GetRoomTypesResponse), args.Error(1)
+}
+
+func (m *MockHotelServiceClient) GetRoomType(ctx context.Context, in *gen.GetRoomTypeRequest, opts ...grpc.CallOption) (*gen.GetRoomTypeResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*gen.GetRoomTypeResponse), args.Error(1)
+}
+
 func TestNewHotelServiceBridge(t *testing.T) {
 	bridge, err := hotel_service.NewHotelServiceBridge("address")
 
@@ -80,3 +107,136 @@ func TestHotelServiceBridge_GetHotelPrice_Timeout(t *testing.T) {
 	assert.Equal(t, 0, price)
 	mockClient.AssertExpectations(t)
 }
+
+func TestHotelServiceBridge_GetRoomType(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	hotelId := uuid.New()
+	roomTypeId := uuid.New()
+
+	mockClient.On("GetRoomType", mock.Anything, &gen.GetRoomTypeRequest{HotelId: hotelId.String(), RoomTypeId: roomTypeId.String()}).
+		Return(&gen.GetRoomTypeResponse{RoomType: &gen.RoomType{
+			Id:               roomTypeId.String(),
+			HotelId:          hotelId.String(),
+			Name:             "Deluxe",
+			Capacity:         2,
+			BedConfiguration: "1 king",
+			RoomsCount:       5,
+			NightPrice:       15000,
+		}}, nil)
+
+	roomType, err := hotelBridge.GetRoomType(hotelId, roomTypeId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &hotel_service.RoomTypeData{
+		ID:               roomTypeId,
+		HotelID:          hotelId,
+		Name:             "Deluxe",
+		Capacity:         2,
+		BedConfiguration: "1 king",
+		RoomsCount:       5,
+		NightPrice:       15000,
+	}, roomType)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHotelServiceBridge_GetRoomType_NotFound(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	hotelId := uuid.New()
+	roomTypeId := uuid.New()
+
+	mockClient.On("GetRoomType", mock.Anything, &gen.GetRoomTypeRequest{HotelId: hotelId.String(), RoomTypeId: roomTypeId.String()}).
+		Return((*gen.GetRoomTypeResponse)(nil), status.Error(codes.NotFound, "room type not found"))
+
+	roomType, err := hotelBridge.GetRoomType(hotelId, roomTypeId)
+
+	assert.NoError(t, err)
+	assert.Nil(t, roomType)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHotelServiceBridge_GetHotelPrices(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	firstHotelId := uuid.New()
+	secondHotelId := uuid.New()
+
+	mockClient.On("GetHotelPrices", mock.Anything, &gen.GetHotelPricesRequest{HotelId: []string{firstHotelId.String(), secondHotelId.String()}}).
+		Return(&gen.GetHotelPricesResponse{Prices: []*gen.HotelPrice{{HotelId: firstHotelId.String(), Price: 100}}}, nil)
+
+	prices, err := hotelBridge.GetHotelPrices([]uuid.UUID{firstHotelId, secondHotelId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{firstHotelId: 100}, prices)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHotelServiceBridge_GetHotelPrices_Error(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	mockClient.On("GetHotelPrices", mock.Anything, mock.Anything).
+		Return((*gen.GetHotelPricesResponse)(nil), errors.New("failed to get prices"))
+
+	prices, err := hotelBridge.GetHotelPrices([]uuid.UUID{uuid.New()})
+
+	assert.Error(t, err)
+	assert.Nil(t, prices)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHotelServiceBridge_GetHotel(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	hotelId := uuid.New()
+	administratorId := uuid.New()
+
+	mockClient.On("GetHotel", mock.Anything, &gen.GetHotelRequest{HotelId: hotelId.String()}).Return(&gen.GetHotelResponse{
+		Id:              hotelId.String(),
+		HotelName:       "Hotel",
+		NightPrice:      100,
+		AdministratorId: administratorId.String(),
+	}, nil)
+
+	hotel, err := hotelBridge.GetHotel(hotelId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &hotel_service.HotelData{ID: hotelId, Name: "Hotel", NightPrice: 100, AdministratorID: administratorId}, hotel)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHotelServiceBridge_GetHotel_NotFound(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	mockClient.On("GetHotel", mock.Anything, mock.Anything).
+		Return((*gen.GetHotelResponse)(nil), status.Error(codes.NotFound, "hotel not found"))
+
+	hotel, err := hotelBridge.GetHotel(uuid.New())
+
+	assert.NoError(t, err)
+	assert.Nil(t, hotel)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHotelServiceBridge_GetHotelsByAdministrator(t *testing.T) {
+	mockClient := new(MockHotelServiceClient)
+	hotelBridge := &hotel_service.HotelServiceBridge{GrpcClient: mockClient}
+
+	administratorId := uuid.New()
+	hotelId := uuid.New()
+
+	mockClient.On("GetHotelsByAdministrator", mock.Anything, &gen.GetHotelsByAdministratorRequest{AdministratorId: administratorId.String()}).
+		Return(&gen.GetHotelsByAdministratorResponse{HotelId: []string{hotelId.String()}}, nil)
+
+	hotelIds, err := hotelBridge.GetHotelsByAdministrator(administratorId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{hotelId}, hotelIds)
+	mockClient.AssertExpectations(t)
+}
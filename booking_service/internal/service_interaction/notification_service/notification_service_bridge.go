@@ -4,7 +4,6 @@ import (
 	"booking_service/internal/rest/dtos/responses"
 	"booking_service/internal/service_interaction/user_service"
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
@@ -14,13 +13,30 @@ import (
 	"log/slog"
 )
 
+// Event types which tell notification_service what happened to the rent
+const (
+	EventBookingCreated       = "booking_created"
+	EventBookingCancelled     = "booking_cancelled"
+	EventBookingStatusChanged = "booking_status_changed"
+	EventWaitlistOffer        = "waitlist_offer"
+	EventGroupBookingCreated  = "group_booking_created"
+	EventBookingModified      = "booking_modified"
+)
+
 type NotificationData struct {
-	UserContactData *user_service.UserData     `json:"user_contact_data"`
-	RentData        *responses.GetRentResponse `json:"rent_data"`
+	EventType       string                              `json:"event_type"`
+	UserContactData *user_service.UserData              `json:"user_contact_data"`
+	RentData        *responses.GetRentResponse          `json:"rent_data"`
+	Cancellation    *responses.CancelRentResponse       `json:"cancellation,omitempty"`
+	Transition      *responses.RentTransitionResponse   `json:"transition,omitempty"`
+	Offer           *responses.WaitlistOfferResponse    `json:"offer,omitempty"`
+	Group           *responses.GetRentGroupResponse     `json:"group,omitempty"`
+	Modification    *responses.RentModificationResponse `json:"modification,omitempty"`
 }
 
 type INotificationServiceBridge interface {
-	SendNotification(ctx context.Context, notificationData *NotificationData)
+	// SendMessage publishes serialized NotificationData to notification_service
+	SendMessage(ctx context.Context, message []byte) error
 }
 
 type NotificationServiceBridge struct {
@@ -38,38 +54,26 @@ func NewNotificationServiceBridge(broker string, topic string) *NotificationServ
 	return &NotificationServiceBridge{writer: writer, tracer: tracer}
 }
 
-func (b *NotificationServiceBridge) SendNotification(ctx context.Context, notificationData *NotificationData) {
-	ctx, span := b.tracer.Start(ctx, "SendNotification",
+func (b *NotificationServiceBridge) SendMessage(ctx context.Context, message []byte) error {
+	ctx, span := b.tracer.Start(ctx, "SendMessage",
 		trace.WithAttributes(
 			attribute.String("messaging.system", "kafka"),
 			attribute.String("messaging.destination", b.writer.Stats().Topic),
 			attribute.String("messaging.operation", "send"),
+			attribute.Int("messaging.message.size", len(message)),
 		),
 	)
 	defer span.End()
 
-	jsonData, err := json.Marshal(notificationData)
-	if err != nil {
-		slog.Error(fmt.Sprintf("Failed to serialize notification data: %v", err))
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "Failed to serialize notification data")
-		return
-	}
-
-	span.SetAttributes(attribute.Int("messaging.message.size", len(jsonData)))
-
-	message := kafka.Message{
-		Value: jsonData,
-	}
-
-	err = b.writer.WriteMessages(ctx, message)
+	err := b.writer.WriteMessages(ctx, kafka.Message{Value: message})
 	if err != nil {
 		slog.Error(fmt.Sprintf("Failed to send Kafka message: %v", err))
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to send Kafka message")
-		return
+		return err
 	}
 
 	slog.Info("Message sent successfully to Kafka")
 	span.SetStatus(codes.Ok, "Message sent successfully")
+	return nil
 }
@@ -0,0 +1,134 @@
+package payment_provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Outcomes the fake provider may be configured with
+const (
+	OutcomeSucceed = "succeed"
+	OutcomeDecline = "decline"
+	OutcomeTimeout = "timeout"
+)
+
+// FakeProvider is an in-process provider for local runs and tests. Every operation has the configured outcome
+// and payment IDs are derived from the reference, so the same flow always gives the same result.
+// On timeout the operation is still applied, as it happens with real providers, and Webhook reports it
+type FakeProvider struct {
+	outcome  string
+	secret   []byte
+	mu       sync.Mutex
+	payments map[string]*Payment
+}
+
+func NewFakeProvider(outcome string, webhookSecret string) (*FakeProvider, error) {
+	if outcome != OutcomeSucceed && outcome != OutcomeDecline && outcome != OutcomeTimeout {
+		return nil, fmt.Errorf("unknown outcome of the fake payment provider: %q", outcome)
+	}
+	return &FakeProvider{outcome: outcome, secret: []byte(webhookSecret), payments: map[string]*Payment{}}, nil
+}
+
+func (p *FakeProvider) Authorize(request AuthorizeRequest) (*Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	paymentID := "fake_" + request.Reference
+	payment, ok := p.payments[paymentID]
+	if !ok || payment.Status == StatusDeclined {
+		payment = &Payment{ID: paymentID, Reference: request.Reference, Status: StatusAuthorized, Amount: request.Amount}
+		if p.outcome == OutcomeDecline {
+			payment.Status = StatusDeclined
+		}
+		p.payments[paymentID] = payment
+	}
+	return p.result(payment)
+}
+
+func (p *FakeProvider) Capture(paymentID string, amount int) (*Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[paymentID]
+	if !ok || payment.Status != StatusAuthorized {
+		return nil, fmt.Errorf("payment %s is not authorized", paymentID)
+	}
+	if amount > payment.Amount {
+		return nil, fmt.Errorf("cannot capture %d, only %d is authorized", amount, payment.Amount)
+	}
+	payment.Status = StatusCaptured
+	payment.Amount = amount
+	return p.result(payment)
+}
+
+func (p *FakeProvider) Refund(paymentID string, amount int) (*Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[paymentID]
+	// A repeated refund returns the refund which was already made
+	if ok && payment.Status == StatusRefunded && payment.Amount == amount {
+		return p.result(payment)
+	}
+	if !ok || (payment.Status != StatusAuthorized && payment.Status != StatusCaptured) {
+		return nil, fmt.Errorf("payment %s cannot be refunded", paymentID)
+	}
+	if amount > payment.Amount {
+		return nil, fmt.Errorf("cannot refund %d, only %d is paid", amount, payment.Amount)
+	}
+	payment.Status = StatusRefunded
+	payment.Amount = amount
+	return p.result(payment)
+}
+
+// Webhook returns the signed callback which reports the current status of the payment
+func (p *FakeProvider) Webhook(paymentID string) ([]byte, string, error) {
+	p.mu.Lock()
+	payment, ok := p.payments[paymentID]
+	p.mu.Unlock()
+	if !ok {
+		return nil, "", fmt.Errorf("payment %s does not exist", paymentID)
+	}
+
+	payload, err := json.Marshal(WebhookEvent{
+		PaymentID: payment.ID,
+		Reference: payment.Reference,
+		Status:    payment.Status,
+		Amount:    payment.Amount,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+	return payload, p.sign(payload), nil
+}
+
+func (p *FakeProvider) ParseWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if !hmac.Equal([]byte(signature), []byte(p.sign(payload))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+	}
+	return &event, nil
+}
+
+func (p *FakeProvider) result(payment *Payment) (*Payment, error) {
+	if p.outcome == OutcomeTimeout {
+		return nil, ErrTimeout
+	}
+	result := *payment
+	return &result, nil
+}
+
+// sign is the hex encoded HMAC-SHA256 of the payload
+func (p *FakeProvider) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package payment_provider_test
+
+import (
+	"booking_service/internal/service_interaction/payment_provider"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFakeProvider_Succeed_AuthorizeCaptureRefund(t *testing.T) {
+	provider, err := payment_provider.NewFakeProvider(payment_provider.OutcomeSucceed, "secret")
+	assert.NoError(t, err)
+
+	payment, err := provider.Authorize(payment_provider.AuthorizeRequest{Reference: "rent", Amount: 1000_00, Currency: "RUB"})
+	assert.NoError(t, err)
+	assert.Equal(t, "fake_rent", payment.ID)
+	assert.Equal(t, payment_provider.StatusAuthorized, payment.Status)
+
+	payment, err = provider.Capture(payment.ID, 1000_00)
+	assert.NoError(t, err)
+	assert.Equal(t, payment_provider.StatusCaptured, payment.Status)
+
+	payment, err = provider.Refund(payment.ID, 400_00)
+	assert.NoError(t, err)
+	assert.Equal(t, payment_provider.StatusRefunded, payment.Status)
+	assert.Equal(t, 400_00, payment.Amount)
+
+	// A repeated refund returns the same refund, another amount cannot be refunded anymore
+	payment, err = provider.Refund(payment.ID, 400_00)
+	assert.NoError(t, err)
+	assert.Equal(t, 400_00, payment.Amount)
+	_, err = provider.Refund(payment.ID, 300_00)
+	assert.Error(t, err)
+}
+
+func TestFakeProvider_Decline_PaymentDeclined(t *testing.T) {
+	provider, _ := payment_provider.NewFakeProvider(payment_provider.OutcomeDecline, "secret")
+
+	payment, err := provider.Authorize(payment_provider.AuthorizeRequest{Reference: "rent", Amount: 1000_00})
+
+	assert.NoError(t, err)
+	assert.Equal(t, payment_provider.StatusDeclined, payment.Status)
+	_, err = provider.Capture(payment.ID, 1000_00)
+	assert.Error(t, err)
+}
+
+func TestFakeProvider_Timeout_OutcomeReportedByWebhook(t *testing.T) {
+	provider, _ := payment_provider.NewFakeProvider(payment_provider.OutcomeTimeout, "secret")
+
+	payment, err := provider.Authorize(payment_provider.AuthorizeRequest{Reference: "rent", Amount: 1000_00})
+	assert.Nil(t, payment)
+	assert.True(t, errors.Is(err, payment_provider.ErrTimeout))
+
+	payload, signature, err := provider.Webhook("fake_rent")
+	assert.NoError(t, err)
+	event, err := provider.ParseWebhook(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, "rent", event.Reference)
+	assert.Equal(t, payment_provider.StatusAuthorized, event.Status)
+}
+
+func TestFakeProvider_ForgedWebhook_InvalidSignature(t *testing.T) {
+	provider, _ := payment_provider.NewFakeProvider(payment_provider.OutcomeSucceed, "secret")
+
+	event, err := provider.ParseWebhook([]byte(`{"payment_id":"fake_rent","status":"captured"}`), "forged")
+
+	assert.Nil(t, event)
+	assert.True(t, errors.Is(err, payment_provider.ErrInvalidSignature))
+}
+
+func TestNewFakeProvider_UnknownOutcome_Error(t *testing.T) {
+	provider, err := payment_provider.NewFakeProvider("maybe", "secret")
+
+	assert.Nil(t, provider)
+	assert.Error(t, err)
+}
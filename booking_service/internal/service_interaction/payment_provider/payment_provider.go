@@ -0,0 +1,52 @@
+package payment_provider
+
+import "errors"
+
+// Statuses of a payment at the provider
+const (
+	StatusAuthorized = "authorized"
+	StatusDeclined   = "declined"
+	StatusCaptured   = "captured"
+	StatusRefunded   = "refunded"
+)
+
+// ErrTimeout is returned when the provider did not answer in time. The payment may still have been processed,
+// its outcome is then reported by a webhook
+var ErrTimeout = errors.New("payment provider timed out")
+
+// ErrInvalidSignature is returned for webhooks which were not sent by the provider
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+type AuthorizeRequest struct {
+	// Reference identifies the rent, authorizing the same reference again returns the same payment
+	Reference string
+	Amount    int
+	Currency  string
+}
+
+type Payment struct {
+	ID        string
+	Reference string
+	Status    string
+	Amount    int
+}
+
+// WebhookEvent is the callback the provider sends when the status of a payment changes
+type WebhookEvent struct {
+	PaymentID string `json:"payment_id"`
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Amount    int    `json:"amount"`
+}
+
+type IPaymentProvider interface {
+	// Authorize reserves the amount on the card of the guest, a declined payment is not an error
+	Authorize(request AuthorizeRequest) (*Payment, error)
+	// Capture charges the authorized amount
+	Capture(paymentID string, amount int) (*Payment, error)
+	// Refund returns the amount to the guest, an authorization which was not captured is released.
+	// Refunding the same payment again returns the refund which was already made
+	Refund(paymentID string, amount int) (*Payment, error)
+	// ParseWebhook checks the signature of a callback and returns the event it carries
+	ParseWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUserContactData_FullMethodName = "/service_interaction.UserService/GetUserContactData"
+	UserService_GetUserContactData_FullMethodName     = "/service_interaction.UserService/GetUserContactData"
+	UserService_GetUserContactDataById_FullMethodName = "/service_interaction.UserService/GetUserContactDataById"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type UserServiceClient interface {
 	GetUserContactData(ctx context.Context, in *GetUserDataRequest, opts ...grpc.CallOption) (*GetUserDataResponse, error)
+	GetUserContactDataById(ctx context.Context, in *GetUserByIdRequest, opts ...grpc.CallOption) (*GetUserDataResponse, error)
 }
 
 type userServiceClient struct {
@@ -47,11 +49,22 @@ func (c *userServiceClient) GetUserContactData(ctx context.Context, in *GetUserD
 	return out, nil
 }
 
+func (c *userServiceClient) GetUserContactDataById(ctx context.Context, in *GetUserByIdRequest, opts ...grpc.CallOption) (*GetUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserDataResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUserContactDataById_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
 type UserServiceServer interface {
 	GetUserContactData(context.Context, *GetUserDataRequest) (*GetUserDataResponse, error)
+	GetUserContactDataById(context.Context, *GetUserByIdRequest) (*GetUserDataResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -65,6 +78,9 @@ type UnimplementedUserServiceServer struct{}
 func (UnimplementedUserServiceServer) GetUserContactData(context.Context, *GetUserDataRequest) (*GetUserDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserContactData not implemented")
 }
+func (UnimplementedUserServiceServer) GetUserContactDataById(context.Context, *GetUserByIdRequest) (*GetUserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserContactDataById not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -104,6 +120,24 @@ func _UserService_GetUserContactData_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetUserContactDataById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserContactDataById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUserContactDataById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserContactDataById(ctx, req.(*GetUserByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +149,10 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserContactData",
 			Handler:    _UserService_GetUserContactData_Handler,
 		},
+		{
+			MethodName: "GetUserContactDataById",
+			Handler:    _UserService_GetUserContactDataById_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user_service.proto",
@@ -3,21 +3,40 @@ package user_service
 import (
 	"booking_service/internal/service_interaction/user_service/gen"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"log/slog"
 	"time"
 )
 
+// Roles of the users, owners administer hotels and guests book rooms
+const (
+	RoleOwner = "owner"
+	RoleGuest = "guest"
+)
+
+// ErrInvalidToken is returned if user_service does not accept the token of the user
+var ErrInvalidToken = errors.New("invalid token")
+
 type UserData struct {
 	Id    uuid.UUID `json:"id"`
 	Email string    `json:"email"`
 	Phone string    `json:"phone"`
+	Role  string    `json:"role"`
+}
+
+func (u *UserData) IsOwner() bool {
+	return u.Role == RoleOwner
 }
 
 type IUserServiceBridge interface {
 	GetUserContactData(token string) (*UserData, error)
+	GetUserContactDataByID(userID uuid.UUID) (*UserData, error)
 }
 
 type UserServiceBridge struct {
@@ -40,12 +59,43 @@ func (u *UserServiceBridge) GetUserContactData(token string) (*UserData, error)
 	defer cancel()
 
 	request := &gen.GetUserDataRequest{Token: token}
-	slog.Info("Sending request to get contact data of user with id " + token)
+	slog.Info("Sending request to get contact data of user by token")
 	response, err := u.GrpcClient.GetUserContactData(ctx, request)
 	if err != nil {
+		if code := status.Code(err); code == codes.Unauthenticated || code == codes.NotFound {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return newUserData(response)
+}
+
+// GetUserContactDataByID returns the contact data of any user, such as the guest of a rent,
+// or nil if the user does not exist
+func (u *UserServiceBridge) GetUserContactDataByID(userID uuid.UUID) (*UserData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request := &gen.GetUserByIdRequest{Id: userID.String()}
+	slog.Info("Sending request to get contact data of user by id")
+	response, err := u.GrpcClient.GetUserContactDataById(ctx, request)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
 		return nil, err
 	}
+	return newUserData(response)
+}
 
-	userContactData := &UserData{Email: response.Email, Phone: response.Phone}
+func newUserData(response *gen.GetUserDataResponse) (*UserData, error) {
+	userContactData := &UserData{Email: response.Email, Phone: response.Phone, Role: response.Role}
+	if response.Id != "" {
+		var err error
+		if userContactData.Id, err = uuid.Parse(response.Id); err != nil {
+			return nil, fmt.Errorf("invalid user id: %w", err)
+		}
+	}
 	return userContactData, nil
 }
@@ -5,9 +5,12 @@ import (
 	"booking_service/internal/service_interaction/user_service/gen"
 	"context"
 	"errors"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"testing"
 )
 
@@ -20,6 +23,11 @@ func (m *MockUserServiceClient) GetUserContactData(ctx context.Context, in *gen.
 	return args.Get(0).(*gen.GetUserDataResponse), args.Error(1)
 }
 
+func (m *MockUserServiceClient) GetUserContactDataById(ctx context.Context, in *gen.GetUserByIdRequest, opts ...grpc.CallOption) (*gen.GetUserDataResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*gen.GetUserDataResponse), args.Error(1)
+}
+
 func TestNewUserServiceBridge(t *testing.T) {
 	bridge, err := user_service.NewUserServiceBridge("address")
 
@@ -32,20 +40,25 @@ func TestUserServiceBridge_GetUserContactData(t *testing.T) {
 	userBridge := &user_service.UserServiceBridge{GrpcClient: mockClient}
 
 	userToken := "token"
+	expectedID := uuid.New()
 	expectedEmail := "test@example.com"
 	expectedPhone := "1234567890"
 
 	mockClient.On("GetUserContactData", mock.Anything, &gen.GetUserDataRequest{Token: userToken}).Return(&gen.GetUserDataResponse{
+		Id:    expectedID.String(),
 		Email: expectedEmail,
 		Phone: expectedPhone,
+		Role:  user_service.RoleOwner,
 	}, nil)
 
 	contactData, err := userBridge.GetUserContactData(userToken)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, contactData)
+	assert.Equal(t, expectedID, contactData.Id)
 	assert.Equal(t, expectedEmail, contactData.Email)
 	assert.Equal(t, expectedPhone, contactData.Phone)
+	assert.True(t, contactData.IsOwner())
 	mockClient.AssertExpectations(t)
 }
 
@@ -78,3 +91,51 @@ func TestUserServiceBridge_GetUserContactData_Timeout(t *testing.T) {
 	assert.Nil(t, contactData)
 	mockClient.AssertExpectations(t)
 }
+
+func TestUserServiceBridge_GetUserContactData_Unauthenticated(t *testing.T) {
+	mockClient := new(MockUserServiceClient)
+	userBridge := &user_service.UserServiceBridge{GrpcClient: mockClient}
+
+	mockClient.On("GetUserContactData", mock.Anything, &gen.GetUserDataRequest{Token: "expired"}).
+		Return((*gen.GetUserDataResponse)(nil), status.Error(codes.Unauthenticated, "invalid token"))
+
+	contactData, err := userBridge.GetUserContactData("expired")
+
+	assert.Nil(t, contactData)
+	assert.ErrorIs(t, err, user_service.ErrInvalidToken)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUserServiceBridge_GetUserContactDataByID(t *testing.T) {
+	mockClient := new(MockUserServiceClient)
+	userBridge := &user_service.UserServiceBridge{GrpcClient: mockClient}
+
+	userID := uuid.New()
+	mockClient.On("GetUserContactDataById", mock.Anything, &gen.GetUserByIdRequest{Id: userID.String()}).Return(&gen.GetUserDataResponse{
+		Id:    userID.String(),
+		Email: "guest@example.com",
+		Role:  user_service.RoleGuest,
+	}, nil)
+
+	contactData, err := userBridge.GetUserContactDataByID(userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, userID, contactData.Id)
+	assert.Equal(t, "guest@example.com", contactData.Email)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUserServiceBridge_GetUserContactDataByID_NotFound(t *testing.T) {
+	mockClient := new(MockUserServiceClient)
+	userBridge := &user_service.UserServiceBridge{GrpcClient: mockClient}
+
+	userID := uuid.New()
+	mockClient.On("GetUserContactDataById", mock.Anything, &gen.GetUserByIdRequest{Id: userID.String()}).
+		Return((*gen.GetUserDataResponse)(nil), status.Error(codes.NotFound, "user not found"))
+
+	contactData, err := userBridge.GetUserContactDataByID(userID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, contactData)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/notification_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+// CreateGroupRent books a room for every line of the group in one transaction, so either all the rooms
+// are booked or none of them. The guest is notified once about the whole group
+func (s *BookingService) CreateGroupRent(request requests.CreateGroupRentRequest, caller *user_service.UserData) (*responses.GetRentGroupResponse, error) {
+	slog.Info("Creation group rent in service")
+	rentRequests := make([]requests.CreateRentRequest, 0, len(request.Lines))
+	quotes := make([]*responses.QuoteResponse, 0, len(request.Lines))
+	for _, line := range request.Lines {
+		rentRequest := requests.CreateRentRequest{
+			HotelID:          request.HotelID,
+			RoomTypeID:       line.RoomTypeID,
+			CheckInDate:      line.CheckInDate,
+			CheckOutDate:     line.CheckOutDate,
+			Adults:           line.Adults,
+			Children:         line.Children,
+			PrimaryGuestName: line.PrimaryGuestName,
+			AdditionalGuests: line.AdditionalGuests,
+		}
+		applyGuestDefaults(&rentRequest)
+		if err := s.validateGuests(request.HotelID, line.RoomTypeID, rentRequest); err != nil {
+			return nil, err
+		}
+
+		quote, err := s.pricingService.Quote(requests.QuoteRequest{
+			HotelID:      request.HotelID,
+			RoomTypeID:   line.RoomTypeID,
+			CheckInDate:  line.CheckInDate,
+			CheckOutDate: line.CheckOutDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rentRequests = append(rentRequests, rentRequest)
+		quotes = append(quotes, quote)
+	}
+
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	group := &responses.GetRentGroupResponse{
+		HotelID:  request.HotelID,
+		ClientID: caller.Id,
+		Rents:    make([]responses.GetRentResponse, 0, len(request.Lines)),
+		Currency: DefaultCurrency,
+	}
+	query := `INSERT INTO booking_groups (hotel_id, client_id) VALUES ($1, $2) RETURNING id, created_at`
+	if err := tx.QueryRow(query, request.HotelID, caller.Id).Scan(&group.ID, &group.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create rent group: %w", err)
+	}
+
+	// The rents of the transaction are visible to the next inserts, so two lines never get the same room
+	rentIDs := make([]uuid.UUID, 0, len(request.Lines))
+	for i, rentRequest := range rentRequests {
+		rent, err := insertRent(tx, rentRequest, caller.Id, quotes[i])
+		if err != nil {
+			if errors.As(err, new(*custom_errors.ServiceBadRequestError)) {
+				return nil, newNoRoomsForGroupLineError(i)
+			}
+			return nil, err
+		}
+		rentIDs = append(rentIDs, rent.ID)
+		group.Rents = append(group.Rents, *rent)
+		group.TotalAmount += rent.TotalAmount
+		group.Currency = rent.Currency
+	}
+
+	query = `UPDATE bookings SET group_id = $1 WHERE id = ANY($2)`
+	if _, err := tx.Exec(query, group.ID, pq.Array(rentIDs)); err != nil {
+		return nil, fmt.Errorf("failed to group rents: %w", err)
+	}
+
+	notificationData := &notification_service.NotificationData{
+		EventType:       notification_service.EventGroupBookingCreated,
+		UserContactData: caller,
+		Group:           group,
+	}
+	if err := enqueueNotification(tx, notificationData); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isExclusionViolation(err) {
+			return nil, newNoRoomsAvailableError()
+		}
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return group, nil
+}
+
+// GetGroupRent returns the group with its rents, it is accessible to its client and to the owner of its hotel
+func (s *BookingService) GetGroupRent(groupID uuid.UUID, caller *user_service.UserData) (*responses.GetRentGroupResponse, error) {
+	slog.Info("Getting group rent in service")
+	group := &responses.GetRentGroupResponse{ID: groupID, Currency: DefaultCurrency}
+	query := `SELECT g.hotel_id, g.client_id, g.created_at FROM booking_groups g WHERE g.id = $1`
+	err := s.Db.Connection.QueryRow(query, groupID).Scan(&group.HotelID, &group.ClientID, &group.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch rent group: %w", err)
+	}
+
+	if group.ClientID != caller.Id {
+		if err := s.authorizeHotel(caller, group.HotelID); err != nil {
+			return nil, err
+		}
+	}
+
+	query = `
+		SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+		       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+		       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
+		FROM bookings b
+		WHERE b.group_id = $1
+		ORDER BY b.check_in_date, b.id`
+	rows, err := s.Db.Connection.Query(query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve rents of the group: %w", err)
+	}
+	defer rows.Close()
+
+	group.Rents = []responses.GetRentResponse{}
+	for rows.Next() {
+		rent, err := s.scanRent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rent: %w", err)
+		}
+		group.Rents = append(group.Rents, *rent)
+		group.TotalAmount += rent.TotalAmount
+		group.Currency = rent.Currency
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rents of the group: %w", err)
+	}
+	return group, nil
+}
+
+func newNoRoomsForGroupLineError(line int) error {
+	return custom_errors.NewServiceBadRequestError("No rooms available",
+		fmt.Sprintf("all rooms are booked for the dates of line %d, no room of the group was booked", line+1))
+}
@@ -0,0 +1,167 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newGroupTestRequest() requests.CreateGroupRentRequest {
+	checkInDate := time.Now().Add(24 * time.Hour)
+	return requests.CreateGroupRentRequest{
+		HotelID: uuid.New(),
+		Lines: []requests.GroupRentLine{
+			{CheckInDate: checkInDate, CheckOutDate: checkInDate.Add(48 * time.Hour)},
+			{CheckInDate: checkInDate.Add(24 * time.Hour), CheckOutDate: checkInDate.Add(48 * time.Hour)},
+		},
+	}
+}
+
+func TestCreateGroupRent_CommonCase_AllRoomsBooked(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	request := newGroupTestRequest()
+	caller := &user_service.UserData{Id: uuid.New()}
+	nightPrice := 1000_00
+	groupID, firstRentID, secondRentID := uuid.New(), uuid.New(), uuid.New()
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO booking_groups \(hotel_id, client_id\) VALUES \(\$1, \$2\) RETURNING id, created_at`).
+		WithArgs(request.HotelID, caller.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(groupID, time.Now()))
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, night_price, nights, currency, total_amount, taxes, service_fee, adults, children, primary_guest_name, additional_guests\)`).
+		WithArgs(request.HotelID, caller.Id, request.Lines[0].CheckInDate, request.Lines[0].CheckOutDate, nil,
+			nightPrice, 2, services.DefaultCurrency, 2*nightPrice, 0, 0, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).
+			AddRow(firstRentID, uuid.New(), nil, time.Now()))
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, night_price, nights, currency, total_amount, taxes, service_fee, adults, children, primary_guest_name, additional_guests\)`).
+		WithArgs(request.HotelID, caller.Id, request.Lines[1].CheckInDate, request.Lines[1].CheckOutDate, nil,
+			nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).
+			AddRow(secondRentID, uuid.New(), nil, time.Now()))
+	mock.ExpectExec(`UPDATE bookings SET group_id = \$1 WHERE id = ANY\(\$2\)`).
+		WithArgs(groupID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	// One notification for the whole group
+	expectOutbox(mock, "group_booking_created")
+	mock.ExpectCommit()
+
+	group, err := bookingService.CreateGroupRent(request, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, groupID, group.ID)
+	assert.Len(t, group.Rents, 2)
+	assert.Equal(t, firstRentID, group.Rents[0].ID)
+	assert.Equal(t, secondRentID, group.Rents[1].ID)
+	assert.Equal(t, 3*nightPrice, group.TotalAmount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateGroupRent_NoRoomsForLine_NothingBooked(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	request := newGroupTestRequest()
+	caller := &user_service.UserData{Id: uuid.New()}
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000_00, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO booking_groups`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(uuid.New(), time.Now()))
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).
+			AddRow(uuid.New(), uuid.New(), nil, time.Now()))
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WillReturnError(sql.ErrNoRows)
+	// The room booked for the first line is released with the rest of the transaction
+	mock.ExpectRollback()
+
+	group, err := bookingService.CreateGroupRent(request, caller)
+
+	assert.Nil(t, group)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.Contains(t, err.Error(), "line 2")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetGroupRent_AnotherGuest_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	groupID := uuid.New()
+	mock.ExpectQuery(`SELECT g.hotel_id, g.client_id, g.created_at FROM booking_groups g WHERE g.id = \$1`).
+		WithArgs(groupID).
+		WillReturnRows(sqlmock.NewRows([]string{"hotel_id", "client_id", "created_at"}).
+			AddRow(uuid.New(), uuid.New(), time.Now()))
+
+	group, err := bookingService.GetGroupRent(groupID, &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest})
+
+	assert.Nil(t, group)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetGroupRent_CommonCase_ReturnRents(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	groupID := uuid.New()
+	caller := &user_service.UserData{Id: rent.ClientID}
+
+	mock.ExpectQuery(`FROM booking_groups g WHERE g.id = \$1`).
+		WithArgs(groupID).
+		WillReturnRows(sqlmock.NewRows([]string{"hotel_id", "client_id", "created_at"}).
+			AddRow(rent.HotelID, rent.ClientID, time.Now()))
+	mock.ExpectQuery(`b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b WHERE b.group_id = \$1 ORDER BY b.check_in_date, b.id`).
+		WithArgs(groupID).
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow(rent.ID, rent.HotelID, rent.RoomID, nil, rent.ClientID, rent.CheckInDate, rent.CheckOutDate, rent.Status,
+				rent.NightPrice, rent.Nights, rent.Currency, rent.TotalAmount, rent.CreatedAt, rent.Version, 1, 0, "", nil, "unpaid"))
+
+	group, err := bookingService.GetGroupRent(groupID, caller)
+
+	assert.NoError(t, err)
+	assert.Len(t, group.Rents, 1)
+	assert.Equal(t, rent.ID, group.Rents[0].ID)
+	assert.Equal(t, rent.TotalAmount, group.TotalAmount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// applyGuestDefaults takes a request without guests for one adult, as rents were taken before guests were recorded
+func applyGuestDefaults(request *requests.CreateRentRequest) {
+	if request.Adults == 0 {
+		request.Adults = 1
+	}
+	request.PrimaryGuestName = strings.TrimSpace(request.PrimaryGuestName)
+}
+
+// validateGuests checks the guests of the request and that they fit into a room of the room type.
+// Without a room type any room of the hotel may be booked, so the capacity is not checked
+func (s *BookingService) validateGuests(hotelID uuid.UUID, roomTypeID *uuid.UUID, request requests.CreateRentRequest) error {
+	if request.Adults < 1 || request.Children < 0 {
+		return custom_errors.NewServiceBadRequestError("Invalid guests",
+			"at least one adult is required and children cannot be negative")
+	}
+	// The primary guest is one of the party, the additional guests are the rest of it
+	if len(request.AdditionalGuests) > request.Adults+request.Children-1 {
+		return custom_errors.NewServiceBadRequestError("Invalid guests", "there are more guests named than staying")
+	}
+	for _, guest := range request.AdditionalGuests {
+		if strings.TrimSpace(guest) == "" {
+			return custom_errors.NewServiceBadRequestError("Invalid guests", "guest name cannot be empty")
+		}
+	}
+
+	if roomTypeID == nil {
+		return nil
+	}
+	roomType, err := s.hotelServiceBridge.GetRoomType(hotelID, *roomTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get room type: %w", err)
+	}
+	if roomType == nil {
+		return custom_errors.NewServiceBadRequestError("Room type not found", roomTypeID.String())
+	}
+	if request.Adults+request.Children > roomType.Capacity {
+		return custom_errors.NewServiceBadRequestError("Too many guests",
+			fmt.Sprintf("the room sleeps at most %d guests", roomType.Capacity))
+	}
+	return nil
+}
+
+// setRentGuests records the guests of the request on the rent
+func setRentGuests(tx *sql.Tx, rent *responses.GetRentResponse, request requests.CreateRentRequest) error {
+	query := `
+		UPDATE bookings
+		SET adults = $2, children = $3, primary_guest_name = $4, additional_guests = $5
+		WHERE id = $1`
+	_, err := tx.Exec(query, rent.ID, request.Adults, request.Children, request.PrimaryGuestName,
+		pq.Array(guestNames(request.AdditionalGuests)))
+	if err != nil {
+		return fmt.Errorf("failed to set guests of rent: %w", err)
+	}
+	copyGuests(rent, request)
+	return nil
+}
+
+func copyGuests(rent *responses.GetRentResponse, request requests.CreateRentRequest) {
+	rent.Adults = request.Adults
+	rent.Children = request.Children
+	rent.PrimaryGuestName = request.PrimaryGuestName
+	rent.AdditionalGuests = guestNames(request.AdditionalGuests)
+}
+
+// guestNames never returns nil, the column of the additional guests is not nullable
+func guestNames(guests []string) []string {
+	names := make([]string, 0, len(guests))
+	for _, guest := range guests {
+		names = append(names, strings.TrimSpace(guest))
+	}
+	return names
+}
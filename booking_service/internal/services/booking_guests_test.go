@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCreateRent_WithGuests_GuestsStored(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	roomTypeID := uuid.New()
+	request := requests.CreateRentRequest{
+		HotelID:          uuid.New(),
+		RoomTypeID:       &roomTypeID,
+		CheckInDate:      time.Now(),
+		CheckOutDate:     time.Now().Add(24 * time.Hour),
+		Adults:           2,
+		Children:         1,
+		PrimaryGuestName: " Ivan Petrov ",
+		AdditionalGuests: []string{"Anna Petrova"},
+	}
+	nightPrice := 1000_00
+	caller := &user_service.UserData{Id: uuid.New()}
+
+	bridgeMock.On("GetRoomType", request.HotelID, roomTypeID).
+		Return(&hotel_service.RoomTypeData{ID: roomTypeID, HotelID: request.HotelID, Capacity: 3, NightPrice: nightPrice}, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(request.HotelID, caller.Id, request.CheckInDate, request.CheckOutDate, &roomTypeID, nightPrice, 1,
+			services.DefaultCurrency, nightPrice, 0, 0, 2, 1, "Ivan Petrov", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).
+			AddRow(uuid.New(), uuid.New(), roomTypeID, time.Now()))
+	expectOutbox(mock, "booking_created")
+	mock.ExpectCommit()
+
+	_, err := bookingService.CreateRent(request, caller, "")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_MoreGuestsThanCapacity_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	roomTypeID := uuid.New()
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		RoomTypeID:   &roomTypeID,
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		Adults:       2,
+		Children:     2,
+	}
+
+	bridgeMock.On("GetRoomType", request.HotelID, roomTypeID).
+		Return(&hotel_service.RoomTypeData{ID: roomTypeID, HotelID: request.HotelID, Capacity: 3}, nil)
+
+	id, err := bookingService.CreateRent(request, &user_service.UserData{Id: uuid.New()}, "")
+
+	assert.Equal(t, uuid.Nil, id)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.Contains(t, err.Error(), "at most 3 guests")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_MoreNamedGuestsThanStaying_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	// The primary guest is the only one staying, so nobody else may be named
+	request := requests.CreateRentRequest{
+		HotelID:          uuid.New(),
+		CheckInDate:      time.Now(),
+		CheckOutDate:     time.Now().Add(24 * time.Hour),
+		PrimaryGuestName: "Ivan Petrov",
+		AdditionalGuests: []string{"Anna Petrova"},
+	}
+
+	id, err := bookingService.CreateRent(request, &user_service.UserData{Id: uuid.New()}, "")
+
+	assert.Equal(t, uuid.Nil, id)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/metrics"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/user_service"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// CreateHold blocks a free room for the stay of the caller until the hold expires or is converted
+// into a rent. The price of the stay is fixed when the hold is made
+func (s *BookingService) CreateHold(request requests.CreateHoldRequest, caller *user_service.UserData) (*responses.CreateHoldResponse, error) {
+	slog.Info("Creation hold in service")
+	quote, err := s.pricingService.Quote(requests.QuoteRequest{
+		HotelID:      request.HotelID,
+		RoomTypeID:   request.RoomTypeID,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hold := &responses.CreateHoldResponse{
+		ExpiresAt:   time.Now().Add(s.holdCfg.TTL),
+		TotalAmount: quote.Total,
+		Currency:    quote.Currency,
+	}
+	hold.HoldID, err = insertHold(s.Db.Connection, request, caller.Id, quote, hold.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || isExclusionViolation(err) {
+			return nil, newNoRoomsAvailableError()
+		}
+		return nil, fmt.Errorf("failed to create hold: %w", err)
+	}
+	return hold, nil
+}
+
+// rowQuerier is satisfied both by the database and by a transaction
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// insertHold blocks a free room for the stay of the client until expiresAt.
+// sql.ErrNoRows is returned if there is no free room
+func insertHold(querier rowQuerier, request requests.CreateHoldRequest, clientID uuid.UUID,
+	quote *responses.QuoteResponse, expiresAt time.Time) (uuid.UUID, error) {
+	// A hold is a booking, so the exclusion constraint on bookings guards it like any other rent
+	query := `
+        INSERT INTO bookings (hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date,
+                              night_price, nights, currency, total_amount, taxes, service_fee, status, hold_expires_at)
+        SELECT r.hotel_id, r.id, r.room_type_id, $2, $3, $4, $6, $7, $8, $9, $10, $11, 'held', $12
+        FROM rooms r
+        WHERE r.hotel_id = $1 AND ($5::uuid IS NULL OR r.room_type_id = $5) AND NOT EXISTS (
+            SELECT 1 FROM bookings b
+            WHERE b.room_id = r.id AND b.status NOT IN ('cancelled', 'no_show')
+              AND tstzrange(b.check_in_date, b.check_out_date) && tstzrange($3, $4))
+        LIMIT 1
+        RETURNING id`
+	var holdID uuid.UUID
+	err := querier.QueryRow(query, request.HotelID, clientID, request.CheckInDate, request.CheckOutDate,
+		request.RoomTypeID, quote.NightPrice, quote.Nights, quote.Currency, quote.Total, quote.Taxes, quote.Fees, expiresAt).
+		Scan(&holdID)
+	return holdID, err
+}
+
+// convertHold turns the unexpired hold of the client into a pending rent of the guests of the request
+// within the transaction. The stay of the request may be omitted, if it is given it has to be the stay of the hold
+func (s *BookingService) convertHold(tx *sql.Tx, holdID uuid.UUID, request requests.CreateRentRequest, clientID uuid.UUID) (*responses.GetRentResponse, error) {
+	query := `
+		SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+		       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+		       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
+		FROM bookings b
+		WHERE b.id = $1 AND b.client_id = $2 AND b.status = 'held' AND b.hold_expires_at > now()
+		FOR UPDATE`
+	rent, _, err := scanRentRow(tx.QueryRow(query, holdID, clientID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, custom_errors.NewServiceUnprocessableEntityError("Hold is not available",
+				"the hold does not exist, has expired or belongs to another client")
+		}
+		return nil, fmt.Errorf("failed to fetch hold: %w", err)
+	}
+
+	if (request.HotelID != uuid.Nil && request.HotelID != rent.HotelID) ||
+		(request.RoomTypeID != nil && (rent.RoomTypeID == nil || *request.RoomTypeID != *rent.RoomTypeID)) ||
+		(!request.CheckInDate.IsZero() && !request.CheckInDate.Equal(rent.CheckInDate)) ||
+		(!request.CheckOutDate.IsZero() && !request.CheckOutDate.Equal(rent.CheckOutDate)) {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid rent", "the stay differs from the stay of the hold")
+	}
+
+	if err := s.validateGuests(rent.HotelID, rent.RoomTypeID, request); err != nil {
+		return nil, err
+	}
+
+	if _, err := changeStatus(tx, holdID, StatusPending, clientID, "hold converted"); err != nil {
+		return nil, err
+	}
+	if err := setRentGuests(tx, rent, request); err != nil {
+		return nil, err
+	}
+	rent.Status = StatusPending
+	rent.Version++
+	return rent, nil
+}
+
+// HoldSweeper releases the rooms of the holds which were not converted before they expired
+// and purges the expired idempotency keys
+type HoldSweeper struct {
+	Db              *db.Database
+	cfg             *config.HoldConfig
+	waitlistService IWaitlistService
+}
+
+func NewHoldSweeper(database *db.Database, cfg *config.HoldConfig, waitlistService IWaitlistService) *HoldSweeper {
+	return &HoldSweeper{Db: database, cfg: cfg, waitlistService: waitlistService}
+}
+
+// Run releases expired holds and purges expired idempotency keys until the context is cancelled
+func (s *HoldSweeper) Run(ctx context.Context) {
+	slog.Info("Starting hold sweeper")
+	ticker := time.NewTicker(s.cfg.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Hold sweeper stopped")
+			return
+		case <-ticker.C:
+			if _, err := s.ReleaseExpiredHolds(); err != nil {
+				slog.Error(fmt.Sprintf("Failed to release expired holds: %v", err))
+			}
+			if _, err := s.PurgeExpiredIdempotencyKeys(); err != nil {
+				slog.Error(fmt.Sprintf("Failed to purge expired idempotency keys: %v", err))
+			}
+		}
+	}
+}
+
+// ReleaseExpiredHolds deletes the expired holds and returns how many of them were released.
+// The waitlist entries whose offered hold expired are expired with it, the guest has let the offer lapse.
+// The released rooms are offered to the waitlists of their hotels
+func (s *HoldSweeper) ReleaseExpiredHolds() (int, error) {
+	// One statement expires the offers of exactly the holds it deletes
+	query := `
+		WITH released AS (
+			DELETE FROM bookings WHERE status = 'held' AND hold_expires_at <= now() RETURNING id, hotel_id
+		), expired_offers AS (
+			UPDATE waitlist_entries SET status = $1
+			WHERE status = $2 AND hold_id IN (SELECT id FROM released)
+		)
+		SELECT hotel_id FROM released`
+	rows, err := s.Db.Connection.Query(query, WaitlistStatusExpired, WaitlistStatusOffered)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release expired holds: %w", err)
+	}
+	defer rows.Close()
+
+	released := 0
+	var hotelIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var hotelID uuid.UUID
+		if err := rows.Scan(&hotelID); err != nil {
+			return 0, fmt.Errorf("failed to scan released hold: %w", err)
+		}
+		released++
+		if !seen[hotelID] {
+			seen[hotelID] = true
+			hotelIDs = append(hotelIDs, hotelID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to release expired holds: %w", err)
+	}
+	metrics.ExpiredHoldsTotal.Add(float64(released))
+
+	for _, hotelID := range hotelIDs {
+		if _, err := s.waitlistService.OfferFreedCapacity(hotelID); err != nil {
+			slog.Error(fmt.Sprintf("Failed to offer released holds to the waitlist: %v", err))
+		}
+	}
+	return released, nil
+}
+
+// PurgeExpiredIdempotencyKeys deletes the keys which can no longer be replayed and returns how many of them were deleted
+func (s *HoldSweeper) PurgeExpiredIdempotencyKeys() (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= now()`
+	result, err := s.Db.Connection.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,241 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var holdRowColumns = []string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date",
+	"status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}
+
+func TestCreateHold_CommonCase_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	holdID := uuid.New()
+	request := requests.CreateHoldRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+	}
+	nightPrice := 1000_00
+	caller := &user_service.UserData{Id: uuid.New()}
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, night_price, nights, currency, total_amount, taxes, service_fee, status, hold_expires_at\) SELECT r.hotel_id, r.id, r.room_type_id, \$2, \$3, \$4, \$6, \$7, \$8, \$9, \$10, \$11, 'held', \$12 FROM rooms r`).
+		WithArgs(request.HotelID, caller.Id, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holdID))
+
+	hold, err := bookingService.CreateHold(request, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, holdID, hold.HoldID)
+	assert.Equal(t, nightPrice, hold.TotalAmount)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), hold.ExpiresAt, time.Minute)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateHold_NoFreeRooms_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	request := requests.CreateHoldRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+	}
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000_00, nil)
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WillReturnError(sql.ErrNoRows)
+
+	hold, err := bookingService.CreateHold(request, &user_service.UserData{Id: uuid.New()})
+
+	assert.Nil(t, hold)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_FromHold_HoldConverted(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	hold := newAccessTestRent()
+	hold.Status = services.StatusHeld
+	caller := &user_service.UserData{Id: hold.ClientID}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM bookings b WHERE b.id = \$1 AND b.client_id = \$2 AND b.status = 'held' AND b.hold_expires_at > now\(\) FOR UPDATE`).
+		WithArgs(hold.ID, caller.Id).
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow(hold.ID, hold.HotelID, hold.RoomID, nil, hold.ClientID, hold.CheckInDate, hold.CheckOutDate, hold.Status,
+				hold.NightPrice, hold.Nights, hold.Currency, hold.TotalAmount, hold.CreatedAt, hold.Version, 1, 0, "", nil, "unpaid"))
+	expectStatusChange(mock, hold.ID, services.StatusHeld, services.StatusPending, caller.Id, "hold converted")
+	mock.ExpectExec(`UPDATE bookings SET adults = \$2, children = \$3, primary_guest_name = \$4, additional_guests = \$5 WHERE id = \$1`).
+		WithArgs(hold.ID, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_created")
+	mock.ExpectCommit()
+
+	// The stay is taken from the hold
+	id, err := bookingService.CreateRent(requests.CreateRentRequest{HoldID: &hold.ID}, caller, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, hold.ID, id)
+	bridgeMock.AssertNotCalled(t, "GetHotelPrice", hold.HotelID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_FromExpiredHold_UnprocessableEntity(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	holdID := uuid.New()
+	caller := &user_service.UserData{Id: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM bookings b WHERE b.id = \$1 AND b.client_id = \$2 AND b.status = 'held'`).
+		WithArgs(holdID, caller.Id).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	id, err := bookingService.CreateRent(requests.CreateRentRequest{HoldID: &holdID}, caller, "")
+
+	assert.Equal(t, uuid.Nil, id)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_FromHoldWithOtherDates_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	hold := newAccessTestRent()
+	hold.Status = services.StatusHeld
+	caller := &user_service.UserData{Id: hold.ClientID}
+	request := requests.CreateRentRequest{
+		HoldID:       &hold.ID,
+		HotelID:      hold.HotelID,
+		CheckInDate:  hold.CheckInDate,
+		CheckOutDate: hold.CheckOutDate.Add(24 * time.Hour),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM bookings b WHERE b.id = \$1 AND b.client_id = \$2 AND b.status = 'held'`).
+		WithArgs(hold.ID, caller.Id).
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow(hold.ID, hold.HotelID, hold.RoomID, nil, hold.ClientID, hold.CheckInDate, hold.CheckOutDate, hold.Status,
+				hold.NightPrice, hold.Nights, hold.Currency, hold.TotalAmount, hold.CreatedAt, hold.Version, 1, 0, "", nil, "unpaid"))
+	mock.ExpectRollback()
+
+	id, err := bookingService.CreateRent(request, caller, "")
+
+	assert.Equal(t, uuid.Nil, id)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseExpiredHolds(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	waitlistMock := &MockWaitlistService{}
+	sweeper := services.NewHoldSweeper(&db2.Database{Connection: db}, &config.HoldConfig{SweepInterval: time.Second}, waitlistMock)
+	firstHotelID, secondHotelID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`DELETE FROM bookings WHERE status = 'held' AND hold_expires_at <= now\(\) RETURNING id, hotel_id`).
+		WithArgs(services.WaitlistStatusExpired, services.WaitlistStatusOffered).
+		WillReturnRows(sqlmock.NewRows([]string{"hotel_id"}).AddRow(firstHotelID).AddRow(secondHotelID).AddRow(firstHotelID))
+	// The released rooms are offered once per hotel
+	waitlistMock.On("OfferFreedCapacity", firstHotelID).Return(1, nil).Once()
+	waitlistMock.On("OfferFreedCapacity", secondHotelID).Return(0, nil).Once()
+
+	released, err := sweeper.ReleaseExpiredHolds()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, released)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseExpiredHolds_ExpiresLapsedOffers(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	waitlistMock := &MockWaitlistService{}
+	sweeper := services.NewHoldSweeper(&db2.Database{Connection: db}, &config.HoldConfig{SweepInterval: time.Second}, waitlistMock)
+	hotelID := uuid.New()
+
+	// The offers of the deleted holds are expired by the same statement, so they are not left offered
+	mock.ExpectQuery(`UPDATE waitlist_entries SET status = \$1\s+WHERE status = \$2 AND hold_id IN \(SELECT id FROM released\)`).
+		WithArgs(services.WaitlistStatusExpired, services.WaitlistStatusOffered).
+		WillReturnRows(sqlmock.NewRows([]string{"hotel_id"}).AddRow(hotelID))
+	waitlistMock.On("OfferFreedCapacity", hotelID).Return(0, nil).Once()
+
+	released, err := sweeper.ReleaseExpiredHolds()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeExpiredIdempotencyKeys(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	sweeper := services.NewHoldSweeper(&db2.Database{Connection: db}, &config.HoldConfig{SweepInterval: time.Second}, &MockWaitlistService{})
+
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE expires_at <= now\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	purged, err := sweeper.PurgeExpiredIdempotencyKeys()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"booking_service/internal/config"
 	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
 	"booking_service/internal/rest/dtos/requests"
 	"booking_service/internal/rest/dtos/responses"
 	"booking_service/internal/service_interaction/hotel_service"
@@ -9,121 +11,624 @@ import (
 	"booking_service/internal/service_interaction/user_service"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"log/slog"
+	"time"
 )
 
+// DefaultCurrency is the currency of all prices, hotel_service does not store one
+const DefaultCurrency = "RUB"
+
 type IBookingService interface {
-	CreateRent(request requests.CreateRentRequest, token string) (uuid.UUID, error)
-	UpdateRent(rentID uuid.UUID, request requests.UpdateRentRequest) error
-	GetRentByID(rentID uuid.UUID) (*responses.GetRentResponse, error)
-	GetRents(filter requests.RentFilter) (*responses.GetRentsResponse, error)
+	CreateRent(request requests.CreateRentRequest, caller *user_service.UserData, idempotencyKey string) (uuid.UUID, error)
+	CreateHold(request requests.CreateHoldRequest, caller *user_service.UserData) (*responses.CreateHoldResponse, error)
+	CreateGroupRent(request requests.CreateGroupRentRequest, caller *user_service.UserData) (*responses.GetRentGroupResponse, error)
+	GetGroupRent(groupID uuid.UUID, caller *user_service.UserData) (*responses.GetRentGroupResponse, error)
+	UpdateRent(rentID uuid.UUID, request requests.UpdateRentRequest, caller *user_service.UserData, version int) (*responses.RentModificationResponse, error)
+	PatchRent(rentID uuid.UUID, patch []byte, caller *user_service.UserData, version int) (*responses.GetRentResponse, error)
+	GetRentByID(rentID uuid.UUID, caller *user_service.UserData) (*responses.GetRentResponse, error)
+	GetRents(filter requests.RentFilter, caller *user_service.UserData) (*responses.GetRentsResponse, error)
+	ExportRents(ctx context.Context, filter requests.RentFilter, caller *user_service.UserData,
+		write func(rent *responses.ExportedRentResponse) error) error
+	CancelRent(rentID uuid.UUID, request requests.CancelRentRequest, caller *user_service.UserData) (*responses.CancelRentResponse, error)
+	TransitionRent(rentID uuid.UUID, request requests.TransitionRentRequest, caller *user_service.UserData) (*responses.RentTransitionResponse, error)
 }
 
 type BookingService struct {
 	Db                        *db.Database
 	hotelServiceBridge        hotel_service.IHotelServiceBridge
-	userServiceBridge         user_service.IUserServiceBridge
-	notificationServiceBridge notification_service.INotificationServiceBridge
+	cancellationPolicyService ICancellationPolicyService
+	pricingService            IPricingService
+	idempotencyCfg            *config.IdempotencyConfig
+	holdCfg                   *config.HoldConfig
+	waitlistService           IWaitlistService
 }
 
 func NewBookingService(
 	database *db.Database,
 	hotelServiceBridge hotel_service.IHotelServiceBridge,
-	userServiceBridge user_service.IUserServiceBridge,
-	notificationServiceBridge notification_service.INotificationServiceBridge) *BookingService {
+	cancellationPolicyService ICancellationPolicyService,
+	pricingService IPricingService,
+	idempotencyCfg *config.IdempotencyConfig,
+	holdCfg *config.HoldConfig,
+	waitlistService IWaitlistService) *BookingService {
 	return &BookingService{
 		Db:                        database,
 		hotelServiceBridge:        hotelServiceBridge,
-		userServiceBridge:         userServiceBridge,
-		notificationServiceBridge: notificationServiceBridge}
+		cancellationPolicyService: cancellationPolicyService,
+		pricingService:            pricingService,
+		idempotencyCfg:            idempotencyCfg,
+		holdCfg:                   holdCfg,
+		waitlistService:           waitlistService}
 }
 
-func (s *BookingService) CreateRent(request requests.CreateRentRequest, token string) (uuid.UUID, error) {
+// CreateRent books a free room for the caller, or converts the hold of the caller if the request has one.
+// A repeated request with the same non-empty idempotency key returns the rent created by the first one
+// instead of booking another room
+func (s *BookingService) CreateRent(request requests.CreateRentRequest, caller *user_service.UserData, idempotencyKey string) (uuid.UUID, error) {
 	slog.Info("Creation rent in service")
-	userData, err := s.userServiceBridge.GetUserContactData(token)
+	var fingerprint string
+	var err error
+	if idempotencyKey != "" {
+		fingerprint, err = fingerprintRequest(request)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		rentID, err := s.findIdempotentRent(idempotencyKey, caller.Id, fingerprint)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if rentID != nil {
+			slog.Info("Replaying the rent created with the idempotency key")
+			return *rentID, nil
+		}
+	}
+
+	// The price of a hold was fixed when the hold was made, its guests are checked when it is converted
+	applyGuestDefaults(&request)
+	var quote *responses.QuoteResponse
+	var promoCode *responses.PromoCodeResponse
+	if request.HoldID != nil && request.PromoCode != "" {
+		return uuid.Nil, custom_errors.NewServiceBadRequestError("Invalid rent", "a promo code cannot be applied to a hold")
+	}
+	if request.HoldID == nil {
+		if err := s.validateGuests(request.HotelID, request.RoomTypeID, request); err != nil {
+			return uuid.Nil, err
+		}
+		var discount *requests.QuoteDiscount
+		if request.PromoCode != "" {
+			promoCode, discount, err = s.resolvePromoCode(request)
+			if err != nil {
+				return uuid.Nil, err
+			}
+		}
+		quote, err = s.pricingService.Quote(requests.QuoteRequest{
+			HotelID:      request.HotelID,
+			RoomTypeID:   request.RoomTypeID,
+			CheckInDate:  request.CheckInDate,
+			CheckOutDate: request.CheckOutDate,
+			Discount:     discount,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var createdRent *responses.GetRentResponse
+	if request.HoldID != nil {
+		createdRent, err = s.convertHold(tx, *request.HoldID, request, caller.Id)
+	} else {
+		createdRent, err = insertRent(tx, request, caller.Id, quote)
+	}
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to fetch user data for notification: %w", err)
+		return uuid.Nil, err
+	}
+	if promoCode != nil {
+		if err := redeemPromoCode(tx, promoCode, createdRent.ID, quote.Discount); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	if idempotencyKey != "" {
+		stored, err := storeIdempotencyKey(tx, idempotencyKey, caller.Id, fingerprint, createdRent.ID, s.idempotencyCfg.KeyTTL)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		// A concurrent retry has won, its rent is replayed and this one is rolled back
+		if !stored {
+			tx.Rollback()
+			rentID, err := s.findIdempotentRent(idempotencyKey, caller.Id, fingerprint)
+			if err != nil {
+				return uuid.Nil, err
+			}
+			if rentID == nil {
+				return uuid.Nil, fmt.Errorf("idempotency key %s is locked by another request", idempotencyKey)
+			}
+			return *rentID, nil
+		}
 	}
 
-	var rentID uuid.UUID
+	notificationData := &notification_service.NotificationData{
+		EventType:       notification_service.EventBookingCreated,
+		UserContactData: caller,
+		RentData:        createdRent,
+	}
+	if err := enqueueNotification(tx, notificationData); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isExclusionViolation(err) {
+			return uuid.Nil, newNoRoomsAvailableError()
+		}
+		return uuid.Nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return createdRent.ID, nil
+}
+
+// insertRent books a free room of the requested type for the stay at the quoted price
+func insertRent(tx *sql.Tx, request requests.CreateRentRequest, clientID uuid.UUID, quote *responses.QuoteResponse) (*responses.GetRentResponse, error) {
+	// The free room is picked and booked in one statement, the exclusion constraint
+	// on bookings rejects the insert if a concurrent request took the same room
+	createdRent := &responses.GetRentResponse{
+		HotelID:      request.HotelID,
+		ClientID:     clientID,
+		NightPrice:   quote.NightPrice,
+		Nights:       quote.Nights,
+		Currency:     quote.Currency,
+		TotalAmount:  quote.Total,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+		Status:       StatusPending,
+		// Set by the default of the column, the rent is paid after it is booked
+		PaymentStatus: PaymentStatusUnpaid,
+	}
+	copyGuests(createdRent, request)
 	query := `
-        INSERT INTO bookings (hotel_id, client_id, check_in_date, check_out_date)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id`
+        INSERT INTO bookings (hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date,
+                              night_price, nights, currency, total_amount, taxes, service_fee,
+                              adults, children, primary_guest_name, additional_guests)
+        SELECT r.hotel_id, r.id, r.room_type_id, $2, $3, $4, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+        FROM rooms r
+        WHERE r.hotel_id = $1 AND ($5::uuid IS NULL OR r.room_type_id = $5) AND NOT EXISTS (
+            SELECT 1 FROM bookings b
+            WHERE b.room_id = r.id AND b.status NOT IN ('cancelled', 'no_show')
+              AND tstzrange(b.check_in_date, b.check_out_date) && tstzrange($3, $4))
+        LIMIT 1
+        RETURNING id, room_id, room_type_id, created_at`
 
-	err = s.Db.Connection.QueryRow(query, request.HotelID, userData.Id, request.CheckInDate, request.CheckOutDate).Scan(&rentID)
+	err := tx.QueryRow(query, request.HotelID, clientID, request.CheckInDate, request.CheckOutDate,
+		request.RoomTypeID, quote.NightPrice, quote.Nights, quote.Currency, quote.Total, quote.Taxes, quote.Fees,
+		request.Adults, request.Children, request.PrimaryGuestName, pq.Array(guestNames(request.AdditionalGuests))).
+		Scan(&createdRent.ID, &createdRent.RoomID, &createdRent.RoomTypeID, &createdRent.CreatedAt)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to create rent: %w", err)
+		if errors.Is(err, sql.ErrNoRows) || isExclusionViolation(err) {
+			return nil, newNoRoomsAvailableError()
+		}
+		return nil, fmt.Errorf("failed to create rent: %w", err)
 	}
+	return createdRent, nil
+}
 
-	createdRent, err := s.GetRentByID(rentID)
+// UpdateRent moves the rent to other dates or another hotel, the client of the rent is never changed.
+// The rent is updated only if it is still at the version the caller has seen. Returns the stay before
+// and after the change together with the price difference
+func (s *BookingService) UpdateRent(rentID uuid.UUID, request requests.UpdateRentRequest, caller *user_service.UserData, version int) (*responses.RentModificationResponse, error) {
+	slog.Info("Update rent in service")
+	rent, err := s.getRentByID(rentID)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to handle rent creation: %w", err)
+		return nil, fmt.Errorf("failed to handle rent update: %w", err)
+	}
+	if rent == nil {
+		return nil, fmt.Errorf("rent with ID %s not found", rentID)
 	}
+	if err := s.authorizeRent(caller, rent); err != nil {
+		return nil, err
+	}
+	return s.updateRent(rent, request, caller, version)
+}
 
-	notificationData := &notification_service.NotificationData{UserContactData: userData, RentData: createdRent}
-	s.notificationServiceBridge.SendNotification(context.Background(), notificationData)
+// PatchRent changes only the fields present in the JSON merge patch and returns the updated rent,
+// or nil if the rent does not exist. The merged rent is validated the same way as a full update
+func (s *BookingService) PatchRent(rentID uuid.UUID, patch []byte, caller *user_service.UserData, version int) (*responses.GetRentResponse, error) {
+	slog.Info("Patching rent in service")
+	rent, err := s.getRentByID(rentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to handle rent patch: %w", err)
+	}
+	if rent == nil {
+		return nil, nil
+	}
+	if err := s.authorizeRent(caller, rent); err != nil {
+		return nil, err
+	}
 
-	return rentID, nil
+	current := requests.UpdateRentRequest{
+		HotelID:      rent.HotelID,
+		CheckInDate:  rent.CheckInDate,
+		CheckOutDate: rent.CheckOutDate,
+	}
+	var request requests.UpdateRentRequest
+	if err := applyMergePatch(current, patch, &request); err != nil {
+		return nil, err
+	}
+	if _, err := s.updateRent(rent, request, caller, version); err != nil {
+		return nil, err
+	}
+	return s.getRentByID(rentID)
 }
 
-func (s *BookingService) UpdateRent(rentID uuid.UUID, request requests.UpdateRentRequest) error {
-	slog.Info("Update rent in service")
+// updateRent re-prices the changed stay the way a new rent is priced and compares it with the stored price.
+// A change which raises the price is applied only if the caller has confirmed the exact difference
+func (s *BookingService) updateRent(rent *responses.GetRentResponse, request requests.UpdateRentRequest, caller *user_service.UserData, version int) (*responses.RentModificationResponse, error) {
+	rentID := rent.ID
+	if rent.Version != version {
+		return nil, newVersionMismatchError(rent.Version)
+	}
+	if rent.Status == StatusBlocked {
+		return nil, custom_errors.NewServiceBadRequestError("Rent cannot be changed",
+			"a blocked rent follows the calendar of its channel")
+	}
+	if IsFinalStatus(rent.Status) {
+		return nil, custom_errors.NewServiceBadRequestError("Rent cannot be changed",
+			"the rent is already "+rent.Status)
+	}
+	if request.HotelID == uuid.Nil {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid rent", "hotel ID is required")
+	}
+	if !request.CheckOutDate.After(request.CheckInDate) {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid rent", "check-out date must be after check-in date")
+	}
+	// A room type belongs to one hotel, so the rent of a room type cannot be moved to another hotel
+	if rent.RoomTypeID != nil && request.HotelID != rent.HotelID {
+		return nil, custom_errors.NewServiceBadRequestError("Rent cannot be moved",
+			"a rent of a room type stays in the hotel of the room type, book the other hotel instead")
+	}
+	// Staff of one hotel cannot move the rent to a hotel of somebody else
+	if rent.ClientID != caller.Id && request.HotelID != rent.HotelID {
+		if err := s.authorizeHotel(caller, request.HotelID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The rent keeps its room type, a rent without one gets a room without one. The current room is kept
+	// if it is still free for the new dates
+	roomQuery := `
+		SELECT r.id, r.room_type_id
+		FROM rooms r
+		WHERE r.hotel_id = $2
+		  AND r.room_type_id IS NOT DISTINCT FROM (SELECT room_type_id FROM bookings WHERE id = $1)
+		  AND NOT EXISTS (
+			SELECT 1 FROM bookings b
+			WHERE b.room_id = r.id AND b.id <> $1 AND b.status NOT IN ('cancelled', 'no_show')
+			  AND tstzrange(b.check_in_date, b.check_out_date) && tstzrange($3, $4))
+		ORDER BY r.id = (SELECT room_id FROM bookings WHERE id = $1) DESC
+		LIMIT 1`
+	var roomID uuid.UUID
+	var roomTypeID *uuid.UUID
+	err = tx.QueryRow(roomQuery, rentID, request.HotelID, request.CheckInDate, request.CheckOutDate).
+		Scan(&roomID, &roomTypeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, newNoRoomsAvailableError()
+		}
+		return nil, fmt.Errorf("failed to update rent: %w", err)
+	}
+
+	// The changed stay is priced at the current price, the promo code of the rent still applies to it
+	// if the stay is long enough for the code
+	redeemed, err := getRedeemedDiscount(tx, rentID)
+	if err != nil {
+		return nil, err
+	}
+	var discount *requests.QuoteDiscount
+	if redeemed != nil && CountNights(request.CheckInDate, request.CheckOutDate) >= redeemed.MinNights {
+		discount = &redeemed.Terms
+	}
+	quote, err := s.pricingService.Quote(requests.QuoteRequest{
+		HotelID:      request.HotelID,
+		RoomTypeID:   roomTypeID,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+		Discount:     discount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	modification := &responses.RentModificationResponse{
+		RentID: rentID,
+		Before: responses.RentStayResponse{
+			HotelID:      rent.HotelID,
+			CheckInDate:  rent.CheckInDate,
+			CheckOutDate: rent.CheckOutDate,
+			Nights:       rent.Nights,
+			TotalAmount:  rent.TotalAmount,
+		},
+		After: responses.RentStayResponse{
+			HotelID:      request.HotelID,
+			CheckInDate:  request.CheckInDate,
+			CheckOutDate: request.CheckOutDate,
+			Nights:       quote.Nights,
+			TotalAmount:  quote.Total,
+		},
+		Currency:        quote.Currency,
+		PriceDifference: quote.Total - rent.TotalAmount,
+		Version:         version + 1,
+	}
+	// A payment is authorized for the price of the rent, so the price is kept once the payment is started
+	if modification.PriceDifference != 0 && !isRepriceable(rent.PaymentStatus) {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Rent cannot be repriced",
+			fmt.Sprintf("the payment of the rent is already %s, the change would alter its price", rent.PaymentStatus))
+	}
+	// The guest confirms the amount shown to them, a price which changed meanwhile has to be confirmed again
+	if modification.PriceDifference > 0 &&
+		(request.ConfirmedPriceDifference == nil || *request.ConfirmedPriceDifference != modification.PriceDifference) {
+		return nil, &PriceDifferenceNotConfirmedError{Modification: modification}
+	}
+
 	query := `
 		UPDATE bookings
-		SET hotel_id = $2, client_id = $3, check_in_date = $4, check_out_date = $5
-		WHERE id = $1`
-	result, err := s.Db.Connection.Exec(query, rentID, request.HotelID, request.ClientID, request.CheckInDate, request.CheckOutDate)
+		SET hotel_id = $2, check_in_date = $3, check_out_date = $4, room_id = $5,
+		    night_price = $6, nights = $7, currency = $8, total_amount = $9, taxes = $10, service_fee = $11,
+		    version = version + 1
+		WHERE id = $1 AND version = $12
+		  AND (total_amount IS NOT DISTINCT FROM $9 OR payment_status IN ('unpaid', 'declined'))`
+	result, err := tx.Exec(query, rentID, request.HotelID, request.CheckInDate, request.CheckOutDate, roomID,
+		quote.NightPrice, quote.Nights, quote.Currency, quote.Total, quote.Taxes, quote.Fees, version)
 	if err != nil {
-		return fmt.Errorf("failed to update rent: %w", err)
+		if isExclusionViolation(err) {
+			return nil, newNoRoomsAvailableError()
+		}
+		return nil, fmt.Errorf("failed to update rent: %w", err)
 	}
+	// The rent was changed by somebody else since it was read, or its payment was started meanwhile
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("rent with ID %s not found", rentID)
+		return nil, custom_errors.NewServicePreconditionFailedError("Rent was modified",
+			"the rent has a newer version or its payment was started")
 	}
-	return nil
+	if redeemed != nil {
+		if err := updateRedeemedDiscount(tx, rentID, quote.Discount); err != nil {
+			return nil, err
+		}
+	}
+
+	stayChanged := request.HotelID != rent.HotelID || !request.CheckInDate.Equal(rent.CheckInDate) ||
+		!request.CheckOutDate.Equal(rent.CheckOutDate)
+	if stayChanged {
+		modifiedRent := *rent
+		modifiedRent.HotelID = request.HotelID
+		modifiedRent.RoomID = roomID
+		modifiedRent.CheckInDate = request.CheckInDate
+		modifiedRent.CheckOutDate = request.CheckOutDate
+		modifiedRent.NightPrice = quote.NightPrice
+		modifiedRent.Nights = quote.Nights
+		modifiedRent.Currency = quote.Currency
+		modifiedRent.TotalAmount = quote.Total
+		modifiedRent.Version = modification.Version
+		notificationData := &notification_service.NotificationData{
+			EventType:       notification_service.EventBookingModified,
+			UserContactData: caller,
+			RentData:        &modifiedRent,
+			Modification:    modification,
+		}
+		if err := enqueueNotification(tx, notificationData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Nights which are no longer booked are offered to the waitlist, the change stands even if it fails
+	nightsReleased := request.HotelID != rent.HotelID || request.CheckInDate.After(rent.CheckInDate) ||
+		request.CheckOutDate.Before(rent.CheckOutDate)
+	if nightsReleased {
+		if _, err := s.waitlistService.OfferFreedCapacity(rent.HotelID); err != nil {
+			slog.Error(fmt.Sprintf("Failed to offer freed capacity to the waitlist: %v", err))
+		}
+	}
+	return modification, nil
+}
+
+// PriceDifferenceNotConfirmedError is returned if a change raises the price of the rent and the caller
+// has not confirmed the difference, the modification tells what the change would cost
+type PriceDifferenceNotConfirmedError struct {
+	Modification *responses.RentModificationResponse
+}
+
+func (e *PriceDifferenceNotConfirmedError) Error() string {
+	return fmt.Sprintf("Price difference must be confirmed: the change costs %d %s more",
+		e.Modification.PriceDifference, e.Modification.Currency)
+}
+
+func newVersionMismatchError(currentVersion int) error {
+	return custom_errors.NewServicePreconditionFailedError("Rent was modified",
+		fmt.Sprintf("the current version of the rent is %d", currentVersion))
 }
 
-func (s *BookingService) GetRentByID(rentID uuid.UUID) (*responses.GetRentResponse, error) {
+// GetRentByID returns the rent if the caller has access to it
+func (s *BookingService) GetRentByID(rentID uuid.UUID, caller *user_service.UserData) (*responses.GetRentResponse, error) {
 	slog.Info("Getting rent by ID in service")
+	rent, err := s.getRentByID(rentID)
+	if err != nil || rent == nil {
+		return nil, err
+	}
+	if err := s.authorizeRent(caller, rent); err != nil {
+		return nil, err
+	}
+	return rent, nil
+}
+
+func (s *BookingService) getRentByID(rentID uuid.UUID) (*responses.GetRentResponse, error) {
 	query := `
-		SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date
+		SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+		       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+		       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
 		FROM bookings b
 		WHERE b.id = $1`
 	row := s.Db.Connection.QueryRow(query, rentID)
 
-	var rent responses.GetRentResponse
-	if err := row.Scan(&rent.ID, &rent.HotelID, &rent.ClientID, &rent.CheckInDate, &rent.CheckOutDate); err != nil {
+	rent, err := s.scanRent(row)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to fetch rent: %w", err)
 	}
+	return rent, nil
+}
 
-	price, err := s.hotelServiceBridge.GetHotelPrice(rent.HotelID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get hotel price: %w", err)
+// GetRents returns a page of the rents matching the filter and accessible to the caller, sorted by the chosen field.
+// The next page starts after the rent the returned cursor points to
+func (s *BookingService) GetRents(filter requests.RentFilter, caller *user_service.UserData) (*responses.GetRentsResponse, error) {
+	slog.Info("Getting rents in service")
+	if err := s.restrictRentFilter(&filter, caller); err != nil {
+		return nil, err
+	}
+	// An owner without hotels has no rents to see
+	if filter.HotelIDs != nil && len(filter.HotelIDs) == 0 {
+		return &responses.GetRentsResponse{Rents: []responses.GetRentResponse{}}, nil
 	}
 
-	rent.NightPrice = price
-	return &rent, nil
-}
+	sortBy, sortOrder, limit := filter.SortBy, filter.SortOrder, filter.Limit
+	if sortBy == "" {
+		sortBy = SortByCheckInDate
+	}
+	if sortOrder == "" {
+		sortOrder = SortOrderAsc
+	}
+	if limit <= 0 {
+		limit = DefaultRentsLimit
+	}
+	if !IsValidSortBy(sortBy) || !IsValidSortOrder(sortOrder) || limit > MaxRentsLimit {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid pagination parameters", "")
+	}
+	condition, params, counter, err := rentFilterCondition(filter)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *BookingService) GetRents(filter requests.RentFilter) (*responses.GetRentsResponse, error) {
-	slog.Info("Getting rents in service")
 	query := `
-		SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date
+		SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+		       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+		       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
 		FROM bookings b
-		WHERE 1=1`
+		WHERE 1=1` + condition
+
+	// sortBy and sortOrder are checked above, so they are safe to put into the query
+	comparison := ">"
+	if sortOrder == SortOrderDesc {
+		comparison = "<"
+	}
+	if filter.Cursor != "" {
+		cursor, err := decodeRentCursor(filter.Cursor, sortBy, sortOrder)
+		if err != nil {
+			return nil, err
+		}
+		query += fmt.Sprintf(" AND (b.%s, b.id) %s ($%d, $%d)", sortBy, comparison, counter, counter+1)
+		params = append(params, cursor.Value, cursor.ID)
+		counter += 2
+	}
+
+	// One more rent is fetched to know if there is a next page
+	query += fmt.Sprintf(" ORDER BY b.%s %s, b.id %s LIMIT $%d", sortBy, sortOrder, sortOrder, counter)
+	params = append(params, limit+1)
+
+	rows, err := s.Db.Connection.Query(query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve rents: %w", err)
+	}
+	defer rows.Close()
+
+	var page []*responses.GetRentResponse
+	snapshotted := map[*responses.GetRentResponse]bool{}
+	for rows.Next() {
+		rent, hasSnapshot, err := scanRentRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rent: %w", err)
+		}
+		page = append(page, rent)
+		snapshotted[rent] = hasSnapshot
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rents: %w", err)
+	}
+
+	hasNextPage := len(page) > limit
+	if hasNextPage {
+		page = page[:limit]
+	}
+
+	var legacyRents []*responses.GetRentResponse
+	for _, rent := range page {
+		if !snapshotted[rent] {
+			legacyRents = append(legacyRents, rent)
+		}
+	}
+	if len(legacyRents) > 0 {
+		if err := s.priceLegacyRents(legacyRents); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &responses.GetRentsResponse{Rents: make([]responses.GetRentResponse, 0, len(page))}
+	for _, rent := range page {
+		response.Rents = append(response.Rents, *rent)
+	}
+
+	if hasNextPage {
+		last := page[len(page)-1]
+		cursor := rentCursor{SortBy: sortBy, SortOrder: sortOrder, Value: last.CheckInDate, ID: last.ID}
+		if sortBy == SortByCreatedAt {
+			cursor.Value = last.CreatedAt
+		}
+		nextCursor, err := encodeRentCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		response.NextCursor = &nextCursor
+	}
+	return response, nil
+}
 
+// rentFilterCondition renders the filter as the conditions of the WHERE clause over the bookings aliased as b.
+// It returns the parameters of the conditions and the number of the next parameter
+func rentFilterCondition(filter requests.RentFilter) (string, []interface{}, int, error) {
+	dateMode := filter.DateMode
+	if dateMode == "" {
+		dateMode = DateModeOverlaps
+	}
+	if !IsValidDateMode(dateMode) {
+		return "", nil, 0, custom_errors.NewServiceBadRequestError("Invalid date mode", dateMode)
+	}
+
+	query := ""
 	params := []interface{}{}
 	counter := 1
 
 	if filter.ClientID != uuid.Nil {
-		query += fmt.Sprintf(" AND b.client_id = $%d", counter)
+		// The blocks of the channels are stored with the owner as their client, they are not stays of the owner
+		query += fmt.Sprintf(" AND b.client_id = $%d AND b.status <> 'blocked'", counter)
 		params = append(params, filter.ClientID)
 		counter++
 	}
@@ -134,44 +639,341 @@ func (s *BookingService) GetRents(filter requests.RentFilter) (*responses.GetRen
 		counter++
 	}
 
-	if filter.FromDate != nil {
-		query += fmt.Sprintf(" AND b.check_in_date >= $%d", counter)
-		params = append(params, filter.FromDate)
+	if filter.HotelIDs != nil {
+		query += fmt.Sprintf(" AND b.hotel_id = ANY($%d)", counter)
+		params = append(params, pq.Array(filter.HotelIDs))
 		counter++
 	}
 
-	if filter.ToDate != nil {
-		query += fmt.Sprintf(" AND b.check_out_date <= $%d", counter)
-		params = append(params, filter.ToDate)
+	if filter.FromDate != nil || filter.ToDate != nil {
+		var condition string
+		condition, params, counter = rentDateCondition(dateMode, filter.FromDate, filter.ToDate, params, counter)
+		query += condition
+	}
+
+	if filter.Status != "" {
+		query += fmt.Sprintf(" AND b.status = $%d", counter)
+		params = append(params, filter.Status)
 		counter++
 	}
+	return query, params, counter, nil
+}
 
-	rows, err := s.Db.Connection.Query(query, params...)
+func (s *BookingService) CancelRent(rentID uuid.UUID, request requests.CancelRentRequest, caller *user_service.UserData) (*responses.CancelRentResponse, error) {
+	slog.Info("Cancellation rent in service")
+	rent, err := s.getRentByID(rentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve rents: %w", err)
+		return nil, fmt.Errorf("failed to handle rent cancellation: %w", err)
+	}
+	if rent == nil {
+		return nil, nil
+	}
+	if err := s.authorizeRent(caller, rent); err != nil {
+		return nil, err
 	}
-	defer rows.Close()
 
-	var rents []responses.GetRentResponse
-	for rows.Next() {
-		var rent responses.GetRentResponse
-		if err := rows.Scan(&rent.ID, &rent.HotelID, &rent.ClientID, &rent.CheckInDate, &rent.CheckOutDate); err != nil {
-			return nil, fmt.Errorf("failed to scan rent: %w", err)
+	cancelledAt := time.Now()
+	if !rent.CheckOutDate.After(cancelledAt) {
+		return nil, custom_errors.NewServiceBadRequestError("Rent cannot be cancelled", "the stay has already ended")
+	}
+
+	policy, err := s.cancellationPolicyService.GetPolicy(rent.HotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cancellation policy: %w", err)
+	}
+
+	totalAmount := rent.TotalAmount
+	// The penalty nights are priced without the discount of a promo code, so the penalty is capped at the price paid
+	penaltyAmount := min(CalculateCancellationPenalty(policy, rent.CheckInDate, rent.Nights, rent.NightPrice, cancelledAt),
+		totalAmount)
+	cancellation := &responses.CancelRentResponse{
+		RentID:        rentID,
+		CancelledAt:   cancelledAt,
+		CancelledBy:   caller.Id,
+		Reason:        request.Reason,
+		TotalAmount:   totalAmount,
+		PenaltyAmount: penaltyAmount,
+		RefundAmount:  totalAmount - penaltyAmount,
+	}
+
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transition, err := changeStatus(tx, rentID, StatusCancelled, caller.Id, request.Reason)
+	if err != nil || transition == nil {
+		return nil, err
+	}
+
+	// A paid rent is refunded by the refund relay once the cancellation is committed
+	query := `
+		UPDATE bookings
+		SET cancelled_at = $2, cancelled_by = $3, cancellation_reason = $4, refund_amount = $5,
+		    payment_status = CASE WHEN payment_status IN ($6, $7) THEN $8 ELSE payment_status END
+		WHERE id = $1`
+	_, err = tx.Exec(query, rentID, cancellation.CancelledAt, cancellation.CancelledBy,
+		cancellation.Reason, cancellation.RefundAmount,
+		PaymentStatusAuthorized, PaymentStatusCaptured, PaymentStatusRefundPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel rent: %w", err)
+	}
+
+	rent.Status = StatusCancelled
+	notificationData := &notification_service.NotificationData{
+		EventType:       notification_service.EventBookingCancelled,
+		UserContactData: caller,
+		RentData:        rent,
+		Cancellation:    cancellation,
+		Transition:      transition,
+	}
+	if err := enqueueNotification(tx, notificationData); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// The room of the rent is free now, the cancellation stands even if the waitlist cannot be offered it
+	if _, err := s.waitlistService.OfferFreedCapacity(rent.HotelID); err != nil {
+		slog.Error(fmt.Sprintf("Failed to offer freed capacity to the waitlist: %v", err))
+	}
+
+	return cancellation, nil
+}
+
+// TransitionRent moves the rent through its lifecycle, only the staff of the hotel may do it
+func (s *BookingService) TransitionRent(rentID uuid.UUID, request requests.TransitionRentRequest, caller *user_service.UserData) (*responses.RentTransitionResponse, error) {
+	slog.Info("Transition rent in service")
+	if !IsValidStatus(request.Status) {
+		return nil, custom_errors.NewServiceBadRequestError("Unknown rent status", request.Status)
+	}
+	// Cancellation also settles the refund, so it has its own endpoint
+	if request.Status == StatusCancelled {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid status transition",
+			"rents are cancelled through the cancellation endpoint")
+	}
+
+	rent, err := s.getRentByID(rentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to handle rent transition: %w", err)
+	}
+	if rent == nil {
+		return nil, nil
+	}
+	if err := s.authorizeHotel(caller, rent.HotelID); err != nil {
+		return nil, err
+	}
+	// Rents are confirmed only when the money is secured, which normally happens as soon as the payment is authorized
+	if request.Status == StatusConfirmed && !IsPaymentAuthorized(rent.PaymentStatus) {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Rent is not paid",
+			"the payment of the rent must be authorized before the rent is confirmed")
+	}
+
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transition, err := changeStatus(tx, rentID, request.Status, caller.Id, request.Reason)
+	if err != nil || transition == nil {
+		return nil, err
+	}
+
+	rent.Status = transition.ToStatus
+	notificationData := &notification_service.NotificationData{
+		EventType:       notification_service.EventBookingStatusChanged,
+		UserContactData: caller,
+		RentData:        rent,
+		Transition:      transition,
+	}
+	if err := enqueueNotification(tx, notificationData); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return transition, nil
+}
+
+// changeStatus moves the rent to the given status within the transaction and records the transition.
+// Returns nil if the rent does not exist
+func changeStatus(tx *sql.Tx, rentID uuid.UUID, toStatus string, changedBy uuid.UUID, reason string) (*responses.RentTransitionResponse, error) {
+	var fromStatus string
+	err := tx.QueryRow(`SELECT status FROM bookings WHERE id = $1 FOR UPDATE`, rentID).Scan(&fromStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
 		}
-		rents = append(rents, rent)
+		return nil, fmt.Errorf("failed to fetch rent status: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate over rents: %w", err)
+	if !CanTransition(fromStatus, toStatus) {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid status transition",
+			fmt.Sprintf("rent cannot move from %s to %s", fromStatus, toStatus))
+	}
+
+	if _, err := tx.Exec(`UPDATE bookings SET status = $2, version = version + 1 WHERE id = $1`, rentID, toStatus); err != nil {
+		return nil, fmt.Errorf("failed to update rent status: %w", err)
+	}
+
+	transition := responses.RentTransitionResponse{
+		RentID:     rentID,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	}
+	query := `
+		INSERT INTO booking_status_transitions (booking_id, from_status, to_status, changed_by, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, changed_at`
+	err = tx.QueryRow(query, rentID, fromStatus, toStatus, changedBy, reason).Scan(&transition.ID, &transition.ChangedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record rent transition: %w", err)
+	}
+	return &transition, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRent reads a rent selected with its price snapshot. Rents created before
+// the snapshot was introduced have no stored price and are priced at the current one
+func (s *BookingService) scanRent(row rowScanner) (*responses.GetRentResponse, error) {
+	rent, snapshotted, err := scanRentRow(row)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotted {
+		return rent, nil
+	}
+
+	price, err := s.getCurrentNightPrice(rent.HotelID, rent.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	setCurrentPrice(rent, price)
+	return rent, nil
+}
+
+// scanRentRow reads a rent and reports whether it has a stored price snapshot
+func scanRentRow(row rowScanner) (*responses.GetRentResponse, bool, error) {
+	var rent responses.GetRentResponse
+	var nightPrice, nights, totalAmount sql.NullInt64
+	var currency sql.NullString
+	err := row.Scan(&rent.ID, &rent.HotelID, &rent.RoomID, &rent.RoomTypeID, &rent.ClientID, &rent.CheckInDate,
+		&rent.CheckOutDate, &rent.Status, &nightPrice, &nights, &currency, &totalAmount, &rent.CreatedAt,
+		&rent.Version, &rent.Adults, &rent.Children, &rent.PrimaryGuestName, pq.Array(&rent.AdditionalGuests),
+		&rent.PaymentStatus)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !nightPrice.Valid {
+		return &rent, false, nil
+	}
+	rent.NightPrice = int(nightPrice.Int64)
+	rent.Nights = int(nights.Int64)
+	rent.Currency = currency.String
+	rent.TotalAmount = int(totalAmount.Int64)
+	return &rent, true, nil
+}
+
+// setCurrentPrice prices a rent without a snapshot at the given current night price
+func setCurrentPrice(rent *responses.GetRentResponse, price int) {
+	rent.NightPrice = price
+	rent.Nights = CountNights(rent.CheckInDate, rent.CheckOutDate)
+	rent.Currency = DefaultCurrency
+	rent.TotalAmount = rent.Nights * price
+}
+
+// priceLegacyRents prices the rents of a page which have no snapshot. Hotel prices are fetched
+// in one call for the whole page, room type prices once per room type
+func (s *BookingService) priceLegacyRents(rents []*responses.GetRentResponse) error {
+	var hotelIDs []uuid.UUID
+	seenHotels := map[uuid.UUID]bool{}
+	for _, rent := range rents {
+		if !seenHotels[rent.HotelID] {
+			seenHotels[rent.HotelID] = true
+			hotelIDs = append(hotelIDs, rent.HotelID)
+		}
+	}
+
+	hotelPrices, err := s.hotelServiceBridge.GetHotelPrices(hotelIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get hotel prices: %w", err)
 	}
 
-	for i := range rents {
-		price, err := s.hotelServiceBridge.GetHotelPrice(rents[i].HotelID)
+	roomTypePrices := map[uuid.UUID]int{}
+	for _, rent := range rents {
+		if rent.RoomTypeID != nil {
+			price, ok := roomTypePrices[*rent.RoomTypeID]
+			if !ok {
+				roomType, err := s.hotelServiceBridge.GetRoomType(rent.HotelID, *rent.RoomTypeID)
+				if err != nil {
+					return fmt.Errorf("failed to get room type price: %w", err)
+				}
+				// A removed room type falls back to the hotel price
+				price = -1
+				if roomType != nil {
+					price = roomType.NightPrice
+				}
+				roomTypePrices[*rent.RoomTypeID] = price
+			}
+			if price >= 0 {
+				setCurrentPrice(rent, price)
+				continue
+			}
+		}
+
+		price, ok := hotelPrices[rent.HotelID]
+		if !ok {
+			return fmt.Errorf("failed to get hotel price: hotel %s not found", rent.HotelID)
+		}
+		setCurrentPrice(rent, price)
+	}
+	return nil
+}
+
+// getCurrentNightPrice returns the current price of the room type or the hotel price if no room type is given
+func (s *BookingService) getCurrentNightPrice(hotelID uuid.UUID, roomTypeID *uuid.UUID) (int, error) {
+	if roomTypeID != nil {
+		roomType, err := s.hotelServiceBridge.GetRoomType(hotelID, *roomTypeID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get hotel price: %w", err)
+			return 0, fmt.Errorf("failed to get room type price: %w", err)
+		}
+		if roomType != nil {
+			return roomType.NightPrice, nil
 		}
-		rents[i].NightPrice = price
 	}
 
-	return &responses.GetRentsResponse{Rents: rents}, nil
+	price, err := s.hotelServiceBridge.GetHotelPrice(hotelID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get hotel price: %w", err)
+	}
+	return price, nil
+}
+
+func newNoRoomsAvailableError() error {
+	return custom_errors.NewServiceBadRequestError("No rooms available", "all rooms are booked for the requested dates")
+}
+
+// isRepriceable reports whether the price of a rent may still change, it may not once a payment was started for it
+func isRepriceable(paymentStatus string) bool {
+	return paymentStatus == PaymentStatusUnpaid || paymentStatus == PaymentStatusDeclined
+}
+
+// isExclusionViolation reports whether the error was raised by an exclusion constraint,
+// which happens when two bookings of the same room overlap
+func isExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23P01"
 }
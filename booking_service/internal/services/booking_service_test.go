@@ -1,18 +1,24 @@
 package services_test
 
 import (
+	"booking_service/internal/config"
 	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
 	"booking_service/internal/rest/dtos/requests"
 	"booking_service/internal/rest/dtos/responses"
-	"booking_service/internal/service_interaction/notification_service"
+	"booking_service/internal/service_interaction/hotel_service"
 	"booking_service/internal/service_interaction/user_service"
 	"booking_service/internal/services"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -32,10 +38,6 @@ type MockHotelServiceBridge struct {
 	mock.Mock
 }
 
-type MockUserServiceBridge struct {
-	mock.Mock
-}
-
 type MockNotificationServiceBridge struct {
 	mock.Mock
 }
@@ -45,6 +47,16 @@ func (m *MockHotelServiceBridge) GetHotelPrice(hotelID uuid.UUID) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockHotelServiceBridge) GetHotelPrices(hotelIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	args := m.Called(hotelIDs)
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
+func (m *MockHotelServiceBridge) GetRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID) (*hotel_service.RoomTypeData, error) {
+	args := m.Called(hotelID, roomTypeID)
+	return args.Get(0).(*hotel_service.RoomTypeData), args.Error(1)
+}
+
 func (m *MockHotelServiceBridge) SendKafkaMessage(hotelID uuid.UUID) error {
 	args := m.Called(hotelID)
 	return args.Error(0)
@@ -55,12 +67,61 @@ func (m *MockHotelServiceBridge) ReceiveKafkaMessage(hotelID uuid.UUID) (int, er
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockHotelServiceBridge) GetHotel(hotelID uuid.UUID) (*hotel_service.HotelData, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(*hotel_service.HotelData), args.Error(1)
+}
+
+func (m *MockHotelServiceBridge) GetHotelsByAdministrator(administratorID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(administratorID)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockNotificationServiceBridge) SendMessage(ctx context.Context, message []byte) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+type MockCancellationPolicyService struct {
+	mock.Mock
+}
+
+func (m *MockCancellationPolicyService) SetPolicy(hotelID uuid.UUID, request requests.SetCancellationPolicyRequest, caller *user_service.UserData) error {
+	args := m.Called(hotelID, request, caller)
+	return args.Error(0)
+}
+
+func (m *MockCancellationPolicyService) GetPolicy(hotelID uuid.UUID) (*responses.GetCancellationPolicyResponse, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(*responses.GetCancellationPolicyResponse), args.Error(1)
+}
+
+type MockUserServiceBridge struct {
+	mock.Mock
+}
+
 func (m *MockUserServiceBridge) GetUserContactData(token string) (*user_service.UserData, error) {
 	args := m.Called(token)
-	return args.Get(0).(*user_service.UserData), nil
+	return args.Get(0).(*user_service.UserData), args.Error(1)
+}
+
+func (m *MockUserServiceBridge) GetUserContactDataByID(userID uuid.UUID) (*user_service.UserData, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*user_service.UserData), args.Error(1)
+}
+
+type MockWaitlistService struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistService) JoinWaitlist(request requests.JoinWaitlistRequest, caller *user_service.UserData) (*responses.WaitlistEntryResponse, error) {
+	args := m.Called(request, caller)
+	return args.Get(0).(*responses.WaitlistEntryResponse), args.Error(1)
 }
 
-func (m *MockNotificationServiceBridge) SendNotification(ctx context.Context, notificationData *notification_service.NotificationData) {
+func (m *MockWaitlistService) OfferFreedCapacity(hotelID uuid.UUID) (int, error) {
+	args := m.Called(hotelID)
+	return args.Int(0), args.Error(1)
 }
 
 // endregion
@@ -70,11 +131,12 @@ func TestCreateRent_CommonCase_Ok(t *testing.T) {
 	defer db.Close()
 
 	bridgeMock := &MockHotelServiceBridge{}
-	userBridgeMock := &MockUserServiceBridge{}
 
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, bridgeMock, userBridgeMock,
-		&MockNotificationServiceBridge{})
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 	rentID := uuid.New()
 	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
@@ -84,23 +146,61 @@ func TestCreateRent_CommonCase_Ok(t *testing.T) {
 	nightPrice := 100000
 
 	userId := uuid.New()
-	token := "token"
-	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, client_id, check_in_date, check_out_date\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
-		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(rentID))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, night_price, nights, currency, total_amount, taxes, service_fee, adults, children, primary_guest_name, additional_guests\) SELECT r.hotel_id, r.id, r.room_type_id, \$2, \$3, \$4, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13, \$14, \$15 FROM rooms r`).
+		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).AddRow(rentID, uuid.New(), nil, time.Now()))
 
 	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
-	userBridgeMock.On("GetUserContactData", token).Return(&user_service.UserData{Id: userId})
+	caller := &user_service.UserData{Id: userId}
+	expectOutbox(mock, "booking_created")
+	mock.ExpectCommit()
 
-	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b").
-		WithArgs(rentID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "client_id", "check_in_date", "check_out_date"}).
-			AddRow(rentID, request.HotelID, userId, request.CheckInDate, request.CheckOutDate))
+	id, err := bookingService.CreateRent(request, caller, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, rentID, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_StoresQuotedPrice(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	pricingService := services.NewPricingService(bridgeMock, &config.PricingConfig{TaxPercent: 10, ServiceFee: 100_00})
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{}, pricingService,
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rentID := uuid.New()
+	userId := uuid.New()
+	now := time.Now()
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  now,
+		CheckOutDate: now.Add(48 * time.Hour),
+	}
+	nightPrice := 1000_00
+	expectedTotal := 2*nightPrice + 2*nightPrice/10 + 100_00
 
-	id, err := bookingService.CreateRent(request, token)
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 2, services.DefaultCurrency, expectedTotal, 2*nightPrice/10, 100_00, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).AddRow(rentID, uuid.New(), nil, time.Now()))
+	expectOutbox(mock, "booking_created")
+	mock.ExpectCommit()
+
+	quote, err := pricingService.Quote(requests.QuoteRequest{HotelID: request.HotelID, CheckInDate: request.CheckInDate, CheckOutDate: request.CheckOutDate})
+	assert.NoError(t, err)
+	id, err := bookingService.CreateRent(request, caller, "")
 
 	assert.NoError(t, err)
 	assert.Equal(t, rentID, id)
+	assert.Equal(t, expectedTotal, quote.Total)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -108,13 +208,14 @@ func TestCreateRent_ErrorCase_DBError(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
-	userBridgeMock := &MockUserServiceBridge{}
+	bridgeMock := &MockHotelServiceBridge{}
 
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, &MockHotelServiceBridge{}, userBridgeMock,
-		&MockNotificationServiceBridge{})
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
-	token := "token"
 	userId := uuid.New()
 	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
@@ -122,275 +223,1988 @@ func TestCreateRent_ErrorCase_DBError(t *testing.T) {
 		CheckOutDate: time.Now().Add(24 * time.Hour),
 	}
 
-	userBridgeMock.On("GetUserContactData", token).Return(&user_service.UserData{Id: userId})
-	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, client_id, check_in_date, check_out_date\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
-		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate).
+	nightPrice := 1000_00
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, night_price, nights, currency, total_amount, taxes, service_fee, adults, children, primary_guest_name, additional_guests\) SELECT r.hotel_id, r.id, r.room_type_id, \$2, \$3, \$4, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13, \$14, \$15 FROM rooms r`).
+		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1, 0, "", sqlmock.AnyArg()).
 		WillReturnError(fmt.Errorf("database error"))
+	mock.ExpectRollback()
 
-	_, err := bookingService.CreateRent(request, token)
+	_, err := bookingService.CreateRent(request, caller, "")
 
 	assert.Error(t, err)
 	assert.Equal(t, "failed to create rent: database error", err.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateRent_Success(t *testing.T) {
+func TestCreateRent_NoFreeRooms_BadRequest(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
+	bridgeMock := &MockHotelServiceBridge{}
+
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
-	rentID := uuid.New()
-	request := requests.UpdateRentRequest{
+	userId := uuid.New()
+	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
-		ClientID:     uuid.New(),
 		CheckInDate:  time.Now(),
 		CheckOutDate: time.Now().Add(24 * time.Hour),
 	}
 
-	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, client_id = \$3, check_in_date = \$4, check_out_date = \$5 WHERE id = \$1`).
-		WithArgs(rentID, request.HotelID, request.ClientID, request.CheckInDate, request.CheckOutDate).
-		WillReturnResult(sqlmock.NewResult(1, 1)) // Simulate 1 row updated
+	nightPrice := 1000_00
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
 
-	err := bookingService.UpdateRent(rentID, request)
+	_, err := bookingService.CreateRent(request, caller, "")
 
-	assert.NoError(t, err)
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateRent_NoRowsAffected(t *testing.T) {
+func TestCreateRent_ConcurrentOverlap_BadRequest(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
+	bridgeMock := &MockHotelServiceBridge{}
+
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
-	rentID := uuid.New()
-	request := requests.UpdateRentRequest{
+	userId := uuid.New()
+	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
-		ClientID:     uuid.New(),
 		CheckInDate:  time.Now(),
 		CheckOutDate: time.Now().Add(24 * time.Hour),
 	}
 
-	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, client_id = \$3, check_in_date = \$4, check_out_date = \$5 WHERE id = \$1`).
-		WithArgs(rentID, request.HotelID, request.ClientID, request.CheckInDate, request.CheckOutDate).
-		WillReturnResult(sqlmock.NewResult(0, 0))
+	nightPrice := 1000_00
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(request.HotelID, userId, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnError(&pq.Error{Code: "23P01"})
+	mock.ExpectRollback()
 
-	err := bookingService.UpdateRent(rentID, request)
+	_, err := bookingService.CreateRent(request, caller, "")
 
 	assert.Error(t, err)
-	assert.Equal(t, fmt.Sprintf("rent with ID %s not found", rentID), err.Error())
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateRent_DBError(t *testing.T) {
+func TestCreateRent_UnknownRoomType_BadRequest(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
+	bridgeMock := &MockHotelServiceBridge{}
+
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
-	rentID := uuid.New()
-	request := requests.UpdateRentRequest{
+	roomTypeID := uuid.New()
+	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
-		ClientID:     uuid.New(),
+		RoomTypeID:   &roomTypeID,
 		CheckInDate:  time.Now(),
 		CheckOutDate: time.Now().Add(24 * time.Hour),
 	}
 
-	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, client_id = \$3, check_in_date = \$4, check_out_date = \$5 WHERE id = \$1`).
-		WithArgs(rentID, request.HotelID, request.ClientID, request.CheckInDate, request.CheckOutDate).
-		WillReturnError(fmt.Errorf("database error"))
+	caller := &user_service.UserData{Id: uuid.New()}
+	bridgeMock.On("GetRoomType", request.HotelID, roomTypeID).Return((*hotel_service.RoomTypeData)(nil), nil)
 
-	err := bookingService.UpdateRent(rentID, request)
+	_, err := bookingService.CreateRent(request, caller, "")
 
 	assert.Error(t, err)
-	assert.Equal(t, "failed to update rent: database error", err.Error())
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRentByID_CommonCase_ReturnRent(t *testing.T) {
+func requestFingerprint(t *testing.T, request requests.CreateRentRequest) string {
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("Error serializing request: %v", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreateRent_NewIdempotencyKey_Stored(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
 	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
+	now := time.Now()
 	rentID := uuid.New()
-	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, bridgeMock, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
-	expectedRent := responses.GetRentResponse{
-		ID:           rentID,
+	userId := uuid.New()
+	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
-		ClientID:     uuid.New(),
-		CheckInDate:  time.Now(),
-		CheckOutDate: time.Now().Add(48 * time.Hour),
-		NightPrice:   1000_00,
+		CheckInDate:  now,
+		CheckOutDate: now.Add(24 * time.Hour),
 	}
+	fingerprint := requestFingerprint(t, request)
 
-	bridgeMock.On("GetHotelPrice", expectedRent.HotelID).Return(expectedRent.NightPrice, nil)
-
-	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b").
-		WithArgs(rentID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "client_id", "check_in_date", "check_out_date"}).
-			AddRow(expectedRent.ID, expectedRent.HotelID, expectedRent.ClientID, expectedRent.CheckInDate, expectedRent.CheckOutDate))
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000, nil)
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectQuery("SELECT k.fingerprint, k.rent_id FROM idempotency_keys k").
+		WithArgs("retry-key", userId).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO bookings").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).AddRow(rentID, uuid.New(), nil, time.Now()))
+	mock.ExpectExec("INSERT INTO idempotency_keys \\(key, client_id, fingerprint, rent_id, expires_at\\)").
+		WithArgs("retry-key", userId, fingerprint, rentID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_created")
+	mock.ExpectCommit()
 
-	rent, err := bookingService.GetRentByID(rentID)
+	id, err := bookingService.CreateRent(request, caller, "retry-key")
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedRent, *rent)
+	assert.Equal(t, rentID, id)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRentByID_BridgeReturnError_ThrowError(t *testing.T) {
+func TestCreateRent_RepeatedIdempotencyKey_Replayed(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
-	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
+	now := time.Now()
 	rentID := uuid.New()
-	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, bridgeMock, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
-	expectedRent := responses.GetRentResponse{
-		ID:           rentID,
+	userId := uuid.New()
+	request := requests.CreateRentRequest{
 		HotelID:      uuid.New(),
-		ClientID:     uuid.New(),
-		CheckInDate:  time.Now(),
-		CheckOutDate: time.Now().Add(48 * time.Hour),
-		NightPrice:   1000_00,
+		CheckInDate:  now,
+		CheckOutDate: now.Add(24 * time.Hour),
 	}
 
-	bridgeMock.On("GetHotelPrice", expectedRent.HotelID).Return(0, errors.New("bridge error"))
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectQuery("SELECT k.fingerprint, k.rent_id FROM idempotency_keys k").
+		WithArgs("retry-key", userId).
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "rent_id"}).AddRow(requestFingerprint(t, request), rentID))
 
-	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b").
-		WithArgs(rentID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "client_id", "check_in_date", "check_out_date"}).
-			AddRow(expectedRent.ID, expectedRent.HotelID, expectedRent.ClientID, expectedRent.CheckInDate, expectedRent.CheckOutDate))
+	id, err := bookingService.CreateRent(request, caller, "retry-key")
 
-	_, err := bookingService.GetRentByID(rentID)
+	assert.NoError(t, err)
+	assert.Equal(t, rentID, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	assert.Error(t, err)
+func TestCreateRent_IdempotencyKeyWithOtherBody_UnprocessableEntity(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	now := time.Now()
+	userId := uuid.New()
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  now,
+		CheckOutDate: now.Add(24 * time.Hour),
+	}
+	otherRequest := request
+	otherRequest.CheckOutDate = now.Add(48 * time.Hour)
+
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectQuery("SELECT k.fingerprint, k.rent_id FROM idempotency_keys k").
+		WithArgs("retry-key", userId).
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "rent_id"}).AddRow(requestFingerprint(t, otherRequest), uuid.New()))
+
+	_, err := bookingService.CreateRent(request, caller, "retry-key")
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRentByID_NotFound(t *testing.T) {
+func TestCreateRent_ConcurrentIdempotentRequest_Replayed(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
+	bridgeMock := &MockHotelServiceBridge{}
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
-	rentID := uuid.New()
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
-	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b WHERE b.id = \$1`).
-		WithArgs(rentID).
+	now := time.Now()
+	firstRentID := uuid.New()
+	userId := uuid.New()
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  now,
+		CheckOutDate: now.Add(24 * time.Hour),
+	}
+	fingerprint := requestFingerprint(t, request)
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000, nil)
+	caller := &user_service.UserData{Id: userId}
+	mock.ExpectQuery("SELECT k.fingerprint, k.rent_id FROM idempotency_keys k").
+		WithArgs("retry-key", userId).
 		WillReturnError(sql.ErrNoRows)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO bookings").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).AddRow(uuid.New(), uuid.New(), nil, time.Now()))
+	mock.ExpectExec("INSERT INTO idempotency_keys").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT k.fingerprint, k.rent_id FROM idempotency_keys k").
+		WithArgs("retry-key", userId).
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "rent_id"}).AddRow(fingerprint, firstRentID))
 
-	rent, err := bookingService.GetRentByID(rentID)
+	id, err := bookingService.CreateRent(request, caller, "retry-key")
 
 	assert.NoError(t, err)
-	assert.Nil(t, rent)
+	assert.Equal(t, firstRentID, id)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRentByID_DBError(t *testing.T) {
+func TestUpdateRent_Success(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
+	bridgeMock := &MockHotelServiceBridge{}
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
 	rentID := uuid.New()
+	nightPrice := 1000_00
+	checkIn := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	confirmed := nightPrice
+	request := requests.UpdateRentRequest{
+		HotelID:                  uuid.New(),
+		CheckInDate:              checkIn,
+		CheckOutDate:             checkIn.AddDate(0, 0, 2),
+		ConfirmedPriceDifference: &confirmed,
+	}
+	rent := responses.GetRentResponse{ID: rentID, HotelID: request.HotelID, ClientID: uuid.New(), Status: services.StatusConfirmed,
+		CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 1), NightPrice: nightPrice, Nights: 1,
+		Currency: services.DefaultCurrency, TotalAmount: nightPrice, Version: 1}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
 
-	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b WHERE b.id = \$1`).
-		WithArgs(rentID).
-		WillReturnError(fmt.Errorf("database error"))
+	roomID := uuid.New()
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(roomID, nil))
+	expectNoRedeemedDiscount(mock, rentID)
+	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, check_in_date = \$3, check_out_date = \$4, room_id = \$5, night_price = \$6, nights = \$7, currency = \$8, total_amount = \$9, taxes = \$10, service_fee = \$11, version = version \+ 1 WHERE id = \$1 AND version = \$12`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate, roomID, nightPrice, 2, services.DefaultCurrency, 2*nightPrice, 0, 0, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1)) // Simulate 1 row updated
+	expectOutbox(mock, "booking_modified")
+	mock.ExpectCommit()
 
-	rent, err := bookingService.GetRentByID(rentID)
+	modification, err := bookingService.UpdateRent(rentID, request, caller, 1)
 
-	assert.Error(t, err)
-	assert.Nil(t, rent)
-	assert.Equal(t, "failed to fetch rent: database error", err.Error())
+	assert.NoError(t, err)
+	assert.Equal(t, nightPrice, modification.Before.TotalAmount)
+	assert.Equal(t, 2*nightPrice, modification.After.TotalAmount)
+	assert.Equal(t, nightPrice, modification.PriceDifference)
+	assert.Equal(t, 2, modification.Version)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRents_WithFullFilter_ReturnFilteredRents(t *testing.T) {
+func TestUpdateRent_HigherPriceNotConfirmed_NothingChanged(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
-	mockBridge := &MockHotelServiceBridge{}
+	bridgeMock := &MockHotelServiceBridge{}
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, mockBridge, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
-
-	clientID := uuid.New()
-	hotelID := uuid.New()
-	fromDate := time.Now().Add(-24 * time.Hour)
-	toDate := time.Now().Add(24 * time.Hour)
-	nightPrice := 1000_00
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	// The guest confirmed the difference shown before the price of the hotel went up
+	confirmed := 1000_00
+	request := requests.UpdateRentRequest{
+		HotelID:                  rent.HotelID,
+		CheckInDate:              rent.CheckInDate,
+		CheckOutDate:             rent.CheckInDate.AddDate(0, 0, 2),
+		ConfirmedPriceDifference: &confirmed,
+	}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
+
+	bridgeMock.On("GetHotelPrice", rent.HotelID).Return(1200_00, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rent.ID, rent.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(rent.RoomID, nil))
+	expectNoRedeemedDiscount(mock, rent.ID)
+	mock.ExpectRollback()
+
+	modification, err := bookingService.UpdateRent(rent.ID, request, caller, rent.Version)
+
+	assert.Nil(t, modification)
+	var unconfirmed *services.PriceDifferenceNotConfirmedError
+	assert.True(t, errors.As(err, &unconfirmed))
+	assert.Equal(t, 2*1200_00-1000_00, unconfirmed.Modification.PriceDifference)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	mockBridge.On("GetHotelPrice", hotelID).Return(nightPrice, nil)
+func TestUpdateRent_PaymentAuthorized_PriceNotChanged(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
 
-	filter := requests.RentFilter{
-		ClientID: clientID,
-		HotelID:  hotelID,
-		FromDate: &fromDate,
-		ToDate:   &toDate,
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	rent.PaymentStatus = services.PaymentStatusAuthorized
+	// The guest confirmed the price of the longer stay, but the payment only covers the booked night
+	confirmed := 1000_00
+	request := requests.UpdateRentRequest{
+		HotelID:                  rent.HotelID,
+		CheckInDate:              rent.CheckInDate,
+		CheckOutDate:             rent.CheckInDate.AddDate(0, 0, 2),
+		ConfirmedPriceDifference: &confirmed,
 	}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
 
-	mockRentID := uuid.New()
-	rows := sqlmock.NewRows([]string{"id", "hotel_id", "client_id", "check_in_date", "check_out_date"}).
-		AddRow(mockRentID, hotelID, clientID, fromDate, toDate)
+	bridgeMock.On("GetHotelPrice", rent.HotelID).Return(1000_00, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rent.ID, rent.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(rent.RoomID, nil))
+	expectNoRedeemedDiscount(mock, rent.ID)
+	mock.ExpectRollback()
 
-	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.hotel_id = \$2 AND b.check_in_date >= \$3 AND b.check_out_date <= \$4`).
-		WithArgs(clientID, hotelID, fromDate, toDate).
-		WillReturnRows(rows)
+	modification, err := bookingService.UpdateRent(rent.ID, request, caller, rent.Version)
+
+	assert.Nil(t, modification)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRent_ShorterStayWithPromoCode_DiscountKeptAndNightsOffered(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
 
-	rents, err := bookingService.GetRents(filter)
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistMock := &MockWaitlistService{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, waitlistMock)
+
+	rent := newAccessTestRent()
+	rent.CheckOutDate = rent.CheckInDate.AddDate(0, 0, 3)
+	rent.Nights = 3
+	rent.TotalAmount = 3 * 900_00
+	request := requests.UpdateRentRequest{
+		HotelID:      rent.HotelID,
+		CheckInDate:  rent.CheckInDate,
+		CheckOutDate: rent.CheckInDate.AddDate(0, 0, 2),
+	}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
+
+	bridgeMock.On("GetHotelPrice", rent.HotelID).Return(1000_00, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rent.ID, rent.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(rent.RoomID, nil))
+	mock.ExpectQuery(`FROM promo_code_redemptions r WHERE r.booking_id = \$1`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "discount_type", "discount_value", "min_nights"}).
+			AddRow("WINTER", services.DiscountTypePercent, 10, 2))
+	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2`).
+		WithArgs(rent.ID, rent.HotelID, request.CheckInDate, request.CheckOutDate, rent.RoomID, 1000_00, 2,
+			services.DefaultCurrency, 2*900_00, 0, 0, rent.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE promo_code_redemptions SET discount_amount = \$2 WHERE booking_id = \$1`).
+		WithArgs(rent.ID, 2*100_00).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_modified")
+	mock.ExpectCommit()
+	waitlistMock.On("OfferFreedCapacity", rent.HotelID).Return(0, nil)
+
+	modification, err := bookingService.UpdateRent(rent.ID, request, caller, rent.Version)
 
 	assert.NoError(t, err)
-	assert.Len(t, rents.Rents, 1)
-	assert.Equal(t, mockRentID, rents.Rents[0].ID)
-	assert.Equal(t, hotelID, rents.Rents[0].HotelID)
-	assert.Equal(t, clientID, rents.Rents[0].ClientID)
-	assert.Equal(t, fromDate, rents.Rents[0].CheckInDate)
-	assert.Equal(t, toDate, rents.Rents[0].CheckOutDate)
-	assert.Equal(t, nightPrice, rents.Rents[0].NightPrice)
+	assert.Equal(t, -900_00, modification.PriceDifference)
+	waitlistMock.AssertExpectations(t)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetRents_WithPartialFilter_ReturnRents(t *testing.T) {
+func TestUpdateRent_CancelledRent_BadRequest(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
 
-	clientID := uuid.New()
-	hotelID := uuid.New()
-	fromDate := time.Now().Add(-24 * time.Hour)
-	toDate := time.Now().Add(24 * time.Hour)
-	nightPrice := 1000_00
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	rent.Status = services.StatusCancelled
+	request := requests.UpdateRentRequest{
+		HotelID:      rent.HotelID,
+		CheckInDate:  rent.CheckInDate,
+		CheckOutDate: rent.CheckOutDate.AddDate(0, 0, 1),
+	}
+	expectRentByID(mock, rent)
+
+	modification, err := bookingService.UpdateRent(rent.ID, request, &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}, rent.Version)
+
+	assert.Nil(t, modification)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRent_RoomTypeToAnotherHotel_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
 
-	mockBridge := &MockHotelServiceBridge{}
 	bookingService := services.NewBookingService(
-		&db2.Database{Connection: db}, mockBridge, &MockUserServiceBridge{},
-		&MockNotificationServiceBridge{})
-	filter := requests.RentFilter{
-		ClientID: uuid.New(),
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	roomTypeID := uuid.New()
+	rent.RoomTypeID = &roomTypeID
+	request := requests.UpdateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  rent.CheckInDate,
+		CheckOutDate: rent.CheckOutDate,
 	}
-	mockRentID := uuid.New()
+	// The room type exists only in the current hotel, so no room is looked for in the other one
+	expectRentByID(mock, rent)
+
+	modification, err := bookingService.UpdateRent(rent.ID, request, &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}, rent.Version)
+
+	assert.Nil(t, modification)
+	var badRequest *custom_errors.ServiceBadRequestError
+	assert.True(t, errors.As(err, &badRequest))
+	assert.Equal(t, "Rent cannot be moved", badRequest.Message)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	mockBridge.On("GetHotelPrice", hotelID).Return(nightPrice, nil)
-	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.client_id, b.check_in_date, b.check_out_date FROM bookings b").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "client_id", "check_in_date", "check_out_date"}).
-			AddRow(mockRentID, hotelID, clientID, fromDate, toDate))
+func TestUpdateRent_NoRowsAffected(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
 
-	rents, err := bookingService.GetRents(filter)
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
 
-	assert.NoError(t, err)
-	assert.Len(t, rents.Rents, 1)
+	rentID := uuid.New()
+	nightPrice := 1000_00
+	request := requests.UpdateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+	}
+	rent := responses.GetRentResponse{ID: rentID, HotelID: request.HotelID, ClientID: uuid.New(), Status: services.StatusConfirmed,
+		TotalAmount: nightPrice, Version: 1}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
+
+	roomID := uuid.New()
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(roomID, nil))
+	expectNoRedeemedDiscount(mock, rentID)
+	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, check_in_date = \$3, check_out_date = \$4, room_id = \$5, night_price = \$6, nights = \$7, currency = \$8, total_amount = \$9, taxes = \$10, service_fee = \$11, version = version \+ 1 WHERE id = \$1 AND version = \$12`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate, roomID, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := bookingService.UpdateRent(rentID, request, caller, 1)
+
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, new(*custom_errors.ServicePreconditionFailedError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRent_DBError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rentID := uuid.New()
+	nightPrice := 1000_00
+	request := requests.UpdateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+	}
+	rent := responses.GetRentResponse{ID: rentID, HotelID: request.HotelID, ClientID: uuid.New(), Status: services.StatusConfirmed,
+		TotalAmount: nightPrice, Version: 1}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
+
+	roomID := uuid.New()
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(roomID, nil))
+	expectNoRedeemedDiscount(mock, rentID)
+	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, check_in_date = \$3, check_out_date = \$4, room_id = \$5, night_price = \$6, nights = \$7, currency = \$8, total_amount = \$9, taxes = \$10, service_fee = \$11, version = version \+ 1 WHERE id = \$1 AND version = \$12`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate, roomID, nightPrice, 1, services.DefaultCurrency, nightPrice, 0, 0, 1).
+		WillReturnError(fmt.Errorf("database error"))
+	mock.ExpectRollback()
+
+	_, err := bookingService.UpdateRent(rentID, request, caller, 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "failed to update rent: database error", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRent_NoFreeRoomForNewDates_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rentID := uuid.New()
+	request := requests.UpdateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+	}
+	rent := responses.GetRentResponse{ID: rentID, HotelID: request.HotelID, ClientID: uuid.New(), Status: services.StatusConfirmed, Version: 1}
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
+
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rentID, request.HotelID, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}))
+	mock.ExpectRollback()
+
+	_, err := bookingService.UpdateRent(rentID, request, caller, 1)
+
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_CommonCase_ReturnRent(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+
+	rentID := uuid.New()
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	expectedRent := responses.GetRentResponse{
+		ID:            rentID,
+		HotelID:       uuid.New(),
+		RoomID:        uuid.New(),
+		ClientID:      uuid.New(),
+		CheckInDate:   time.Now(),
+		CheckOutDate:  time.Now().Add(48 * time.Hour),
+		NightPrice:    1000_00,
+		Nights:        2,
+		Currency:      services.DefaultCurrency,
+		TotalAmount:   2 * 1000_00,
+		Status:        services.StatusConfirmed,
+		CreatedAt:     time.Now(),
+		Version:       1,
+		Adults:        1,
+		PaymentStatus: services.PaymentStatusUnpaid,
+	}
+
+	bridgeMock.On("GetHotelPrice", expectedRent.HotelID).Return(expectedRent.NightPrice, nil)
+
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(expectedRent.ID, expectedRent.HotelID, expectedRent.RoomID, nil, expectedRent.ClientID, expectedRent.CheckInDate, expectedRent.CheckOutDate, expectedRent.Status, nil, nil, nil, nil, expectedRent.CreatedAt, expectedRent.Version, 1, 0, "", nil, "unpaid"))
+
+	rent, err := bookingService.GetRentByID(rentID, &user_service.UserData{Id: expectedRent.ClientID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRent, *rent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_WithRoomType_ReturnRoomTypePrice(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+
+	rentID := uuid.New()
+	roomTypeID := uuid.New()
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	expectedRent := responses.GetRentResponse{
+		ID:            rentID,
+		HotelID:       uuid.New(),
+		RoomID:        uuid.New(),
+		RoomTypeID:    &roomTypeID,
+		ClientID:      uuid.New(),
+		CheckInDate:   time.Now(),
+		CheckOutDate:  time.Now().Add(48 * time.Hour),
+		NightPrice:    2500_00,
+		Nights:        2,
+		Currency:      services.DefaultCurrency,
+		TotalAmount:   2 * 2500_00,
+		Status:        services.StatusConfirmed,
+		CreatedAt:     time.Now(),
+		Version:       1,
+		Adults:        1,
+		PaymentStatus: services.PaymentStatusUnpaid,
+	}
+
+	bridgeMock.On("GetRoomType", expectedRent.HotelID, roomTypeID).
+		Return(&hotel_service.RoomTypeData{ID: roomTypeID, HotelID: expectedRent.HotelID, NightPrice: expectedRent.NightPrice}, nil)
+
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(expectedRent.ID, expectedRent.HotelID, expectedRent.RoomID, roomTypeID, expectedRent.ClientID, expectedRent.CheckInDate, expectedRent.CheckOutDate, expectedRent.Status, nil, nil, nil, nil, expectedRent.CreatedAt, expectedRent.Version, 1, 0, "", nil, "unpaid"))
+
+	rent, err := bookingService.GetRentByID(rentID, &user_service.UserData{Id: expectedRent.ClientID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRent, *rent)
+	bridgeMock.AssertNotCalled(t, "GetHotelPrice", expectedRent.HotelID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_WithPriceSnapshot_ReturnStoredPrice(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	now := time.Now()
+	expectedRent := responses.GetRentResponse{
+		ID:            uuid.New(),
+		HotelID:       uuid.New(),
+		RoomID:        uuid.New(),
+		ClientID:      uuid.New(),
+		CheckInDate:   now,
+		CheckOutDate:  now.Add(72 * time.Hour),
+		NightPrice:    1500_00,
+		Nights:        3,
+		Currency:      services.DefaultCurrency,
+		TotalAmount:   3 * 1500_00,
+		Status:        services.StatusConfirmed,
+		CreatedAt:     time.Now(),
+		Version:       1,
+		Adults:        1,
+		PaymentStatus: services.PaymentStatusUnpaid,
+	}
+
+	expectRentByID(mock, expectedRent)
+
+	rent, err := bookingService.GetRentByID(expectedRent.ID, &user_service.UserData{Id: expectedRent.ClientID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRent, *rent)
+	// The stored price is returned without asking hotel_service
+	bridgeMock.AssertNotCalled(t, "GetHotelPrice", expectedRent.HotelID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_BridgeReturnError_ThrowError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+
+	rentID := uuid.New()
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	expectedRent := responses.GetRentResponse{
+		ID:            rentID,
+		HotelID:       uuid.New(),
+		RoomID:        uuid.New(),
+		ClientID:      uuid.New(),
+		CheckInDate:   time.Now(),
+		CheckOutDate:  time.Now().Add(48 * time.Hour),
+		NightPrice:    1000_00,
+		Status:        services.StatusConfirmed,
+		CreatedAt:     time.Now(),
+		Version:       1,
+		Adults:        1,
+		PaymentStatus: services.PaymentStatusUnpaid,
+	}
+
+	bridgeMock.On("GetHotelPrice", expectedRent.HotelID).Return(0, errors.New("bridge error"))
+
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(expectedRent.ID, expectedRent.HotelID, expectedRent.RoomID, nil, expectedRent.ClientID, expectedRent.CheckInDate, expectedRent.CheckOutDate, expectedRent.Status, nil, nil, nil, nil, expectedRent.CreatedAt, expectedRent.Version, 1, 0, "", nil, "unpaid"))
+
+	_, err := bookingService.GetRentByID(rentID, &user_service.UserData{Id: expectedRent.ClientID})
+
+	assert.Error(t, err)
+}
+
+func TestGetRentByID_NotFound(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	rentID := uuid.New()
+
+	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b WHERE b.id = \$1`).
+		WithArgs(rentID).
+		WillReturnError(sql.ErrNoRows)
+
+	rent, err := bookingService.GetRentByID(rentID, &user_service.UserData{Id: uuid.New()})
+
+	assert.NoError(t, err)
+	assert.Nil(t, rent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_DBError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	rentID := uuid.New()
+
+	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b WHERE b.id = \$1`).
+		WithArgs(rentID).
+		WillReturnError(fmt.Errorf("database error"))
+
+	rent, err := bookingService.GetRentByID(rentID, &user_service.UserData{Id: uuid.New()})
+
+	assert.Error(t, err)
+	assert.Nil(t, rent)
+	assert.Equal(t, "failed to fetch rent: database error", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_WithFullFilter_ReturnFilteredRents(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	mockBridge := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, mockBridge,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(mockBridge, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	clientID := uuid.New()
+	hotelID := uuid.New()
+	fromDate := time.Now().Add(-24 * time.Hour)
+	toDate := time.Now().Add(24 * time.Hour)
+	nightPrice := 1000_00
+
+	mockBridge.On("GetHotelPrices", []uuid.UUID{hotelID}).Return(map[uuid.UUID]int{hotelID: nightPrice}, nil)
+
+	filter := requests.RentFilter{
+		ClientID: clientID,
+		HotelID:  hotelID,
+		FromDate: &fromDate,
+		ToDate:   &toDate,
+	}
+
+	mockRentID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+		AddRow(mockRentID, hotelID, uuid.New(), nil, clientID, fromDate, toDate, services.StatusConfirmed, nil, nil, nil, nil, time.Now(), 1, 1, 0, "", nil, "unpaid")
+
+	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND b.hotel_id = \$2 AND tstzrange\(b.check_in_date, b.check_out_date\) && tstzrange\(\$3::timestamptz, \$4::timestamptz\)`).
+		WithArgs(clientID, hotelID, fromDate, toDate, services.DefaultRentsLimit+1).
+		WillReturnRows(rows)
+
+	rents, err := bookingService.GetRents(filter, &user_service.UserData{Id: clientID})
+
+	assert.NoError(t, err)
+	assert.Len(t, rents.Rents, 1)
+	assert.Equal(t, mockRentID, rents.Rents[0].ID)
+	assert.Equal(t, hotelID, rents.Rents[0].HotelID)
+	assert.Equal(t, clientID, rents.Rents[0].ClientID)
+	assert.Equal(t, fromDate, rents.Rents[0].CheckInDate)
+	assert.Equal(t, toDate, rents.Rents[0].CheckOutDate)
+	assert.Equal(t, nightPrice, rents.Rents[0].NightPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_WithPartialFilter_ReturnRents(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	clientID := uuid.New()
+	hotelID := uuid.New()
+	fromDate := time.Now().Add(-24 * time.Hour)
+	toDate := time.Now().Add(24 * time.Hour)
+	nightPrice := 1000_00
+
+	mockBridge := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, mockBridge,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(mockBridge, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	filter := requests.RentFilter{
+		ClientID: uuid.New(),
+	}
+	mockRentID := uuid.New()
+
+	mockBridge.On("GetHotelPrices", []uuid.UUID{hotelID}).Return(map[uuid.UUID]int{hotelID: nightPrice}, nil)
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(mockRentID, hotelID, uuid.New(), nil, clientID, fromDate, toDate, services.StatusConfirmed, nil, nil, nil, nil, time.Now(), 1, 1, 0, "", nil, "unpaid"))
+
+	rents, err := bookingService.GetRents(filter, &user_service.UserData{Id: filter.ClientID})
+
+	assert.NoError(t, err)
+	assert.Len(t, rents.Rents, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectRentByID(mock sqlmock.Sqlmock, rent responses.GetRentResponse) {
+	paymentStatus := rent.PaymentStatus
+	if paymentStatus == "" {
+		paymentStatus = services.PaymentStatusUnpaid
+	}
+	var roomTypeID any
+	if rent.RoomTypeID != nil {
+		roomTypeID = *rent.RoomTypeID
+	}
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(rent.ID, rent.HotelID, rent.RoomID, roomTypeID, rent.ClientID, rent.CheckInDate, rent.CheckOutDate, rent.Status,
+				rent.NightPrice, rent.Nights, rent.Currency, rent.TotalAmount, rent.CreatedAt, rent.Version, 1, 0, "", nil, paymentStatus))
+}
+
+// newHotelOwner returns the owner administering the hotel
+func newHotelOwner(bridgeMock *MockHotelServiceBridge, hotelID uuid.UUID) *user_service.UserData {
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, AdministratorID: owner.Id}, nil)
+	return owner
+}
+
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectExec(`INSERT INTO notification_outbox \(event_type, payload\) VALUES \(\$1, \$2\)`).
+		WithArgs(eventType, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectNoRedeemedDiscount(mock sqlmock.Sqlmock, rentID uuid.UUID) {
+	mock.ExpectQuery(`FROM promo_code_redemptions r WHERE r.booking_id = \$1`).
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "discount_type", "discount_value", "min_nights"}))
+}
+
+func expectStatusChange(mock sqlmock.Sqlmock, rentID uuid.UUID, fromStatus string, toStatus string, changedBy uuid.UUID, reason string) {
+	mock.ExpectQuery(`SELECT status FROM bookings WHERE id = \$1 FOR UPDATE`).
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(fromStatus))
+	mock.ExpectExec(`UPDATE bookings SET status = \$2, version = version \+ 1 WHERE id = \$1`).
+		WithArgs(rentID, toStatus).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO booking_status_transitions \(booking_id, from_status, to_status, changed_by, reason\)`).
+		WithArgs(rentID, fromStatus, toStatus, changedBy, reason).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "changed_at"}).AddRow(uuid.New(), time.Now()))
+}
+
+func TestCancelRent_BeforeFreeCancellationDeadline_FullRefund(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyServiceMock := &MockCancellationPolicyService{}
+	waitlistMock := &MockWaitlistService{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		policyServiceMock,
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, waitlistMock)
+
+	userID := uuid.New()
+	now := time.Now()
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     userID,
+		CheckInDate:  now.Add(72 * time.Hour),
+		CheckOutDate: now.Add(120 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       2,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  2 * 1000_00,
+		Status:       services.StatusConfirmed,
+	}
+	request := requests.CancelRentRequest{Reason: "Change of plans"}
+
+	caller := &user_service.UserData{Id: userID}
+	policyServiceMock.On("GetPolicy", rent.HotelID).Return(&responses.GetCancellationPolicyResponse{
+		HotelID:               rent.HotelID,
+		FreeCancellationHours: services.DefaultFreeCancellationHours,
+		PenaltyNights:         services.DefaultPenaltyNights,
+	}, nil)
+	waitlistMock.On("OfferFreedCapacity", rent.HotelID).Return(1, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	expectStatusChange(mock, rent.ID, services.StatusConfirmed, services.StatusCancelled, userID, request.Reason)
+	// A paid rent is marked for the refund relay by the same statement
+	mock.ExpectExec(`UPDATE bookings SET cancelled_at = \$2, cancelled_by = \$3, cancellation_reason = \$4, refund_amount = \$5, payment_status = CASE WHEN payment_status IN \(\$6, \$7\) THEN \$8 ELSE payment_status END WHERE id = \$1`).
+		WithArgs(rent.ID, sqlmock.AnyArg(), userID, request.Reason, 2*rent.NightPrice,
+			services.PaymentStatusAuthorized, services.PaymentStatusCaptured, services.PaymentStatusRefundPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_cancelled")
+	mock.ExpectCommit()
+
+	cancellation, err := bookingService.CancelRent(rent.ID, request, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rent.ID, cancellation.RentID)
+	assert.Equal(t, userID, cancellation.CancelledBy)
+	assert.Equal(t, 2*rent.NightPrice, cancellation.TotalAmount)
+	assert.Equal(t, 0, cancellation.PenaltyAmount)
+	assert.Equal(t, 2*rent.NightPrice, cancellation.RefundAmount)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelRent_AfterFreeCancellationDeadline_FirstNightCharged(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyServiceMock := &MockCancellationPolicyService{}
+	waitlistMock := &MockWaitlistService{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		policyServiceMock,
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, waitlistMock)
+
+	userID := uuid.New()
+	now := time.Now()
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     userID,
+		CheckInDate:  now.Add(24 * time.Hour),
+		CheckOutDate: now.Add(96 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       3,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  3 * 1000_00,
+		Status:       services.StatusConfirmed,
+	}
+
+	caller := &user_service.UserData{Id: userID}
+	policyServiceMock.On("GetPolicy", rent.HotelID).Return(&responses.GetCancellationPolicyResponse{
+		HotelID:               rent.HotelID,
+		FreeCancellationHours: services.DefaultFreeCancellationHours,
+		PenaltyNights:         services.DefaultPenaltyNights,
+	}, nil)
+	waitlistMock.On("OfferFreedCapacity", rent.HotelID).Return(1, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	expectStatusChange(mock, rent.ID, services.StatusConfirmed, services.StatusCancelled, userID, "")
+	mock.ExpectExec("UPDATE bookings SET cancelled_at").
+		WithArgs(rent.ID, sqlmock.AnyArg(), userID, "", 2*rent.NightPrice,
+			services.PaymentStatusAuthorized, services.PaymentStatusCaptured, services.PaymentStatusRefundPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_cancelled")
+	mock.ExpectCommit()
+
+	cancellation, err := bookingService.CancelRent(rent.ID, requests.CancelRentRequest{}, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3*rent.NightPrice, cancellation.TotalAmount)
+	assert.Equal(t, rent.NightPrice, cancellation.PenaltyAmount)
+	assert.Equal(t, 2*rent.NightPrice, cancellation.RefundAmount)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelRent_PenaltyAbovePaidPrice_PenaltyCappedAtPaidPrice(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyServiceMock := &MockCancellationPolicyService{}
+	waitlistMock := &MockWaitlistService{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		policyServiceMock,
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, waitlistMock)
+
+	userID := uuid.New()
+	now := time.Now()
+	// A fixed promo code took 1500.00 off the two nights, less than the price of the penalty night is paid
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     userID,
+		CheckInDate:  now.Add(24 * time.Hour),
+		CheckOutDate: now.Add(72 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       2,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  500_00,
+		Status:       services.StatusConfirmed,
+	}
+
+	caller := &user_service.UserData{Id: userID}
+	policyServiceMock.On("GetPolicy", rent.HotelID).Return(&responses.GetCancellationPolicyResponse{
+		HotelID:               rent.HotelID,
+		FreeCancellationHours: services.DefaultFreeCancellationHours,
+		PenaltyNights:         services.DefaultPenaltyNights,
+	}, nil)
+	waitlistMock.On("OfferFreedCapacity", rent.HotelID).Return(0, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	expectStatusChange(mock, rent.ID, services.StatusConfirmed, services.StatusCancelled, userID, "")
+	mock.ExpectExec("UPDATE bookings SET cancelled_at").
+		WithArgs(rent.ID, sqlmock.AnyArg(), userID, "", 0,
+			services.PaymentStatusAuthorized, services.PaymentStatusCaptured, services.PaymentStatusRefundPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_cancelled")
+	mock.ExpectCommit()
+
+	cancellation, err := bookingService.CancelRent(rent.ID, requests.CancelRentRequest{}, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500_00, cancellation.PenaltyAmount)
+	assert.Equal(t, 0, cancellation.RefundAmount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelRent_AlreadyCancelled_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyServiceMock := &MockCancellationPolicyService{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		policyServiceMock,
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	now := time.Now()
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     uuid.New(),
+		CheckInDate:  now.Add(72 * time.Hour),
+		CheckOutDate: now.Add(96 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       1,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  1 * 1000_00,
+		Status:       services.StatusConfirmed,
+	}
+
+	caller := &user_service.UserData{Id: rent.ClientID}
+	policyServiceMock.On("GetPolicy", rent.HotelID).Return(&responses.GetCancellationPolicyResponse{
+		HotelID:               rent.HotelID,
+		FreeCancellationHours: services.DefaultFreeCancellationHours,
+		PenaltyNights:         services.DefaultPenaltyNights,
+	}, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM bookings WHERE id = \$1 FOR UPDATE`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(services.StatusCancelled))
+	mock.ExpectRollback()
+
+	cancellation, err := bookingService.CancelRent(rent.ID, requests.CancelRentRequest{}, caller)
+
+	assert.Nil(t, cancellation)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelRent_StayEnded_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	now := time.Now()
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     uuid.New(),
+		CheckInDate:  now.Add(-72 * time.Hour),
+		CheckOutDate: now.Add(-24 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       2,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  2 * 1000_00,
+		Status:       services.StatusConfirmed,
+	}
+
+	caller := &user_service.UserData{Id: rent.ClientID}
+	expectRentByID(mock, rent)
+
+	cancellation, err := bookingService.CancelRent(rent.ID, requests.CancelRentRequest{}, caller)
+
+	assert.Nil(t, cancellation)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelRent_NotFound(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rentID := uuid.New()
+	caller := &user_service.UserData{Id: uuid.New()}
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WithArgs(rentID).
+		WillReturnError(sql.ErrNoRows)
+
+	cancellation, err := bookingService.CancelRent(rentID, requests.CancelRentRequest{}, caller)
+
+	assert.NoError(t, err)
+	assert.Nil(t, cancellation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionRent_AllowedTransition_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	ownerID := uuid.New()
+	now := time.Now()
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     uuid.New(),
+		CheckInDate:  now,
+		CheckOutDate: now.Add(48 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       2,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  2 * 1000_00,
+		Status:       services.StatusCheckedIn,
+	}
+	request := requests.TransitionRentRequest{Status: services.StatusCheckedIn, Reason: "Guest arrived"}
+
+	caller := &user_service.UserData{Id: ownerID, Role: user_service.RoleOwner}
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: ownerID}, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	expectStatusChange(mock, rent.ID, services.StatusConfirmed, services.StatusCheckedIn, ownerID, request.Reason)
+	expectOutbox(mock, "booking_status_changed")
+	mock.ExpectCommit()
+
+	transition, err := bookingService.TransitionRent(rent.ID, request, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rent.ID, transition.RentID)
+	assert.Equal(t, services.StatusConfirmed, transition.FromStatus)
+	assert.Equal(t, services.StatusCheckedIn, transition.ToStatus)
+	assert.Equal(t, ownerID, transition.ChangedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionRent_ForbiddenTransition_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	now := time.Now()
+	rent := responses.GetRentResponse{
+		ID:           uuid.New(),
+		HotelID:      uuid.New(),
+		RoomID:       uuid.New(),
+		ClientID:     uuid.New(),
+		CheckInDate:  now,
+		CheckOutDate: now.Add(24 * time.Hour),
+		NightPrice:   1000_00,
+		Nights:       1,
+		Currency:     services.DefaultCurrency,
+		TotalAmount:  1000_00,
+		Status:       services.StatusPending,
+	}
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: caller.Id}, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM bookings WHERE id = \$1 FOR UPDATE`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(services.StatusPending))
+	mock.ExpectRollback()
+
+	transition, err := bookingService.TransitionRent(rent.ID, requests.TransitionRentRequest{Status: services.StatusCheckedOut}, caller)
+
+	assert.Nil(t, transition)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionRent_ToCancelled_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+
+	transition, err := bookingService.TransitionRent(uuid.New(), requests.TransitionRentRequest{Status: services.StatusCancelled}, caller)
+
+	assert.Nil(t, transition)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionRent_NotFound(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rentID := uuid.New()
+	caller := &user_service.UserData{Id: uuid.New()}
+	mock.ExpectQuery("SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b").
+		WithArgs(rentID).
+		WillReturnError(sql.ErrNoRows)
+
+	transition, err := bookingService.TransitionRent(rentID, requests.TransitionRentRequest{Status: services.StatusConfirmed}, caller)
+
+	assert.NoError(t, err)
+	assert.Nil(t, transition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_WithStatusFilter_FilterByStatus(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	filter := requests.RentFilter{Status: services.StatusNoShow}
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}
+
+	mock.ExpectQuery(`SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status, b.night_price, b.nights, b.currency, b.total_amount, b.created_at, b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND b.status = \$2`).
+		WithArgs(caller.Id, services.StatusNoShow, services.DefaultRentsLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}))
+
+	rents, err := bookingService.GetRents(filter, caller)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rents.Rents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_WithinDateMode_StayContainedInWindow(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	fromDate := time.Now()
+	filter := requests.RentFilter{FromDate: &fromDate, DateMode: services.DateModeWithin}
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}
+
+	// The window without an end leaves the range unbounded
+	mock.ExpectQuery(`FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND tstzrange\(b.check_in_date, b.check_out_date\) <@ tstzrange\(\$2::timestamptz, \$3::timestamptz\)`).
+		WithArgs(caller.Id, fromDate, nil, services.DefaultRentsLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}))
+
+	rents, err := bookingService.GetRents(filter, caller)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rents.Rents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_StartsInDateMode_CheckInInWindow(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	fromDate := time.Now()
+	toDate := fromDate.Add(7 * 24 * time.Hour)
+	filter := requests.RentFilter{FromDate: &fromDate, ToDate: &toDate, DateMode: services.DateModeStartsIn}
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}
+
+	mock.ExpectQuery(`FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND b.check_in_date >= \$2 AND b.check_in_date < \$3 ORDER BY`).
+		WithArgs(caller.Id, fromDate, toDate, services.DefaultRentsLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}))
+
+	rents, err := bookingService.GetRents(filter, caller)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rents.Rents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_EndsInDateModeWithoutStart_CheckOutBeforeEnd(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	toDate := time.Now()
+	filter := requests.RentFilter{ToDate: &toDate, DateMode: services.DateModeEndsIn}
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}
+
+	mock.ExpectQuery(`FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND b.check_out_date < \$2 ORDER BY`).
+		WithArgs(caller.Id, toDate, services.DefaultRentsLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}))
+
+	rents, err := bookingService.GetRents(filter, caller)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rents.Rents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_InvalidDateMode_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	_, err := bookingService.GetRents(requests.RentFilter{DateMode: "during"}, &user_service.UserData{Id: uuid.New()})
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_MoreRentsThanLimit_ReturnNextCursor(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	clientID := uuid.New()
+	caller := &user_service.UserData{Id: clientID, Role: user_service.RoleGuest}
+	filter := requests.RentFilter{ClientID: clientID, Limit: 1, SortBy: services.SortByCreatedAt, SortOrder: services.SortOrderDesc}
+
+	now := time.Now()
+	firstID, secondID := uuid.New(), uuid.New()
+	mock.ExpectQuery(`FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' ORDER BY b.created_at desc, b.id desc LIMIT \$2`).
+		WithArgs(clientID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(firstID, uuid.New(), uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusPending, 1000, 1, services.DefaultCurrency, 1000, now, 1, 1, 0, "", nil, "unpaid").
+			AddRow(secondID, uuid.New(), uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusPending, 1000, 1, services.DefaultCurrency, 1000, now.Add(-time.Hour), 1, 1, 0, "", nil, "unpaid"))
+
+	rents, err := bookingService.GetRents(filter, caller)
+
+	assert.NoError(t, err)
+	assert.Len(t, rents.Rents, 1)
+	assert.Equal(t, firstID, rents.Rents[0].ID)
+	assert.NotNil(t, rents.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// The cursor continues the list after the last returned rent
+	filter.Cursor = *rents.NextCursor
+	mock.ExpectQuery(`FROM bookings b WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND \(b.created_at, b.id\) < \(\$2, \$3\) ORDER BY b.created_at desc, b.id desc LIMIT \$4`).
+		WithArgs(clientID, sqlmock.AnyArg(), firstID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(secondID, uuid.New(), uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusPending, 1000, 1, services.DefaultCurrency, 1000, now.Add(-time.Hour), 1, 1, 0, "", nil, "unpaid"))
+
+	rents, err = bookingService.GetRents(filter, caller)
+
+	assert.NoError(t, err)
+	assert.Len(t, rents.Rents, 1)
+	assert.Equal(t, secondID, rents.Rents[0].ID)
+	assert.Nil(t, rents.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_CursorOfOtherSort_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	caller := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}
+	now := time.Now()
+	mock.ExpectQuery(`ORDER BY b.check_in_date asc, b.id asc LIMIT \$2`).
+		WithArgs(caller.Id, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusPending, 1000, 1, services.DefaultCurrency, 1000, now, 1, 1, 0, "", nil, "unpaid").
+			AddRow(uuid.New(), uuid.New(), uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusPending, 1000, 1, services.DefaultCurrency, 1000, now, 1, 1, 0, "", nil, "unpaid"))
+	rents, err := bookingService.GetRents(requests.RentFilter{Limit: 1}, caller)
+	assert.NoError(t, err)
+
+	_, err = bookingService.GetRents(requests.RentFilter{Limit: 1, Cursor: *rents.NextCursor, SortBy: services.SortByCreatedAt}, caller)
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_InvalidCursor_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	_, err := bookingService.GetRents(requests.RentFilter{Cursor: "not a cursor"}, &user_service.UserData{Id: uuid.New()})
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_LegacyRents_PricesFetchedInOneCall(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	now := time.Now()
+	hotelID := uuid.New()
+	otherHotelID := uuid.New()
+	bridgeMock.On("GetHotelPrices", []uuid.UUID{hotelID, otherHotelID}).
+		Return(map[uuid.UUID]int{hotelID: 1000_00, otherHotelID: 2000_00}, nil).Once()
+	mock.ExpectQuery("FROM bookings b").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}).
+			AddRow(uuid.New(), hotelID, uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusConfirmed, nil, nil, nil, nil, now, 1, 1, 0, "", nil, "unpaid").
+			AddRow(uuid.New(), otherHotelID, uuid.New(), nil, uuid.New(), now, now.Add(48*time.Hour), services.StatusConfirmed, nil, nil, nil, nil, now, 1, 1, 0, "", nil, "unpaid").
+			AddRow(uuid.New(), hotelID, uuid.New(), nil, uuid.New(), now, now.Add(48*time.Hour), services.StatusConfirmed, nil, nil, nil, nil, now, 1, 1, 0, "", nil, "unpaid"))
+
+	rents, err := bookingService.GetRents(requests.RentFilter{}, &user_service.UserData{Id: uuid.New()})
+
+	assert.NoError(t, err)
+	assert.Len(t, rents.Rents, 3)
+	assert.Equal(t, 2*2000_00, rents.Rents[1].TotalAmount)
+	assert.Equal(t, 2*1000_00, rents.Rents[2].TotalAmount)
+	bridgeMock.AssertNumberOfCalls(t, "GetHotelPrices", 1)
+	bridgeMock.AssertNotCalled(t, "GetHotelPrice", hotelID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func newAccessTestRent() responses.GetRentResponse {
+	now := time.Now()
+	return responses.GetRentResponse{
+		ID:            uuid.New(),
+		HotelID:       uuid.New(),
+		RoomID:        uuid.New(),
+		ClientID:      uuid.New(),
+		CheckInDate:   now.Add(24 * time.Hour),
+		CheckOutDate:  now.Add(48 * time.Hour),
+		NightPrice:    1000_00,
+		Nights:        1,
+		Currency:      services.DefaultCurrency,
+		TotalAmount:   1000_00,
+		Status:        services.StatusConfirmed,
+		CreatedAt:     now,
+		Version:       1,
+		Adults:        1,
+		PaymentStatus: services.PaymentStatusUnpaid,
+	}
+}
+
+func TestGetRentByID_RentOfAnotherClient_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	expectRentByID(mock, rent)
+
+	result, err := bookingService.GetRentByID(rent.ID, &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest})
+
+	assert.Nil(t, result)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_OwnerOfHotel_ReturnRent(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: owner.Id}, nil)
+	expectRentByID(mock, rent)
+
+	result, err := bookingService.GetRentByID(rent.ID, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rent.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRentByID_OwnerOfAnotherHotel_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: uuid.New()}, nil)
+	expectRentByID(mock, rent)
+
+	result, err := bookingService.GetRentByID(rent.ID, owner)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRent_RentOfAnotherClient_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	request := requests.UpdateRentRequest{
+		HotelID:      rent.HotelID,
+		CheckInDate:  rent.CheckInDate,
+		CheckOutDate: rent.CheckOutDate,
+	}
+	expectRentByID(mock, rent)
+
+	_, err := bookingService.UpdateRent(rent.ID, request, &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}, rent.Version)
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRent_BlockedByChannel_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	rent.Status = services.StatusBlocked
+	request := requests.UpdateRentRequest{
+		HotelID:      rent.HotelID,
+		CheckInDate:  rent.CheckInDate.AddDate(0, 0, 1),
+		CheckOutDate: rent.CheckOutDate.AddDate(0, 0, 1),
+	}
+	expectRentByID(mock, rent)
+
+	_, err := bookingService.UpdateRent(rent.ID, request, &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleOwner}, rent.Version)
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionRent_ByGuest_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	expectRentByID(mock, rent)
+
+	// Even the client of the rent cannot check themselves in
+	transition, err := bookingService.TransitionRent(rent.ID, requests.TransitionRentRequest{Status: services.StatusCheckedIn},
+		&user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest})
+
+	assert.Nil(t, transition)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_GuestFilterByAnotherClient_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rents, err := bookingService.GetRents(requests.RentFilter{ClientID: uuid.New()},
+		&user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest})
+
+	assert.Nil(t, rents)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_Owner_RestrictedToAdministeredHotels(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	hotelIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	bridgeMock.On("GetHotelsByAdministrator", owner.Id).Return(hotelIDs, nil)
+	mock.ExpectQuery(`FROM bookings b WHERE 1=1 AND b.hotel_id = ANY\(\$1\) ORDER BY b.check_in_date asc, b.id asc LIMIT \$2`).
+		WithArgs(pq.Array(hotelIDs), services.DefaultRentsLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date", "check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults", "children", "primary_guest_name", "additional_guests", "payment_status"}))
+
+	rents, err := bookingService.GetRents(requests.RentFilter{}, owner)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rents.Rents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRents_OwnerWithoutHotels_ReturnEmptyPage(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	bridgeMock.On("GetHotelsByAdministrator", owner.Id).Return([]uuid.UUID{}, nil)
+
+	rents, err := bookingService.GetRents(requests.RentFilter{}, owner)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rents.Rents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchRent_OnlyCheckOutDate_OtherFieldsKept(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	rent.CheckInDate = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rent.CheckOutDate = time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+	newCheckOutDate := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	caller := &user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}
+	roomID := uuid.New()
+
+	bridgeMock.On("GetHotelPrice", rent.HotelID).Return(1000_00, nil)
+	expectRentByID(mock, rent)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT r.id, r.room_type_id FROM rooms r WHERE r.hotel_id = \$2`).
+		WithArgs(rent.ID, rent.HotelID, rent.CheckInDate, newCheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_type_id"}).AddRow(roomID, nil))
+	expectNoRedeemedDiscount(mock, rent.ID)
+	mock.ExpectExec(`UPDATE bookings SET hotel_id = \$2, check_in_date = \$3, check_out_date = \$4`).
+		WithArgs(rent.ID, rent.HotelID, rent.CheckInDate, newCheckOutDate, roomID, 1000_00, 4, services.DefaultCurrency, 4*1000_00, 0, 0, rent.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_modified")
+	mock.ExpectCommit()
+	patchedRent := rent
+	patchedRent.RoomID = roomID
+	patchedRent.CheckOutDate = newCheckOutDate
+	patchedRent.Version = rent.Version + 1
+	expectRentByID(mock, patchedRent)
+
+	result, err := bookingService.PatchRent(rent.ID, []byte(`{"check_out_date":"2025-01-05T12:00:00Z","confirmed_price_difference":300000}`), caller, rent.Version)
+
+	assert.NoError(t, err)
+	assert.Equal(t, newCheckOutDate, result.CheckOutDate)
+	assert.Equal(t, rent.Version+1, result.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchRent_NullHotel_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	expectRentByID(mock, rent)
+
+	result, err := bookingService.PatchRent(rent.ID, []byte(`{"hotel_id":null}`),
+		&user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}, rent.Version)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchRent_CheckOutBeforeCheckIn_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	expectRentByID(mock, rent)
+	// Valid on its own, the new check-in date is after the stored check-out date
+	patch := fmt.Sprintf(`{"check_in_date":%q}`, rent.CheckOutDate.Add(24*time.Hour).Format(time.RFC3339))
+
+	result, err := bookingService.PatchRent(rent.ID, []byte(patch),
+		&user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}, rent.Version)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchRent_ClientID_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	expectRentByID(mock, rent)
+
+	result, err := bookingService.PatchRent(rent.ID, []byte(fmt.Sprintf(`{"client_id":%q}`, uuid.New())),
+		&user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}, rent.Version)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchRent_NotFound(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rentID := uuid.New()
+	mock.ExpectQuery("FROM bookings b").
+		WithArgs(rentID).
+		WillReturnError(sql.ErrNoRows)
+
+	result, err := bookingService.PatchRent(rentID, []byte(`{}`), &user_service.UserData{Id: uuid.New()}, 1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchRent_StaleVersion_PreconditionFailed(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	rent.Version = 3
+	expectRentByID(mock, rent)
+
+	result, err := bookingService.PatchRent(rent.ID, []byte(`{"check_out_date":"2025-01-05T12:00:00Z"}`),
+		&user_service.UserData{Id: rent.ClientID, Role: user_service.RoleGuest}, 2)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.As(err, new(*custom_errors.ServicePreconditionFailedError)))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
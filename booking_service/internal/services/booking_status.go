@@ -0,0 +1,49 @@
+package services
+
+// Statuses of the booking lifecycle
+const (
+	StatusHeld       = "held"
+	StatusPending    = "pending"
+	StatusConfirmed  = "confirmed"
+	StatusCheckedIn  = "checked_in"
+	StatusCheckedOut = "checked_out"
+	StatusCancelled  = "cancelled"
+	StatusNoShow     = "no_show"
+	// StatusBlocked marks a booking made on another channel, it follows the calendar of the channel
+	StatusBlocked = "blocked"
+)
+
+// allowedTransitions maps every status to the statuses a booking may move to from it.
+// checked_out, cancelled and no_show are final. A held rent becomes pending when its hold is converted,
+// a blocked rent changes only with the calendar of its channel
+var allowedTransitions = map[string][]string{
+	StatusHeld:       {StatusPending, StatusCancelled},
+	StatusPending:    {StatusConfirmed, StatusCancelled},
+	StatusConfirmed:  {StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusCheckedIn:  {StatusCheckedOut},
+	StatusCheckedOut: {},
+	StatusCancelled:  {},
+	StatusNoShow:     {},
+	StatusBlocked:    {},
+}
+
+// IsValidStatus reports whether the status belongs to the booking lifecycle
+func IsValidStatus(status string) bool {
+	_, ok := allowedTransitions[status]
+	return ok
+}
+
+// IsFinalStatus reports whether the booking has left its lifecycle, a blocked booking never enters it
+func IsFinalStatus(status string) bool {
+	return IsValidStatus(status) && len(allowedTransitions[status]) == 0 && status != StatusBlocked
+}
+
+// CanTransition reports whether a booking may move from one status to another
+func CanTransition(from string, to string) bool {
+	for _, status := range allowedTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package services_test
+
+import (
+	"booking_service/internal/services"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, services.CanTransition(services.StatusPending, services.StatusConfirmed))
+	assert.True(t, services.CanTransition(services.StatusConfirmed, services.StatusCheckedIn))
+	assert.True(t, services.CanTransition(services.StatusConfirmed, services.StatusNoShow))
+	assert.True(t, services.CanTransition(services.StatusCheckedIn, services.StatusCheckedOut))
+	assert.True(t, services.CanTransition(services.StatusHeld, services.StatusPending))
+
+	assert.False(t, services.CanTransition(services.StatusPending, services.StatusCheckedIn))
+	assert.False(t, services.CanTransition(services.StatusCheckedIn, services.StatusCancelled))
+	assert.False(t, services.CanTransition(services.StatusCancelled, services.StatusConfirmed))
+	assert.False(t, services.CanTransition(services.StatusCheckedOut, services.StatusCheckedIn))
+	assert.False(t, services.CanTransition(services.StatusHeld, services.StatusConfirmed))
+	assert.False(t, services.CanTransition("unknown", services.StatusConfirmed))
+}
+
+func TestIsValidStatus(t *testing.T) {
+	assert.True(t, services.IsValidStatus(services.StatusNoShow))
+	assert.False(t, services.IsValidStatus("archived"))
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"strconv"
+)
+
+// ErrInvalidFeedToken is returned if the feed token is unknown or has been replaced by a new one
+var ErrInvalidFeedToken = errors.New("invalid feed token")
+
+type ICalendarService interface {
+	CreateFeedToken(caller *user_service.UserData) (*responses.FeedTokenResponse, error)
+	GetHotelFeed(hotelID uuid.UUID, feedToken string) (*responses.CalendarFeedResponse, error)
+	GetGuestFeed(feedToken string) (*responses.CalendarFeedResponse, error)
+}
+
+type CalendarService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+}
+
+func NewCalendarService(database *db.Database, hotelServiceBridge hotel_service.IHotelServiceBridge) *CalendarService {
+	return &CalendarService{Db: database, hotelServiceBridge: hotelServiceBridge}
+}
+
+// Feeds skip the stays which ended long ago, calendar clients keep the events they have already seen.
+// Blocked rents came from the calendars of other channels and are not published back to them
+const calendarFeedQuery = `
+		SELECT b.id, b.hotel_id, b.room_id, b.check_in_date, b.check_out_date, b.status, b.version, b.created_at,
+		       b.adults, b.children, b.primary_guest_name
+		FROM bookings b
+		WHERE %s AND b.status NOT IN ('held', 'blocked') AND b.check_out_date >= now() - interval '90 days'
+		ORDER BY b.check_in_date, b.id`
+
+// calendarRent is the part of the rent shown in the calendar feeds
+type calendarRent struct {
+	ID               uuid.UUID
+	HotelID          uuid.UUID
+	RoomID           uuid.UUID
+	Event            responses.CalendarEventResponse
+	Adults           int
+	Children         int
+	PrimaryGuestName string
+}
+
+// CreateFeedToken issues a new feed token of the caller, the previous token stops working
+func (s *CalendarService) CreateFeedToken(caller *user_service.UserData) (*responses.FeedTokenResponse, error) {
+	slog.Info("Creating feed token in service")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	query := `
+		INSERT INTO calendar_feed_tokens (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()`
+	if _, err := s.Db.Connection.Exec(query, caller.Id, hashFeedToken(token)); err != nil {
+		return nil, fmt.Errorf("failed to store feed token: %w", err)
+	}
+	return &responses.FeedTokenResponse{Token: token}, nil
+}
+
+// GetHotelFeed returns the bookings of the hotel, the feed token must belong to the owner of the hotel
+func (s *CalendarService) GetHotelFeed(hotelID uuid.UUID, feedToken string) (*responses.CalendarFeedResponse, error) {
+	slog.Info("Getting hotel calendar feed in service")
+	userID, err := s.resolveFeedToken(feedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	hotel, err := s.hotelServiceBridge.GetHotel(hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hotel: %w", err)
+	}
+	if hotel == nil || hotel.AdministratorID != userID {
+		return nil, custom_errors.NewServiceForbiddenError("Access denied", "the hotel is administered by another owner")
+	}
+
+	rents, err := s.getCalendarRents("b.hotel_id = $1", hotelID)
+	if err != nil {
+		return nil, err
+	}
+	feed := &responses.CalendarFeedResponse{Name: hotel.Name, Events: make([]responses.CalendarEventResponse, 0, len(rents))}
+	for _, rent := range rents {
+		event := rent.Event
+		event.Summary = rent.PrimaryGuestName
+		if event.Summary == "" {
+			event.Summary = "Booking " + rent.ID.String()[:8]
+		}
+		event.Description = fmt.Sprintf("Rent %s\nRoom %s\nGuests: %d adults, %d children",
+			rent.ID, rent.RoomID, rent.Adults, rent.Children)
+		feed.Events = append(feed.Events, event)
+	}
+	return feed, nil
+}
+
+// GetGuestFeed returns the stays booked by the owner of the feed token
+func (s *CalendarService) GetGuestFeed(feedToken string) (*responses.CalendarFeedResponse, error) {
+	slog.Info("Getting guest calendar feed in service")
+	userID, err := s.resolveFeedToken(feedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	rents, err := s.getCalendarRents("b.client_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every hotel is fetched once for its name
+	hotelNames := map[uuid.UUID]string{}
+	feed := &responses.CalendarFeedResponse{Name: "My stays", Events: make([]responses.CalendarEventResponse, 0, len(rents))}
+	for _, rent := range rents {
+		name, ok := hotelNames[rent.HotelID]
+		if !ok {
+			hotel, err := s.hotelServiceBridge.GetHotel(rent.HotelID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch hotel: %w", err)
+			}
+			if hotel != nil {
+				name = hotel.Name
+			}
+			hotelNames[rent.HotelID] = name
+		}
+
+		event := rent.Event
+		event.Summary = "Stay"
+		if name != "" {
+			event.Summary = "Stay at " + name
+		}
+		event.Description = "Rent " + rent.ID.String() + "\nGuests: " + strconv.Itoa(rent.Adults+rent.Children)
+		feed.Events = append(feed.Events, event)
+	}
+	return feed, nil
+}
+
+func (s *CalendarService) resolveFeedToken(feedToken string) (uuid.UUID, error) {
+	if feedToken == "" {
+		return uuid.Nil, ErrInvalidFeedToken
+	}
+	query := `SELECT t.user_id FROM calendar_feed_tokens t WHERE t.token_hash = $1`
+	var userID uuid.UUID
+	if err := s.Db.Connection.QueryRow(query, hashFeedToken(feedToken)).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, ErrInvalidFeedToken
+		}
+		return uuid.Nil, fmt.Errorf("failed to fetch feed token: %w", err)
+	}
+	return userID, nil
+}
+
+func (s *CalendarService) getCalendarRents(condition string, arg uuid.UUID) ([]calendarRent, error) {
+	rows, err := s.Db.Connection.Query(fmt.Sprintf(calendarFeedQuery, condition), arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rents: %w", err)
+	}
+	defer rows.Close()
+
+	var rents []calendarRent
+	for rows.Next() {
+		var rent calendarRent
+		err := rows.Scan(&rent.ID, &rent.HotelID, &rent.RoomID, &rent.Event.Start, &rent.Event.End, &rent.Event.Status,
+			&rent.Event.Version, &rent.Event.CreatedAt, &rent.Adults, &rent.Children, &rent.PrimaryGuestName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rent: %w", err)
+		}
+		rent.Event.RentID = rent.ID
+		rents = append(rents, rent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rents: %w", err)
+	}
+	return rents, nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var calendarRentColumns = []string{"id", "hotel_id", "room_id", "check_in_date", "check_out_date", "status", "version",
+	"created_at", "adults", "children", "primary_guest_name"}
+
+func newCalendarService(t *testing.T) (*services.CalendarService, sqlmock.Sqlmock, *MockHotelServiceBridge) {
+	db, mock := createMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	bridgeMock := &MockHotelServiceBridge{}
+	return services.NewCalendarService(&db2.Database{Connection: db}, bridgeMock), mock, bridgeMock
+}
+
+func expectFeedToken(mock sqlmock.Sqlmock, token string, userID *uuid.UUID) {
+	sum := sha256.Sum256([]byte(token))
+	rows := sqlmock.NewRows([]string{"user_id"})
+	if userID != nil {
+		rows.AddRow(*userID)
+	}
+	mock.ExpectQuery(`SELECT t.user_id FROM calendar_feed_tokens t WHERE t.token_hash = \$1`).
+		WithArgs(hex.EncodeToString(sum[:])).
+		WillReturnRows(rows)
+}
+
+func TestCreateFeedToken_CommonCase_HashStored(t *testing.T) {
+	calendarService, mock, _ := newCalendarService(t)
+	caller := &user_service.UserData{Id: uuid.New()}
+
+	mock.ExpectExec(`INSERT INTO calendar_feed_tokens \(user_id, token_hash\)`).
+		WithArgs(caller.Id, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	token, err := calendarService.CreateFeedToken(caller)
+
+	assert.NoError(t, err)
+	assert.Len(t, token.Token, 43)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetHotelFeed_Owner_BookingsReturned(t *testing.T) {
+	calendarService, mock, bridgeMock := newCalendarService(t)
+	ownerID := uuid.New()
+	hotelID := uuid.New()
+	rentID := uuid.New()
+	checkIn := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	expectFeedToken(mock, "secret", &ownerID)
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, Name: "Test Hotel", AdministratorID: ownerID}, nil)
+	mock.ExpectQuery(`FROM bookings b WHERE b.hotel_id = \$1 AND b.status NOT IN \('held', 'blocked'\)`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows(calendarRentColumns).
+			AddRow(rentID, hotelID, uuid.New(), checkIn, checkIn.AddDate(0, 0, 2), services.StatusConfirmed, 3,
+				checkIn.AddDate(0, -1, 0), 2, 1, "John Doe"))
+
+	feed, err := calendarService.GetHotelFeed(hotelID, "secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Hotel", feed.Name)
+	assert.Len(t, feed.Events, 1)
+	assert.Equal(t, rentID, feed.Events[0].RentID)
+	assert.Equal(t, "John Doe", feed.Events[0].Summary)
+	assert.Equal(t, 3, feed.Events[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetHotelFeed_HotelOfAnotherOwner_Forbidden(t *testing.T) {
+	calendarService, mock, bridgeMock := newCalendarService(t)
+	userID := uuid.New()
+	hotelID := uuid.New()
+
+	expectFeedToken(mock, "secret", &userID)
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, AdministratorID: uuid.New()}, nil)
+
+	feed, err := calendarService.GetHotelFeed(hotelID, "secret")
+
+	assert.Nil(t, feed)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetGuestFeed_RevokedToken_InvalidFeedToken(t *testing.T) {
+	calendarService, mock, _ := newCalendarService(t)
+
+	expectFeedToken(mock, "revoked", nil)
+
+	feed, err := calendarService.GetGuestFeed("revoked")
+
+	assert.Nil(t, feed)
+	assert.ErrorIs(t, err, services.ErrInvalidFeedToken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetGuestFeed_SeveralStays_HotelFetchedOnce(t *testing.T) {
+	calendarService, mock, bridgeMock := newCalendarService(t)
+	guestID := uuid.New()
+	hotelID := uuid.New()
+	checkIn := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	expectFeedToken(mock, "secret", &guestID)
+	mock.ExpectQuery(`FROM bookings b WHERE b.client_id = \$1 AND b.status NOT IN \('held', 'blocked'\)`).
+		WithArgs(guestID).
+		WillReturnRows(sqlmock.NewRows(calendarRentColumns).
+			AddRow(uuid.New(), hotelID, uuid.New(), checkIn, checkIn.AddDate(0, 0, 2), services.StatusConfirmed, 1, checkIn, 1, 0, "").
+			AddRow(uuid.New(), hotelID, uuid.New(), checkIn.AddDate(0, 1, 0), checkIn.AddDate(0, 1, 3), services.StatusPending, 1, checkIn, 1, 0, ""))
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, Name: "Test Hotel"}, nil).Once()
+
+	feed, err := calendarService.GetGuestFeed("secret")
+
+	assert.NoError(t, err)
+	assert.Len(t, feed.Events, 2)
+	assert.Equal(t, "Stay at Test Hotel", feed.Events[1].Summary)
+	bridgeMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
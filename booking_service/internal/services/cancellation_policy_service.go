@@ -0,0 +1,100 @@
+package services
+
+import (
+	"booking_service/internal/db"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// Policy applied to hotels which did not configure their own one:
+// free cancellation until 48 hours before check-in, then the first night is charged
+const (
+	DefaultFreeCancellationHours = 48
+	DefaultPenaltyNights         = 1
+)
+
+type ICancellationPolicyService interface {
+	SetPolicy(hotelID uuid.UUID, request requests.SetCancellationPolicyRequest, caller *user_service.UserData) error
+	GetPolicy(hotelID uuid.UUID) (*responses.GetCancellationPolicyResponse, error)
+}
+
+type CancellationPolicyService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+}
+
+func NewCancellationPolicyService(database *db.Database, hotelServiceBridge hotel_service.IHotelServiceBridge) *CancellationPolicyService {
+	return &CancellationPolicyService{Db: database, hotelServiceBridge: hotelServiceBridge}
+}
+
+// SetPolicy replaces the cancellation policy of the hotel, only its owner may change the refunds of its rents
+func (s *CancellationPolicyService) SetPolicy(hotelID uuid.UUID, request requests.SetCancellationPolicyRequest, caller *user_service.UserData) error {
+	slog.Info("Setting cancellation policy in service")
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO cancellation_policies (hotel_id, free_cancellation_hours, penalty_nights)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (hotel_id) DO UPDATE
+		SET free_cancellation_hours = EXCLUDED.free_cancellation_hours, penalty_nights = EXCLUDED.penalty_nights`
+	_, err := s.Db.Connection.Exec(query, hotelID, request.FreeCancellationHours, request.PenaltyNights)
+	if err != nil {
+		return fmt.Errorf("failed to set cancellation policy: %w", err)
+	}
+	return nil
+}
+
+func (s *CancellationPolicyService) GetPolicy(hotelID uuid.UUID) (*responses.GetCancellationPolicyResponse, error) {
+	slog.Info("Getting cancellation policy in service")
+	query := `
+		SELECT p.hotel_id, p.free_cancellation_hours, p.penalty_nights
+		FROM cancellation_policies p
+		WHERE p.hotel_id = $1`
+
+	var policy responses.GetCancellationPolicyResponse
+	err := s.Db.Connection.QueryRow(query, hotelID).Scan(&policy.HotelID, &policy.FreeCancellationHours, &policy.PenaltyNights)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &responses.GetCancellationPolicyResponse{
+				HotelID:               hotelID,
+				FreeCancellationHours: DefaultFreeCancellationHours,
+				PenaltyNights:         DefaultPenaltyNights,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch cancellation policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// CalculateCancellationPenalty returns the part of the stay price which is not refunded
+// when the booking is cancelled at the given moment
+func CalculateCancellationPenalty(
+	policy *responses.GetCancellationPolicyResponse,
+	checkInDate time.Time,
+	nights int,
+	nightPrice int,
+	cancelledAt time.Time) int {
+	freeUntil := checkInDate.Add(-time.Duration(policy.FreeCancellationHours) * time.Hour)
+	if !cancelledAt.After(freeUntil) {
+		return 0
+	}
+	return min(policy.PenaltyNights, nights) * nightPrice
+}
+
+// CountNights returns the number of nights between the check-in and check-out dates
+func CountNights(checkInDate time.Time, checkOutDate time.Time) int {
+	checkInDay := time.Date(checkInDate.Year(), checkInDate.Month(), checkInDate.Day(), 0, 0, 0, 0, time.UTC)
+	checkOutDate = checkOutDate.In(checkInDate.Location())
+	checkOutDay := time.Date(checkOutDate.Year(), checkOutDate.Month(), checkOutDate.Day(), 0, 0, 0, 0, time.UTC)
+	nights := int(checkOutDay.Sub(checkInDay).Hours() / 24)
+	return max(nights, 1)
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetPolicy_Success(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyService := services.NewCancellationPolicyService(&db2.Database{Connection: db}, bridgeMock)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	request := requests.SetCancellationPolicyRequest{FreeCancellationHours: 24, PenaltyNights: 2}
+
+	mock.ExpectExec(`INSERT INTO cancellation_policies \(hotel_id, free_cancellation_hours, penalty_nights\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(hotel_id\) DO UPDATE`).
+		WithArgs(hotelID, 24, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := policyService.SetPolicy(hotelID, request, owner)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetPolicy_DBError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyService := services.NewCancellationPolicyService(&db2.Database{Connection: db}, bridgeMock)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+
+	mock.ExpectExec("INSERT INTO cancellation_policies").
+		WillReturnError(errors.New("db error"))
+
+	err := policyService.SetPolicy(hotelID, requests.SetCancellationPolicyRequest{}, owner)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetPolicy_HotelOfAnotherOwner_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	policyService := services.NewCancellationPolicyService(&db2.Database{Connection: db}, bridgeMock)
+	hotelID := uuid.New()
+	newHotelOwner(bridgeMock, hotelID)
+
+	err := policyService.SetPolicy(hotelID, requests.SetCancellationPolicyRequest{FreeCancellationHours: 0},
+		&user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner})
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPolicy_Configured_ReturnPolicy(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	policyService := services.NewCancellationPolicyService(&db2.Database{Connection: db}, &MockHotelServiceBridge{})
+	hotelID := uuid.New()
+
+	mock.ExpectQuery("SELECT p.hotel_id, p.free_cancellation_hours, p.penalty_nights FROM cancellation_policies p").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"hotel_id", "free_cancellation_hours", "penalty_nights"}).
+			AddRow(hotelID, 24, 2))
+
+	policy, err := policyService.GetPolicy(hotelID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, responses.GetCancellationPolicyResponse{HotelID: hotelID, FreeCancellationHours: 24, PenaltyNights: 2}, *policy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPolicy_NotConfigured_ReturnDefaultPolicy(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	policyService := services.NewCancellationPolicyService(&db2.Database{Connection: db}, &MockHotelServiceBridge{})
+	hotelID := uuid.New()
+
+	mock.ExpectQuery("SELECT p.hotel_id, p.free_cancellation_hours, p.penalty_nights FROM cancellation_policies p").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"hotel_id", "free_cancellation_hours", "penalty_nights"}))
+
+	policy, err := policyService.GetPolicy(hotelID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.DefaultFreeCancellationHours, policy.FreeCancellationHours)
+	assert.Equal(t, services.DefaultPenaltyNights, policy.PenaltyNights)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalculateCancellationPenalty(t *testing.T) {
+	policy := &responses.GetCancellationPolicyResponse{FreeCancellationHours: 48, PenaltyNights: 1}
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, services.CalculateCancellationPenalty(policy, checkIn, 3, 100, checkIn.Add(-49*time.Hour)))
+	assert.Equal(t, 0, services.CalculateCancellationPenalty(policy, checkIn, 3, 100, checkIn.Add(-48*time.Hour)))
+	assert.Equal(t, 100, services.CalculateCancellationPenalty(policy, checkIn, 3, 100, checkIn.Add(-47*time.Hour)))
+
+	// The penalty never exceeds the price of the whole stay
+	strictPolicy := &responses.GetCancellationPolicyResponse{FreeCancellationHours: 0, PenaltyNights: 5}
+	assert.Equal(t, 300, services.CalculateCancellationPenalty(strictPolicy, checkIn, 3, 100, checkIn.Add(time.Hour)))
+}
+
+func TestCountNights(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 1, services.CountNights(checkIn, checkIn.Add(2*time.Hour)))
+	assert.Equal(t, 1, services.CountNights(checkIn, checkIn.Add(22*time.Hour)))
+	assert.Equal(t, 2, services.CountNights(checkIn, checkIn.Add(48*time.Hour)))
+	assert.Equal(t, 2, services.CountNights(checkIn, checkIn.Add(50*time.Hour)))
+}
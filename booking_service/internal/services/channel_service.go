@@ -0,0 +1,199 @@
+package services
+
+import (
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/ical"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type IChannelService interface {
+	CreateChannel(hotelID uuid.UUID, request requests.CreateChannelRequest, caller *user_service.UserData) (*responses.ChannelResponse, error)
+	GetChannels(hotelID uuid.UUID, caller *user_service.UserData) ([]responses.ChannelResponse, error)
+	UploadChannelCalendar(hotelID uuid.UUID, channelID uuid.UUID, calendar string, caller *user_service.UserData) (*responses.ChannelResponse, error)
+	DeleteChannel(hotelID uuid.UUID, channelID uuid.UUID, caller *user_service.UserData) (*responses.ChannelResponse, error)
+}
+
+type ChannelService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	waitlistService    IWaitlistService
+}
+
+func NewChannelService(
+	database *db.Database,
+	hotelServiceBridge hotel_service.IHotelServiceBridge,
+	waitlistService IWaitlistService) *ChannelService {
+	return &ChannelService{Db: database, hotelServiceBridge: hotelServiceBridge, waitlistService: waitlistService}
+}
+
+const channelColumns = `c.id, c.hotel_id, c.room_type_id, c.name, COALESCE(c.source_url, ''), c.calendar_data IS NOT NULL,
+		       c.last_synced_at, COALESCE(c.last_error, ''), c.created_at`
+
+// CreateChannel registers the calendar of the hotel on another channel, its bookings are blocked on the next sync
+func (s *ChannelService) CreateChannel(hotelID uuid.UUID, request requests.CreateChannelRequest, caller *user_service.UserData) (*responses.ChannelResponse, error) {
+	slog.Info("Creating channel in service")
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID); err != nil {
+		return nil, err
+	}
+	sourceURL, err := normalizeChannelURL(request.URL)
+	if err != nil {
+		return nil, err
+	}
+	if request.RoomTypeID != nil {
+		roomType, err := s.hotelServiceBridge.GetRoomType(hotelID, *request.RoomTypeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get room type: %w", err)
+		}
+		if roomType == nil {
+			return nil, custom_errors.NewServiceBadRequestError("Room type not found", request.RoomTypeID.String())
+		}
+	}
+
+	channel := &responses.ChannelResponse{
+		HotelID:    hotelID,
+		RoomTypeID: request.RoomTypeID,
+		Name:       strings.TrimSpace(request.Name),
+		URL:        sourceURL,
+	}
+	query := `
+		INSERT INTO channel_calendars (hotel_id, room_type_id, owner_id, name, source_url)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at`
+	err = s.Db.Connection.QueryRow(query, hotelID, request.RoomTypeID, caller.Id, channel.Name, sourceURL).
+		Scan(&channel.ID, &channel.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create channel: %w", err)
+	}
+	return channel, nil
+}
+
+func (s *ChannelService) GetChannels(hotelID uuid.UUID, caller *user_service.UserData) ([]responses.ChannelResponse, error) {
+	slog.Info("Getting channels in service")
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + channelColumns + ` FROM channel_calendars c WHERE c.hotel_id = $1 ORDER BY c.created_at, c.id`
+	rows, err := s.Db.Connection.Query(query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []responses.ChannelResponse{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan channel: %w", err)
+		}
+		channels = append(channels, *channel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate channels: %w", err)
+	}
+	return channels, nil
+}
+
+// UploadChannelCalendar replaces the calendar of a channel which has no URL. The calendar is validated
+// when it is uploaded, so the sync fails only on calendars fetched from URLs
+func (s *ChannelService) UploadChannelCalendar(hotelID uuid.UUID, channelID uuid.UUID, calendar string, caller *user_service.UserData) (*responses.ChannelResponse, error) {
+	slog.Info("Uploading channel calendar in service")
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID); err != nil {
+		return nil, err
+	}
+	if _, err := ical.Parse(strings.NewReader(calendar), time.Local); err != nil {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid calendar", err.Error())
+	}
+
+	channel, err := getChannel(s.Db.Connection, hotelID, channelID)
+	if err != nil || channel == nil {
+		return nil, err
+	}
+	if channel.URL != "" {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Calendar cannot be uploaded",
+			"the channel is synced from its URL")
+	}
+
+	query := `UPDATE channel_calendars SET calendar_data = $2 WHERE id = $1`
+	if _, err := s.Db.Connection.Exec(query, channelID, calendar); err != nil {
+		return nil, fmt.Errorf("failed to upload channel calendar: %w", err)
+	}
+	channel.Uploaded = true
+	return channel, nil
+}
+
+// DeleteChannel removes the channel together with its blocked rents and returns it, or nil if it does not exist
+func (s *ChannelService) DeleteChannel(hotelID uuid.UUID, channelID uuid.UUID, caller *user_service.UserData) (*responses.ChannelResponse, error) {
+	slog.Info("Deleting channel in service")
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID); err != nil {
+		return nil, err
+	}
+
+	query := `DELETE FROM channel_calendars c WHERE c.id = $1 AND c.hotel_id = $2 RETURNING ` + channelColumns
+	channel, err := scanChannel(s.Db.Connection.QueryRow(query, channelID, hotelID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to delete channel: %w", err)
+	}
+
+	// The blocked rooms are free now, the channel stays deleted even if the waitlist cannot be offered them
+	if _, err := s.waitlistService.OfferFreedCapacity(hotelID); err != nil {
+		slog.Error(fmt.Sprintf("Failed to offer freed capacity to the waitlist: %v", err))
+	}
+	return channel, nil
+}
+
+// normalizeChannelURL accepts http and https URLs, webcal is the scheme calendar apps use for https feeds
+func normalizeChannelURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", custom_errors.NewServiceBadRequestError("Invalid channel URL", err.Error())
+	}
+	if parsed.Scheme == "webcal" {
+		parsed.Scheme = "https"
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", custom_errors.NewServiceBadRequestError("Invalid channel URL", "the URL must be an http or https URL")
+	}
+	return parsed.String(), nil
+}
+
+func getChannel(querier rowQuerier, hotelID uuid.UUID, channelID uuid.UUID) (*responses.ChannelResponse, error) {
+	query := `SELECT ` + channelColumns + ` FROM channel_calendars c WHERE c.id = $1 AND c.hotel_id = $2`
+	channel, err := scanChannel(querier.QueryRow(query, channelID, hotelID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch channel: %w", err)
+	}
+	return channel, nil
+}
+
+func scanChannel(row rowScanner) (*responses.ChannelResponse, error) {
+	var channel responses.ChannelResponse
+	err := row.Scan(&channel.ID, &channel.HotelID, &channel.RoomTypeID, &channel.Name, &channel.URL, &channel.Uploaded,
+		&channel.LastSyncedAt, &channel.LastError, &channel.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
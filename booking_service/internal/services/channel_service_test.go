@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var channelColumns = []string{"id", "hotel_id", "room_type_id", "name", "source_url", "uploaded", "last_synced_at",
+	"last_error", "created_at"}
+
+const testChannelCalendar = "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:abc@channel\r\n" +
+	"DTSTART;VALUE=DATE:20250110\r\nDTEND;VALUE=DATE:20250112\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+func newChannelService(t *testing.T) (*services.ChannelService, sqlmock.Sqlmock, *MockHotelServiceBridge, *MockWaitlistService) {
+	db, mock := createMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistMock := &MockWaitlistService{}
+	return services.NewChannelService(&db2.Database{Connection: db}, bridgeMock, waitlistMock), mock, bridgeMock, waitlistMock
+}
+
+func TestCreateChannel_WebcalURL_StoredAsHttps(t *testing.T) {
+	channelService, mock, bridgeMock, _ := newChannelService(t)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	channelID := uuid.New()
+
+	mock.ExpectQuery(`INSERT INTO channel_calendars \(hotel_id, room_type_id, owner_id, name, source_url\)`).
+		WithArgs(hotelID, nil, owner.Id, "Airbnb", "https://example.com/calendar.ics").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(channelID, time.Now()))
+
+	channel, err := channelService.CreateChannel(hotelID,
+		requests.CreateChannelRequest{Name: " Airbnb ", URL: "webcal://example.com/calendar.ics"}, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, channelID, channel.ID)
+	assert.Equal(t, "https://example.com/calendar.ics", channel.URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateChannel_FileURL_BadRequest(t *testing.T) {
+	channelService, mock, bridgeMock, _ := newChannelService(t)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+
+	channel, err := channelService.CreateChannel(hotelID,
+		requests.CreateChannelRequest{Name: "Local", URL: "file:///etc/passwd"}, owner)
+
+	assert.Nil(t, channel)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateChannel_HotelOfAnotherOwner_Forbidden(t *testing.T) {
+	channelService, mock, bridgeMock, _ := newChannelService(t)
+	hotelID := uuid.New()
+	newHotelOwner(bridgeMock, hotelID)
+
+	channel, err := channelService.CreateChannel(hotelID, requests.CreateChannelRequest{Name: "Airbnb"},
+		&user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner})
+
+	assert.Nil(t, channel)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadChannelCalendar_CommonCase_Stored(t *testing.T) {
+	channelService, mock, bridgeMock, _ := newChannelService(t)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	channelID := uuid.New()
+
+	mock.ExpectQuery(`FROM channel_calendars c WHERE c.id = \$1 AND c.hotel_id = \$2`).
+		WithArgs(channelID, hotelID).
+		WillReturnRows(sqlmock.NewRows(channelColumns).
+			AddRow(channelID, hotelID, nil, "Booking", "", false, nil, "", time.Now()))
+	mock.ExpectExec(`UPDATE channel_calendars SET calendar_data = \$2 WHERE id = \$1`).
+		WithArgs(channelID, testChannelCalendar).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	channel, err := channelService.UploadChannelCalendar(hotelID, channelID, testChannelCalendar, owner)
+
+	assert.NoError(t, err)
+	assert.True(t, channel.Uploaded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadChannelCalendar_InvalidCalendar_BadRequest(t *testing.T) {
+	channelService, mock, bridgeMock, _ := newChannelService(t)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+
+	channel, err := channelService.UploadChannelCalendar(hotelID, uuid.New(), "not a calendar", owner)
+
+	assert.Nil(t, channel)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadChannelCalendar_ChannelWithURL_UnprocessableEntity(t *testing.T) {
+	channelService, mock, bridgeMock, _ := newChannelService(t)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	channelID := uuid.New()
+
+	mock.ExpectQuery(`FROM channel_calendars c WHERE c.id = \$1 AND c.hotel_id = \$2`).
+		WithArgs(channelID, hotelID).
+		WillReturnRows(sqlmock.NewRows(channelColumns).
+			AddRow(channelID, hotelID, nil, "Airbnb", "https://example.com/calendar.ics", false, nil, "", time.Now()))
+
+	channel, err := channelService.UploadChannelCalendar(hotelID, channelID, testChannelCalendar, owner)
+
+	assert.Nil(t, channel)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteChannel_CommonCase_FreedCapacityOffered(t *testing.T) {
+	channelService, mock, bridgeMock, waitlistMock := newChannelService(t)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	channelID := uuid.New()
+
+	mock.ExpectQuery(`DELETE FROM channel_calendars c WHERE c.id = \$1 AND c.hotel_id = \$2 RETURNING`).
+		WithArgs(channelID, hotelID).
+		WillReturnRows(sqlmock.NewRows(channelColumns).
+			AddRow(channelID, hotelID, nil, "Booking", "", true, nil, "", time.Now()))
+	waitlistMock.On("OfferFreedCapacity", hotelID).Return(1, nil)
+
+	channel, err := channelService.DeleteChannel(hotelID, channelID, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, channelID, channel.ID)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
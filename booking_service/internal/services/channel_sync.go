@@ -0,0 +1,355 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	"booking_service/internal/db"
+	"booking_service/internal/ical"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxChannelCalendarSize limits a calendar fetched from a channel
+const maxChannelCalendarSize = 5 << 20
+
+// The errors recorded on a channel. The owner sees them instead of the details of the failure,
+// which would tell what answers at the URL
+var (
+	errCalendarUnavailable = errors.New("failed to fetch calendar")
+	errCalendarInvalid     = errors.New("invalid calendar")
+	errChannelNotSynced    = errors.New("failed to sync calendar")
+)
+
+// errNonPublicAddress is returned when a calendar URL leads to an address of the service network
+var errNonPublicAddress = errors.New("the address is not public")
+
+// sharedAddressSpace is used by carrier-grade NAT and cloud metadata services, netip does not count it as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ChannelSyncer applies the calendars of the other channels of the hotels: every event of a calendar blocks
+// a room for its dates, and the block follows the event when it is moved or removed from the calendar
+type ChannelSyncer struct {
+	Db              *db.Database
+	cfg             *config.ChannelConfig
+	client          *http.Client
+	waitlistService IWaitlistService
+}
+
+func NewChannelSyncer(database *db.Database, cfg *config.ChannelConfig, waitlistService IWaitlistService) *ChannelSyncer {
+	return &ChannelSyncer{
+		Db:              database,
+		cfg:             cfg,
+		client:          newChannelClient(cfg),
+		waitlistService: waitlistService,
+	}
+}
+
+// newChannelClient makes the client which fetches the calendars. The URLs are given by the owners, so the client
+// refuses to connect to loopback, private and link-local addresses. The address is checked when it is dialed,
+// which also covers the redirects and the host names resolving to such addresses
+func newChannelClient(cfg *config.ChannelConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.FetchTimeout}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = refuseNonPublicAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the calendar host and take the check with it
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: cfg.FetchTimeout, Transport: transport}
+}
+
+func refuseNonPublicAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || sharedAddressSpace.Contains(ip) {
+		return errNonPublicAddress
+	}
+	return nil
+}
+
+// channelSource is what the syncer needs to know about a channel
+type channelSource struct {
+	ID           uuid.UUID
+	HotelID      uuid.UUID
+	RoomTypeID   *uuid.UUID
+	OwnerID      uuid.UUID
+	SourceURL    string
+	CalendarData string
+}
+
+// channelBlock is a blocked rent created from an event of the channel
+type channelBlock struct {
+	ID           uuid.UUID
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+}
+
+// Run syncs all channels until the context is cancelled
+func (s *ChannelSyncer) Run(ctx context.Context) {
+	slog.Info("Starting channel syncer")
+	ticker := time.NewTicker(s.cfg.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Channel syncer stopped")
+			return
+		case <-ticker.C:
+			if _, err := s.SyncChannels(ctx); err != nil {
+				slog.Error(fmt.Sprintf("Failed to sync channels: %v", err))
+			}
+		}
+	}
+}
+
+// SyncChannels syncs every channel and returns how many of them were synced. A channel which fails
+// keeps its blocks as they are and records the error, the other channels are synced anyway
+func (s *ChannelSyncer) SyncChannels(ctx context.Context) (int, error) {
+	query := `
+		SELECT c.id, c.hotel_id, c.room_type_id, c.owner_id, COALESCE(c.source_url, ''), COALESCE(c.calendar_data, '')
+		FROM channel_calendars c
+		WHERE c.source_url IS NOT NULL OR c.calendar_data IS NOT NULL
+		ORDER BY c.id`
+	rows, err := s.Db.Connection.QueryContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch channels: %w", err)
+	}
+	var channels []channelSource
+	for rows.Next() {
+		var channel channelSource
+		err := rows.Scan(&channel.ID, &channel.HotelID, &channel.RoomTypeID, &channel.OwnerID,
+			&channel.SourceURL, &channel.CalendarData)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate channels: %w", err)
+	}
+
+	synced := 0
+	for _, channel := range channels {
+		if err := s.syncChannel(ctx, channel); err != nil {
+			slog.Error(fmt.Sprintf("Failed to sync channel %s: %v", channel.ID, err))
+			s.recordSyncError(channel.ID, err)
+			continue
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+func (s *ChannelSyncer) syncChannel(ctx context.Context, channel channelSource) error {
+	calendar := channel.CalendarData
+	if channel.SourceURL != "" {
+		fetched, err := s.fetchCalendar(ctx, channel.SourceURL)
+		if err != nil {
+			return err
+		}
+		calendar = fetched
+	}
+	// All-day events are taken in the time zone of the service, like the dates of the rents booked here
+	events, err := ical.Parse(strings.NewReader(calendar), time.Local)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCalendarInvalid, err)
+	}
+
+	// Channels drop past events from their calendars, so only the current and future blocks follow the calendar
+	now := time.Now()
+	wanted := make(map[string]ical.Event, len(events))
+	for _, event := range events {
+		if !event.Cancelled && event.End.After(now) {
+			wanted[event.UID] = event
+		}
+	}
+
+	tx, err := s.Db.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The lock keeps concurrent syncs of the channel apart, it also stops if the channel was deleted meanwhile
+	var locked uuid.UUID
+	err = tx.QueryRow(`SELECT c.id FROM channel_calendars c WHERE c.id = $1 FOR UPDATE`, channel.ID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to lock channel: %w", err)
+	}
+
+	blocks, err := getChannelBlocks(tx, channel.ID)
+	if err != nil {
+		return err
+	}
+
+	released := false
+	for _, uid := range slices.Sorted(maps.Keys(blocks)) {
+		block := blocks[uid]
+		event, ok := wanted[uid]
+		if ok && event.Start.Equal(block.CheckInDate) && event.End.Equal(block.CheckOutDate) {
+			delete(wanted, uid)
+			continue
+		}
+		if !ok && !block.CheckOutDate.After(now) {
+			continue
+		}
+		// A moved event is blocked again, so its room is picked for the new dates
+		if _, err := tx.Exec(`DELETE FROM bookings WHERE id = $1`, block.ID); err != nil {
+			return fmt.Errorf("failed to release blocked rent: %w", err)
+		}
+		released = true
+	}
+
+	var conflicts []string
+	for _, uid := range slices.Sorted(maps.Keys(wanted)) {
+		event := wanted[uid]
+		blocked, err := insertChannelBlock(tx, channel, uid, event)
+		if err != nil {
+			return err
+		}
+		if !blocked {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s - %s", uid,
+				event.Start.Format(time.DateOnly), event.End.Format(time.DateOnly)))
+		}
+	}
+
+	// Events which overlap the rents booked here are reported to the owner, they cannot be blocked
+	var syncError string
+	if len(conflicts) > 0 {
+		syncError = "no free room for events: " + strings.Join(conflicts, ", ")
+	}
+	query := `UPDATE channel_calendars SET last_synced_at = now(), last_error = NULLIF($2, '') WHERE id = $1`
+	if _, err := tx.Exec(query, channel.ID, syncError); err != nil {
+		return fmt.Errorf("failed to update channel: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if released {
+		if _, err := s.waitlistService.OfferFreedCapacity(channel.HotelID); err != nil {
+			slog.Error(fmt.Sprintf("Failed to offer freed capacity to the waitlist: %v", err))
+		}
+	}
+	return nil
+}
+
+func (s *ChannelSyncer) fetchCalendar(ctx context.Context, sourceURL string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create calendar request: %w", err)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errCalendarUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: status %d", errCalendarUnavailable, response.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxChannelCalendarSize+1))
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read calendar: %w", errCalendarUnavailable, err)
+	}
+	if len(body) > maxChannelCalendarSize {
+		return "", fmt.Errorf("%w: calendar is larger than %d bytes", errCalendarInvalid, maxChannelCalendarSize)
+	}
+	return string(body), nil
+}
+
+// recordSyncError records the kind of the failure on the channel, the error itself is only logged
+func (s *ChannelSyncer) recordSyncError(channelID uuid.UUID, syncErr error) {
+	recorded := errChannelNotSynced
+	for _, known := range []error{errCalendarUnavailable, errCalendarInvalid} {
+		if errors.Is(syncErr, known) {
+			recorded = known
+		}
+	}
+	query := `UPDATE channel_calendars SET last_error = $2 WHERE id = $1`
+	if _, err := s.Db.Connection.Exec(query, channelID, recorded.Error()); err != nil {
+		slog.Error(fmt.Sprintf("Failed to record channel sync error: %v", err))
+	}
+}
+
+func getChannelBlocks(tx *sql.Tx, channelID uuid.UUID) (map[string]channelBlock, error) {
+	query := `
+		SELECT b.id, b.external_uid, b.check_in_date, b.check_out_date
+		FROM bookings b
+		WHERE b.channel_id = $1`
+	rows, err := tx.Query(query, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blocked rents: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := map[string]channelBlock{}
+	for rows.Next() {
+		var uid string
+		var block channelBlock
+		if err := rows.Scan(&block.ID, &uid, &block.CheckInDate, &block.CheckOutDate); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked rent: %w", err)
+		}
+		blocks[uid] = block
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate blocked rents: %w", err)
+	}
+	return blocks, nil
+}
+
+// insertChannelBlock blocks a free room for the event the same way a rent is booked, it reports false
+// if no room is free. Blocks carry a zero price, so they never count as revenue
+func insertChannelBlock(tx *sql.Tx, channel channelSource, uid string, event ical.Event) (bool, error) {
+	query := `
+        INSERT INTO bookings (hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, status,
+                              night_price, nights, currency, total_amount, primary_guest_name, channel_id, external_uid)
+        SELECT r.hotel_id, r.id, r.room_type_id, $3, $4, $5, 'blocked', 0, $6, $7, 0, $8, $9, $10
+        FROM rooms r
+        WHERE r.hotel_id = $1 AND ($2::uuid IS NULL OR r.room_type_id = $2) AND NOT EXISTS (
+            SELECT 1 FROM bookings b
+            WHERE b.room_id = r.id AND b.status NOT IN ('cancelled', 'no_show')
+              AND tstzrange(b.check_in_date, b.check_out_date) && tstzrange($4, $5))
+        LIMIT 1
+        RETURNING id`
+	var rentID uuid.UUID
+	err := tx.QueryRow(query, channel.HotelID, channel.RoomTypeID, channel.OwnerID, event.Start, event.End,
+		CountNights(event.Start, event.End), DefaultCurrency, event.Summary, channel.ID, uid).Scan(&rentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		if isExclusionViolation(err) {
+			return false, newNoRoomsAvailableError()
+		}
+		return false, fmt.Errorf("failed to block rent: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,173 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	"booking_service/internal/services"
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var channelSourceColumns = []string{"id", "hotel_id", "room_type_id", "owner_id", "source_url", "calendar_data"}
+
+// newChannelSyncer makes a syncer which may fetch the calendars served by the tests on the loopback address
+func newChannelSyncer(t *testing.T) (*services.ChannelSyncer, sqlmock.Sqlmock, *MockWaitlistService) {
+	return newChannelSyncerWithConfig(t, &config.ChannelConfig{SyncInterval: time.Minute, FetchTimeout: time.Second,
+		AllowPrivateNetworks: true})
+}
+
+func newChannelSyncerWithConfig(t *testing.T, cfg *config.ChannelConfig) (*services.ChannelSyncer, sqlmock.Sqlmock, *MockWaitlistService) {
+	db, mock := createMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	waitlistMock := &MockWaitlistService{}
+	return services.NewChannelSyncer(&db2.Database{Connection: db}, cfg, waitlistMock), mock, waitlistMock
+}
+
+// channelEvent renders an all-day event which starts the given number of days from today
+func channelEvent(uid string, startDay int, endDay int) string {
+	today := time.Now()
+	return "BEGIN:VEVENT\r\nUID:" + uid + "\r\nSUMMARY:Reserved\r\n" +
+		"DTSTART;VALUE=DATE:" + today.AddDate(0, 0, startDay).Format("20060102") + "\r\n" +
+		"DTEND;VALUE=DATE:" + today.AddDate(0, 0, endDay).Format("20060102") + "\r\nEND:VEVENT\r\n"
+}
+
+func channelDay(day int) time.Time {
+	year, month, date := time.Now().AddDate(0, 0, day).Date()
+	return time.Date(year, month, date, 0, 0, 0, 0, time.Local)
+}
+
+func TestSyncChannels_UploadedCalendar_BlocksFollowEvents(t *testing.T) {
+	syncer, mock, waitlistMock := newChannelSyncer(t)
+	channelID := uuid.New()
+	hotelID := uuid.New()
+	ownerID := uuid.New()
+	kept := uuid.New()
+	moved := uuid.New()
+	removed := uuid.New()
+	calendar := "BEGIN:VCALENDAR\r\n" + channelEvent("a", 1, 3) + channelEvent("b", 5, 6) +
+		channelEvent("c", 10, 12) + channelEvent("e", 20, 22) + "END:VCALENDAR\r\n"
+
+	mock.ExpectQuery(`FROM channel_calendars c\s+WHERE c.source_url IS NOT NULL OR c.calendar_data IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows(channelSourceColumns).AddRow(channelID, hotelID, nil, ownerID, "", calendar))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT c.id FROM channel_calendars c WHERE c.id = \$1 FOR UPDATE`).
+		WithArgs(channelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(channelID))
+	mock.ExpectQuery(`FROM bookings b\s+WHERE b.channel_id = \$1`).
+		WithArgs(channelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "external_uid", "check_in_date", "check_out_date"}).
+			AddRow(kept, "a", channelDay(1), channelDay(3)).
+			AddRow(moved, "c", channelDay(8), channelDay(10)).
+			AddRow(removed, "d", channelDay(2), channelDay(4)))
+	mock.ExpectExec(`DELETE FROM bookings WHERE id = \$1`).WithArgs(moved).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM bookings WHERE id = \$1`).WithArgs(removed).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, status,`).
+		WithArgs(hotelID, nil, ownerID, channelDay(5), channelDay(6), 1, services.DefaultCurrency, "Reserved", channelID, "b").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, status,`).
+		WithArgs(hotelID, nil, ownerID, channelDay(10), channelDay(12), 2, services.DefaultCurrency, "Reserved", channelID, "c").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	// Every room is booked for the last event
+	mock.ExpectQuery(`INSERT INTO bookings \(hotel_id, room_id, room_type_id, client_id, check_in_date, check_out_date, status,`).
+		WithArgs(hotelID, nil, ownerID, channelDay(20), channelDay(22), 2, services.DefaultCurrency, "Reserved", channelID, "e").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(`UPDATE channel_calendars SET last_synced_at = now\(\), last_error = NULLIF\(\$2, ''\) WHERE id = \$1`).
+		WithArgs(channelID, "no free room for events: e "+channelDay(20).Format(time.DateOnly)+" - "+channelDay(22).Format(time.DateOnly)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	waitlistMock.On("OfferFreedCapacity", hotelID).Return(0, nil)
+
+	synced, err := syncer.SyncChannels(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncChannels_FeedUnavailable_BlocksKeptAndErrorRecorded(t *testing.T) {
+	syncer, mock, waitlistMock := newChannelSyncer(t)
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer feed.Close()
+	channelID := uuid.New()
+
+	mock.ExpectQuery(`FROM channel_calendars c\s+WHERE c.source_url IS NOT NULL OR c.calendar_data IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows(channelSourceColumns).AddRow(channelID, uuid.New(), nil, uuid.New(), feed.URL, ""))
+	mock.ExpectExec(`UPDATE channel_calendars SET last_error = \$2 WHERE id = \$1`).
+		WithArgs(channelID, "failed to fetch calendar").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	synced, err := syncer.SyncChannels(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, synced)
+	waitlistMock.AssertNotCalled(t, "OfferFreedCapacity")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncChannels_FeedOnServiceNetwork_NotFetched(t *testing.T) {
+	syncer, mock, _ := newChannelSyncerWithConfig(t, &config.ChannelConfig{SyncInterval: time.Minute, FetchTimeout: time.Second})
+	fetched := false
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+	}))
+	defer feed.Close()
+	channelID := uuid.New()
+
+	mock.ExpectQuery(`FROM channel_calendars c\s+WHERE c.source_url IS NOT NULL OR c.calendar_data IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows(channelSourceColumns).AddRow(channelID, uuid.New(), nil, uuid.New(), feed.URL, ""))
+	mock.ExpectExec(`UPDATE channel_calendars SET last_error = \$2 WHERE id = \$1`).
+		WithArgs(channelID, "failed to fetch calendar").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	synced, err := syncer.SyncChannels(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, synced)
+	assert.False(t, fetched)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncChannels_CancelledEvent_BlockReleased(t *testing.T) {
+	syncer, mock, waitlistMock := newChannelSyncer(t)
+	channelID := uuid.New()
+	hotelID := uuid.New()
+	blockID := uuid.New()
+	calendar := "BEGIN:VCALENDAR\r\n" +
+		strings.Replace(channelEvent("a", 1, 3), "END:VEVENT", "STATUS:CANCELLED\r\nEND:VEVENT", 1) +
+		"END:VCALENDAR\r\n"
+
+	mock.ExpectQuery(`FROM channel_calendars c\s+WHERE c.source_url IS NOT NULL OR c.calendar_data IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows(channelSourceColumns).AddRow(channelID, hotelID, nil, uuid.New(), "", calendar))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT c.id FROM channel_calendars c WHERE c.id = \$1 FOR UPDATE`).
+		WithArgs(channelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(channelID))
+	mock.ExpectQuery(`FROM bookings b\s+WHERE b.channel_id = \$1`).
+		WithArgs(channelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "external_uid", "check_in_date", "check_out_date"}).
+			AddRow(blockID, "a", channelDay(1), channelDay(3)))
+	mock.ExpectExec(`DELETE FROM bookings WHERE id = \$1`).WithArgs(blockID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE channel_calendars SET last_synced_at = now\(\)`).
+		WithArgs(channelID, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	waitlistMock.On("OfferFreedCapacity", hotelID).Return(1, nil)
+
+	synced, err := syncer.SyncChannels(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
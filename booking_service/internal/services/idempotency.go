@@ -0,0 +1,70 @@
+package services
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// fingerprintRequest identifies the body of the rent creation, so a repeated key with another body is detected
+func fingerprintRequest(request requests.CreateRentRequest) (string, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize request: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// findIdempotentRent returns the rent created earlier with the key, or nil if the key is new or expired.
+// The rent ID is the whole body of the 201 response, so it is all that is stored to replay it
+func (s *BookingService) findIdempotentRent(key string, clientID uuid.UUID, fingerprint string) (*uuid.UUID, error) {
+	query := `
+		SELECT k.fingerprint, k.rent_id
+		FROM idempotency_keys k
+		WHERE k.key = $1 AND k.client_id = $2 AND k.expires_at > now()`
+	var storedFingerprint string
+	var rentID uuid.UUID
+	err := s.Db.Connection.QueryRow(query, key, clientID).Scan(&storedFingerprint, &rentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch idempotency key: %w", err)
+	}
+
+	if storedFingerprint != fingerprint {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Idempotency key reused",
+			"the key was already used with a different request body")
+	}
+	return &rentID, nil
+}
+
+// storeIdempotencyKey saves the key together with the created rent in its transaction.
+// It returns false if a concurrent request with the same key has already stored it
+func storeIdempotencyKey(tx *sql.Tx, key string, clientID uuid.UUID, fingerprint string, rentID uuid.UUID, ttl time.Duration) (bool, error) {
+	// An expired key is taken over by the new request
+	query := `
+		INSERT INTO idempotency_keys (key, client_id, fingerprint, rent_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key, client_id) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, rent_id = EXCLUDED.rent_id,
+		    created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= now()`
+	result, err := tx.Exec(query, key, clientID, fingerprint, rentID, time.Now().Add(ttl))
+	if err != nil {
+		return false, fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type IInventoryService interface {
+	AddRooms(hotelID uuid.UUID, request requests.AddRoomsRequest, caller *user_service.UserData) ([]uuid.UUID, error)
+	GetRooms(hotelID uuid.UUID) (*responses.GetRoomsResponse, error)
+}
+
+type InventoryService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	waitlistService    IWaitlistService
+}
+
+func NewInventoryService(
+	database *db.Database,
+	hotelServiceBridge hotel_service.IHotelServiceBridge,
+	waitlistService IWaitlistService) *InventoryService {
+	return &InventoryService{Db: database, hotelServiceBridge: hotelServiceBridge, waitlistService: waitlistService}
+}
+
+// AddRooms adds rooms to the inventory of the hotel, only its owner may do it
+func (s *InventoryService) AddRooms(hotelID uuid.UUID, request requests.AddRoomsRequest, caller *user_service.UserData) ([]uuid.UUID, error) {
+	slog.Info("Adding rooms to hotel inventory in service")
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID); err != nil {
+		return nil, err
+	}
+	var roomType *hotel_service.RoomTypeData
+	if request.RoomTypeID != nil {
+		var err error
+		roomType, err = s.hotelServiceBridge.GetRoomType(hotelID, *request.RoomTypeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get room type: %w", err)
+		}
+		if roomType == nil {
+			return nil, custom_errors.NewServiceBadRequestError("Room type not found", request.RoomTypeID.String())
+		}
+	}
+
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if roomType != nil {
+		if err := checkRoomsCount(tx, hotelID, roomType, len(request.RoomNumbers)); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `INSERT INTO rooms (hotel_id, room_type_id, room_number) VALUES ($1, $2, $3) RETURNING id`
+	roomIDs := make([]uuid.UUID, 0, len(request.RoomNumbers))
+	for _, roomNumber := range request.RoomNumbers {
+		var roomID uuid.UUID
+		if err := tx.QueryRow(query, hotelID, request.RoomTypeID, roomNumber).Scan(&roomID); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return nil, custom_errors.NewServiceBadRequestError("Room already exists", roomNumber)
+			}
+			return nil, fmt.Errorf("failed to add room: %w", err)
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// The new rooms may be offered to the waitlisted guests, the rooms are added even if they cannot be
+	if _, err := s.waitlistService.OfferFreedCapacity(hotelID); err != nil {
+		slog.Error(fmt.Sprintf("Failed to offer new rooms to the waitlist: %v", err))
+	}
+	return roomIDs, nil
+}
+
+// checkRoomsCount keeps the inventory of a room type within the number of rooms declared for it in hotel_service.
+// The additions to a room type are serialized, so concurrent ones cannot exceed the number together
+func checkRoomsCount(tx *sql.Tx, hotelID uuid.UUID, roomType *hotel_service.RoomTypeData, added int) error {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, roomType.ID.String()); err != nil {
+		return fmt.Errorf("failed to lock room type: %w", err)
+	}
+	var roomsCount int
+	query := `SELECT count(*) FROM rooms WHERE hotel_id = $1 AND room_type_id = $2`
+	if err := tx.QueryRow(query, hotelID, roomType.ID).Scan(&roomsCount); err != nil {
+		return fmt.Errorf("failed to count rooms of room type: %w", err)
+	}
+	if roomsCount+added > roomType.RoomsCount {
+		return custom_errors.NewServiceBadRequestError("Too many rooms of the room type",
+			fmt.Sprintf("the room type has %d rooms, %d of them are in the inventory", roomType.RoomsCount, roomsCount))
+	}
+	return nil
+}
+
+func (s *InventoryService) GetRooms(hotelID uuid.UUID) (*responses.GetRoomsResponse, error) {
+	slog.Info("Getting hotel inventory in service")
+	query := `
+		SELECT r.id, r.hotel_id, r.room_type_id, r.room_number
+		FROM rooms r
+		WHERE r.hotel_id = $1
+		ORDER BY r.room_number`
+	rows, err := s.Db.Connection.Query(query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := []responses.GetRoomResponse{}
+	for rows.Next() {
+		var room responses.GetRoomResponse
+		if err := rows.Scan(&room.ID, &room.HotelID, &room.RoomTypeID, &room.RoomNumber); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		rooms = append(rooms, room)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rooms: %w", err)
+	}
+
+	return &responses.GetRoomsResponse{Rooms: rooms}, nil
+}
@@ -0,0 +1,205 @@
+package services_test
+
+import (
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAddRooms_CommonCase_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	waitlistMock := &MockWaitlistService{}
+	bridgeMock := &MockHotelServiceBridge{}
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, bridgeMock, waitlistMock)
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	firstRoomID := uuid.New()
+	secondRoomID := uuid.New()
+
+	waitlistMock.On("OfferFreedCapacity", hotelID).Return(0, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO rooms \(hotel_id, room_type_id, room_number\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+		WithArgs(hotelID, nil, "101").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(firstRoomID))
+	mock.ExpectQuery(`INSERT INTO rooms \(hotel_id, room_type_id, room_number\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+		WithArgs(hotelID, nil, "102").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(secondRoomID))
+	mock.ExpectCommit()
+
+	roomIDs, err := inventoryService.AddRooms(hotelID, requests.AddRoomsRequest{RoomNumbers: []string{"101", "102"}}, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{firstRoomID, secondRoomID}, roomIDs)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRooms_DuplicateRoomNumber_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, bridgeMock, &MockWaitlistService{})
+	hotelID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO rooms`).
+		WithArgs(hotelID, nil, "101").
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := inventoryService.AddRooms(hotelID, requests.AddRoomsRequest{RoomNumbers: []string{"101"}}, owner)
+
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRooms_WithRoomType_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistMock := &MockWaitlistService{}
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, bridgeMock, waitlistMock)
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+	roomID := uuid.New()
+
+	bridgeMock.On("GetRoomType", hotelID, roomTypeID).
+		Return(&hotel_service.RoomTypeData{ID: roomTypeID, HotelID: hotelID, RoomsCount: 5}, nil)
+	waitlistMock.On("OfferFreedCapacity", hotelID).Return(0, nil)
+	mock.ExpectBegin()
+	expectRoomsCount(mock, hotelID, roomTypeID, 4)
+	mock.ExpectQuery(`INSERT INTO rooms`).
+		WithArgs(hotelID, &roomTypeID, "201").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(roomID))
+	mock.ExpectCommit()
+
+	roomIDs, err := inventoryService.AddRooms(hotelID, requests.AddRoomsRequest{RoomTypeID: &roomTypeID, RoomNumbers: []string{"201"}}, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{roomID}, roomIDs)
+	waitlistMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRooms_MoreRoomsThanRoomTypeHas_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, bridgeMock, &MockWaitlistService{})
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+
+	bridgeMock.On("GetRoomType", hotelID, roomTypeID).
+		Return(&hotel_service.RoomTypeData{ID: roomTypeID, HotelID: hotelID, RoomsCount: 5}, nil)
+	mock.ExpectBegin()
+	expectRoomsCount(mock, hotelID, roomTypeID, 4)
+	mock.ExpectRollback()
+
+	_, err := inventoryService.AddRooms(hotelID, requests.AddRoomsRequest{RoomTypeID: &roomTypeID, RoomNumbers: []string{"201", "202"}}, owner)
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectRoomsCount(mock sqlmock.Sqlmock, hotelID uuid.UUID, roomTypeID uuid.UUID, roomsCount int) {
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+		WithArgs(roomTypeID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM rooms WHERE hotel_id = \$1 AND room_type_id = \$2`).
+		WithArgs(hotelID, roomTypeID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(roomsCount))
+}
+
+func TestAddRooms_UnknownRoomType_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, bridgeMock, &MockWaitlistService{})
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	owner := newHotelOwner(bridgeMock, hotelID)
+
+	bridgeMock.On("GetRoomType", hotelID, roomTypeID).Return((*hotel_service.RoomTypeData)(nil), nil)
+
+	_, err := inventoryService.AddRooms(hotelID, requests.AddRoomsRequest{RoomTypeID: &roomTypeID, RoomNumbers: []string{"201"}}, owner)
+
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRooms_HotelOfAnotherOwner_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, bridgeMock, &MockWaitlistService{})
+	hotelID := uuid.New()
+	newHotelOwner(bridgeMock, hotelID)
+
+	_, err := inventoryService.AddRooms(hotelID, requests.AddRoomsRequest{RoomNumbers: []string{"101"}},
+		&user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner})
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRooms_CommonCase_ReturnRooms(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockWaitlistService{})
+	hotelID := uuid.New()
+	roomID := uuid.New()
+
+	mock.ExpectQuery(`SELECT r.id, r.hotel_id, r.room_type_id, r.room_number FROM rooms r WHERE r.hotel_id = \$1`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "room_type_id", "room_number"}).AddRow(roomID, hotelID, nil, "101"))
+
+	rooms, err := inventoryService.GetRooms(hotelID)
+
+	assert.NoError(t, err)
+	assert.Len(t, rooms.Rooms, 1)
+	assert.Equal(t, roomID, rooms.Rooms[0].ID)
+	assert.Equal(t, "101", rooms.Rooms[0].RoomNumber)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRooms_DBError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	inventoryService := services.NewInventoryService(&db2.Database{Connection: db}, &MockHotelServiceBridge{}, &MockWaitlistService{})
+	hotelID := uuid.New()
+
+	mock.ExpectQuery(`SELECT r.id, r.hotel_id, r.room_type_id, r.room_number FROM rooms r`).
+		WithArgs(hotelID).
+		WillReturnError(fmt.Errorf("database error"))
+
+	rooms, err := inventoryService.GetRooms(hotelID)
+
+	assert.Error(t, err)
+	assert.Nil(t, rooms)
+	assert.Equal(t, "failed to retrieve rooms: database error", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
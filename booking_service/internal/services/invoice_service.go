@@ -0,0 +1,368 @@
+package services
+
+import (
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Kinds of invoice documents, a credit note cancels an issued invoice
+const (
+	InvoiceKindInvoice    = "invoice"
+	InvoiceKindCreditNote = "credit_note"
+)
+
+type IInvoiceService interface {
+	GetInvoice(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error)
+	IssueCreditNote(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error)
+}
+
+type InvoiceService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	userServiceBridge  user_service.IUserServiceBridge
+}
+
+func NewInvoiceService(
+	database *db.Database,
+	hotelServiceBridge hotel_service.IHotelServiceBridge,
+	userServiceBridge user_service.IUserServiceBridge) *InvoiceService {
+	return &InvoiceService{Db: database, hotelServiceBridge: hotelServiceBridge, userServiceBridge: userServiceBridge}
+}
+
+// GetInvoice returns the invoice of the rent. The invoice is issued on the first request and never changes
+// afterwards; once it is cancelled by a credit note, the next request issues a new one from the current rent
+func (s *InvoiceService) GetInvoice(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error) {
+	slog.Info("Getting invoice in service")
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rent, hasPrice, err := lockInvoicedRent(tx, rentID)
+	if err != nil || rent == nil {
+		return nil, err
+	}
+	hotel, err := s.getInvoiceHotel(rent, caller, false)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := getLatestInvoice(tx, rentID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Kind == InvoiceKindInvoice {
+		return latest, nil
+	}
+	if !isInvoiceable(rent.Status) {
+		// The last credit note is the final document of a rent which is no longer billed
+		if latest != nil {
+			return latest, nil
+		}
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Invoice cannot be issued",
+			fmt.Sprintf("the rent is %s", rent.Status))
+	}
+	if !hasPrice {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Invoice cannot be issued",
+			"the rent has no stored price")
+	}
+
+	// The charges are taken as they were priced, a later change of the pricing settings does not alter them
+	charges, err := getRentCharges(tx, rentID)
+	if err != nil {
+		return nil, err
+	}
+	if charges == nil {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Invoice cannot be issued",
+			"the rent has no stored taxes and fees")
+	}
+	discount, err := getRentDiscount(tx, rentID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &responses.InvoiceResponse{
+		Kind:         InvoiceKindInvoice,
+		RentID:       rent.ID,
+		HotelID:      rent.HotelID,
+		HotelName:    hotel.Name,
+		GuestID:      rent.ClientID,
+		GuestName:    rent.PrimaryGuestName,
+		CheckInDate:  rent.CheckInDate,
+		CheckOutDate: rent.CheckOutDate,
+		Currency:     rent.Currency,
+	}
+	// The invoice never changes once issued, so the email of the guest is fetched whoever requests it first
+	guest, err := s.userServiceBridge.GetUserContactDataByID(rent.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guest contact data: %w", err)
+	}
+	if guest != nil {
+		invoice.GuestEmail = guest.Email
+	}
+	invoice.Lines = invoiceLines(rent, discount, charges)
+	invoice.Taxes = charges.Taxes
+	invoice.Total = rent.TotalAmount
+
+	if err := issueInvoice(tx, invoice); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	slog.Info("Invoice " + invoice.Number + " issued")
+	return invoice, nil
+}
+
+// IssueCreditNote cancels the current invoice of the rent with a credit note of the opposite amount,
+// it is done by the owner of the hotel to correct an invoice
+func (s *InvoiceService) IssueCreditNote(rentID uuid.UUID, caller *user_service.UserData) (*responses.InvoiceResponse, error) {
+	slog.Info("Issuing credit note in service")
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rent, _, err := lockInvoicedRent(tx, rentID)
+	if err != nil || rent == nil {
+		return nil, err
+	}
+	if _, err := s.getInvoiceHotel(rent, caller, true); err != nil {
+		return nil, err
+	}
+
+	invoice, err := getLatestInvoice(tx, rentID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil || invoice.Kind != InvoiceKindInvoice {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Credit note cannot be issued",
+			"the rent has no invoice to credit")
+	}
+
+	creditNote := *invoice
+	creditNote.Kind = InvoiceKindCreditNote
+	creditNote.CreditedInvoiceID = &invoice.ID
+	creditNote.CreditedNumber = invoice.Number
+	creditNote.Lines = make([]responses.InvoiceLineResponse, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		creditNote.Lines = append(creditNote.Lines, responses.InvoiceLineResponse{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   -line.UnitPrice,
+			Amount:      -line.Amount,
+		})
+	}
+	creditNote.Taxes = -invoice.Taxes
+	creditNote.Total = -invoice.Total
+
+	if err := issueInvoice(tx, &creditNote); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	slog.Info("Credit note " + creditNote.Number + " issued for invoice " + invoice.Number)
+	return &creditNote, nil
+}
+
+// getInvoiceHotel fetches the hotel printed on the invoice and allows the owner of the hotel
+// and, unless ownerOnly is set, the guest of the rent
+func (s *InvoiceService) getInvoiceHotel(
+	rent *responses.GetRentResponse, caller *user_service.UserData, ownerOnly bool) (*hotel_service.HotelData, error) {
+	hotel, err := s.hotelServiceBridge.GetHotel(rent.HotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hotel: %w", err)
+	}
+	if caller.IsOwner() && hotel != nil && hotel.AdministratorID == caller.Id {
+		return hotel, nil
+	}
+	if ownerOnly {
+		return nil, custom_errors.NewServiceForbiddenError("Access denied", "only the hotel owner may do it")
+	}
+	if rent.ClientID != caller.Id {
+		return nil, custom_errors.NewServiceForbiddenError("Access denied",
+			"only the guest of the rent and the owner of its hotel may see its invoices")
+	}
+	if hotel == nil {
+		return nil, fmt.Errorf("hotel %s not found", rent.HotelID)
+	}
+	return hotel, nil
+}
+
+// invoiceLines itemizes the stored price of the rent: the nights at the booked price, the discount of the promo code,
+// and the taxes and the service fee stored with the price, so the lines add up to the charged amount
+func invoiceLines(rent *responses.GetRentResponse, discount *rentDiscount, charges *rentCharges) []responses.InvoiceLineResponse {
+	subtotal := rent.Nights * rent.NightPrice
+	lines := []responses.InvoiceLineResponse{{
+		Description: fmt.Sprintf("Accommodation %s - %s",
+			rent.CheckInDate.Format("2006-01-02"), rent.CheckOutDate.Format("2006-01-02")),
+		Quantity:  rent.Nights,
+		UnitPrice: rent.NightPrice,
+		Amount:    subtotal,
+	}}
+
+	if discount != nil && discount.Amount > 0 {
+		lines = append(lines, responses.InvoiceLineResponse{
+			Description: "Promo code " + discount.Code,
+			Quantity:    1,
+			UnitPrice:   -discount.Amount,
+			Amount:      -discount.Amount,
+		})
+	}
+
+	if charges.Taxes != 0 {
+		lines = append(lines, responses.InvoiceLineResponse{
+			Description: "Taxes",
+			Quantity:    1,
+			UnitPrice:   charges.Taxes,
+			Amount:      charges.Taxes,
+		})
+	}
+	if charges.ServiceFee != 0 {
+		lines = append(lines, responses.InvoiceLineResponse{
+			Description: "Service fee",
+			Quantity:    1,
+			UnitPrice:   charges.ServiceFee,
+			Amount:      charges.ServiceFee,
+		})
+	}
+	return lines
+}
+
+// isInvoiceable reports whether a rent in the status is billed, held and pending rents are not booked yet
+func isInvoiceable(status string) bool {
+	return status == StatusConfirmed || status == StatusCheckedIn || status == StatusCheckedOut
+}
+
+// rentDiscount is the promo code redeemed by a rent
+type rentDiscount struct {
+	Code   string
+	Amount int
+}
+
+func lockInvoicedRent(tx *sql.Tx, rentID uuid.UUID) (*responses.GetRentResponse, bool, error) {
+	query := `
+		SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+		       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+		       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
+		FROM bookings b
+		WHERE b.id = $1
+		FOR UPDATE`
+	rent, hasPrice, err := scanRentRow(tx.QueryRow(query, rentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to fetch rent: %w", err)
+	}
+	return rent, hasPrice, nil
+}
+
+// rentCharges are the taxes and the service fee of the rent, priced together with its nights
+type rentCharges struct {
+	Taxes      int
+	ServiceFee int
+}
+
+// getRentCharges returns the stored charges of the rent, or nil if the rent was priced before they were stored
+func getRentCharges(tx *sql.Tx, rentID uuid.UUID) (*rentCharges, error) {
+	query := `
+		SELECT b.taxes, b.service_fee
+		FROM bookings b
+		WHERE b.id = $1 AND b.taxes IS NOT NULL AND b.service_fee IS NOT NULL`
+	var charges rentCharges
+	if err := tx.QueryRow(query, rentID).Scan(&charges.Taxes, &charges.ServiceFee); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch rent charges: %w", err)
+	}
+	return &charges, nil
+}
+
+func getRentDiscount(tx *sql.Tx, rentID uuid.UUID) (*rentDiscount, error) {
+	query := `
+		SELECT r.code, r.discount_amount
+		FROM promo_code_redemptions r
+		WHERE r.booking_id = $1`
+	var discount rentDiscount
+	if err := tx.QueryRow(query, rentID).Scan(&discount.Code, &discount.Amount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch rent discount: %w", err)
+	}
+	return &discount, nil
+}
+
+func getLatestInvoice(tx *sql.Tx, rentID uuid.UUID) (*responses.InvoiceResponse, error) {
+	query := `SELECT i.document FROM invoices i WHERE i.booking_id = $1 ORDER BY i.sequence DESC LIMIT 1`
+	var document []byte
+	if err := tx.QueryRow(query, rentID).Scan(&document); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
+	}
+
+	var invoice responses.InvoiceResponse
+	if err := json.Unmarshal(document, &invoice); err != nil {
+		return nil, fmt.Errorf("failed to decode invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// issueInvoice numbers the document with the next number of the hotel and stores its snapshot
+func issueInvoice(tx *sql.Tx, invoice *responses.InvoiceResponse) error {
+	query := `
+		INSERT INTO hotel_invoice_sequences (hotel_id, last_number)
+		VALUES ($1, 1)
+		ON CONFLICT (hotel_id) DO UPDATE SET last_number = hotel_invoice_sequences.last_number + 1
+		RETURNING last_number`
+	var sequence int
+	if err := tx.QueryRow(query, invoice.HotelID).Scan(&sequence); err != nil {
+		return fmt.Errorf("failed to take invoice number: %w", err)
+	}
+
+	invoice.ID = uuid.New()
+	invoice.Number = formatInvoiceNumber(invoice.Kind, invoice.HotelID, sequence)
+	invoice.IssuedAt = time.Now().UTC().Truncate(time.Second)
+	document, err := json.Marshal(invoice)
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice: %w", err)
+	}
+
+	query = `
+		INSERT INTO invoices (id, hotel_id, booking_id, sequence, number, kind, credited_invoice_id, total, currency,
+		                      document, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err = tx.Exec(query, invoice.ID, invoice.HotelID, invoice.RentID, sequence, invoice.Number, invoice.Kind,
+		invoice.CreditedInvoiceID, invoice.Total, invoice.Currency, document, invoice.IssuedAt)
+	if err != nil {
+		return fmt.Errorf("failed to issue invoice: %w", err)
+	}
+	return nil
+}
+
+// formatInvoiceNumber prefixes the sequence with the kind of the document and the hotel, e.g. INV-1A2B3C4D-000042
+func formatInvoiceNumber(kind string, hotelID uuid.UUID, sequence int) string {
+	prefix := "INV"
+	if kind == InvoiceKindCreditNote {
+		prefix = "CN"
+	}
+	return fmt.Sprintf("%s-%s-%06d", prefix, strings.ToUpper(hotelID.String()[:8]), sequence)
+}
@@ -0,0 +1,250 @@
+package services_test
+
+import (
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"encoding/json"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func newInvoiceService(t *testing.T) (*services.InvoiceService, sqlmock.Sqlmock, *MockHotelServiceBridge, *MockUserServiceBridge) {
+	db, mock := createMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	bridgeMock := &MockHotelServiceBridge{}
+	userBridgeMock := &MockUserServiceBridge{}
+	invoiceService := services.NewInvoiceService(&db2.Database{Connection: db}, bridgeMock, userBridgeMock)
+	return invoiceService, mock, bridgeMock, userBridgeMock
+}
+
+func expectRentCharges(mock sqlmock.Sqlmock, rentID uuid.UUID, taxes int, serviceFee int) {
+	mock.ExpectQuery(`SELECT b.taxes, b.service_fee FROM bookings b WHERE b.id = \$1 AND b.taxes IS NOT NULL AND b.service_fee IS NOT NULL`).
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows([]string{"taxes", "service_fee"}).AddRow(taxes, serviceFee))
+}
+
+func expectLatestInvoice(mock sqlmock.Sqlmock, rentID uuid.UUID, invoice *responses.InvoiceResponse) {
+	rows := sqlmock.NewRows([]string{"document"})
+	if invoice != nil {
+		document, _ := json.Marshal(invoice)
+		rows.AddRow(document)
+	}
+	mock.ExpectQuery(`SELECT i.document FROM invoices i WHERE i.booking_id = \$1 ORDER BY i.sequence DESC LIMIT 1`).
+		WithArgs(rentID).
+		WillReturnRows(rows)
+}
+
+func expectIssuedInvoice(mock sqlmock.Sqlmock, hotelID uuid.UUID, rentID uuid.UUID, sequence int, number string, kind string, total int) {
+	mock.ExpectQuery(`INSERT INTO hotel_invoice_sequences \(hotel_id, last_number\)`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(sequence))
+	mock.ExpectExec(`INSERT INTO invoices`).
+		WithArgs(sqlmock.AnyArg(), hotelID, rentID, sequence, number, kind, sqlmock.AnyArg(), total,
+			services.DefaultCurrency, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func newTestInvoice(rent responses.GetRentResponse) *responses.InvoiceResponse {
+	return &responses.InvoiceResponse{
+		ID:       uuid.New(),
+		Number:   "INV-" + strings.ToUpper(rent.HotelID.String()[:8]) + "-000007",
+		Kind:     services.InvoiceKindInvoice,
+		RentID:   rent.ID,
+		HotelID:  rent.HotelID,
+		GuestID:  rent.ClientID,
+		Lines:    []responses.InvoiceLineResponse{{Description: "Accommodation", Quantity: 1, UnitPrice: 1000_00, Amount: 1000_00}},
+		Total:    1000_00,
+		Currency: services.DefaultCurrency,
+	}
+}
+
+func TestGetInvoice_FirstRequest_InvoiceIssued(t *testing.T) {
+	invoiceService, mock, bridgeMock, userBridgeMock := newInvoiceService(t)
+	rent := newAccessTestRent()
+	rent.Nights = 2
+	rent.TotalAmount = 2480_00
+	caller := &user_service.UserData{Id: rent.ClientID, Email: "guest@example.com"}
+	number := "INV-" + strings.ToUpper(rent.HotelID.String()[:8]) + "-000001"
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, Name: "Test Hotel"}, nil)
+	userBridgeMock.On("GetUserContactDataByID", rent.ClientID).Return(caller, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	expectLatestInvoice(mock, rent.ID, nil)
+	// The charges are the stored ones, whatever the current pricing settings are
+	expectRentCharges(mock, rent.ID, 180_00, 500_00)
+	mock.ExpectQuery(`SELECT r.code, r.discount_amount FROM promo_code_redemptions r`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "discount_amount"}).AddRow("SPRING", 200_00))
+	expectIssuedInvoice(mock, rent.HotelID, rent.ID, 1, number, services.InvoiceKindInvoice, 2480_00)
+	mock.ExpectCommit()
+
+	invoice, err := invoiceService.GetInvoice(rent.ID, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, number, invoice.Number)
+	assert.Equal(t, "Test Hotel", invoice.HotelName)
+	assert.Equal(t, "guest@example.com", invoice.GuestEmail)
+	assert.Equal(t, []responses.InvoiceLineResponse{
+		{Description: invoice.Lines[0].Description, Quantity: 2, UnitPrice: 1000_00, Amount: 2000_00},
+		{Description: "Promo code SPRING", Quantity: 1, UnitPrice: -200_00, Amount: -200_00},
+		{Description: "Taxes", Quantity: 1, UnitPrice: 180_00, Amount: 180_00},
+		{Description: "Service fee", Quantity: 1, UnitPrice: 500_00, Amount: 500_00},
+	}, invoice.Lines)
+	assert.Equal(t, 180_00, invoice.Taxes)
+	assert.Equal(t, 2480_00, invoice.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInvoice_FirstRequestedByOwner_GuestEmailIssued(t *testing.T) {
+	invoiceService, mock, bridgeMock, userBridgeMock := newInvoiceService(t)
+	rent := newAccessTestRent()
+	owner := &user_service.UserData{Id: uuid.New(), Email: "owner@example.com", Role: user_service.RoleOwner}
+	number := "INV-" + strings.ToUpper(rent.HotelID.String()[:8]) + "-000001"
+
+	bridgeMock.On("GetHotel", rent.HotelID).
+		Return(&hotel_service.HotelData{ID: rent.HotelID, Name: "Test Hotel", AdministratorID: owner.Id}, nil)
+	userBridgeMock.On("GetUserContactDataByID", rent.ClientID).
+		Return(&user_service.UserData{Id: rent.ClientID, Email: "guest@example.com"}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	expectLatestInvoice(mock, rent.ID, nil)
+	expectRentCharges(mock, rent.ID, 0, 0)
+	mock.ExpectQuery(`SELECT r.code, r.discount_amount FROM promo_code_redemptions r`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "discount_amount"}))
+	expectIssuedInvoice(mock, rent.HotelID, rent.ID, 1, number, services.InvoiceKindInvoice, rent.TotalAmount)
+	mock.ExpectCommit()
+
+	invoice, err := invoiceService.GetInvoice(rent.ID, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rent.ClientID, invoice.GuestID)
+	assert.Equal(t, "guest@example.com", invoice.GuestEmail)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInvoice_NoStoredCharges_UnprocessableEntity(t *testing.T) {
+	invoiceService, mock, bridgeMock, _ := newInvoiceService(t)
+	rent := newAccessTestRent()
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, Name: "Test Hotel"}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	expectLatestInvoice(mock, rent.ID, nil)
+	// The rent was priced before its taxes and fees were stored
+	mock.ExpectQuery(`SELECT b.taxes, b.service_fee FROM bookings b`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"taxes", "service_fee"}))
+	mock.ExpectRollback()
+
+	invoice, err := invoiceService.GetInvoice(rent.ID, &user_service.UserData{Id: rent.ClientID})
+
+	assert.Nil(t, invoice)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInvoice_AlreadyIssued_SameInvoiceReturned(t *testing.T) {
+	invoiceService, mock, bridgeMock, _ := newInvoiceService(t)
+	rent := newAccessTestRent()
+	issued := newTestInvoice(rent)
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, Name: "Renamed Hotel"}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	expectLatestInvoice(mock, rent.ID, issued)
+	mock.ExpectRollback()
+
+	invoice, err := invoiceService.GetInvoice(rent.ID, &user_service.UserData{Id: rent.ClientID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, issued.ID, invoice.ID)
+	assert.Equal(t, issued.Number, invoice.Number)
+	assert.Empty(t, invoice.HotelName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInvoice_PendingRent_UnprocessableEntity(t *testing.T) {
+	invoiceService, mock, bridgeMock, _ := newInvoiceService(t)
+	rent := newAccessTestRent()
+	rent.Status = services.StatusPending
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	expectLatestInvoice(mock, rent.ID, nil)
+	mock.ExpectRollback()
+
+	invoice, err := invoiceService.GetInvoice(rent.ID, &user_service.UserData{Id: rent.ClientID})
+
+	assert.Nil(t, invoice)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInvoice_AnotherGuest_Forbidden(t *testing.T) {
+	invoiceService, mock, bridgeMock, _ := newInvoiceService(t)
+	rent := newAccessTestRent()
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: uuid.New()}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	mock.ExpectRollback()
+
+	invoice, err := invoiceService.GetInvoice(rent.ID, &user_service.UserData{Id: uuid.New()})
+
+	assert.Nil(t, invoice)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIssueCreditNote_IssuedInvoice_AmountsReversed(t *testing.T) {
+	invoiceService, mock, bridgeMock, _ := newInvoiceService(t)
+	rent := newAccessTestRent()
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	issued := newTestInvoice(rent)
+	number := "CN-" + strings.ToUpper(rent.HotelID.String()[:8]) + "-000008"
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: owner.Id}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	expectLatestInvoice(mock, rent.ID, issued)
+	expectIssuedInvoice(mock, rent.HotelID, rent.ID, 8, number, services.InvoiceKindCreditNote, -1000_00)
+	mock.ExpectCommit()
+
+	creditNote, err := invoiceService.IssueCreditNote(rent.ID, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, number, creditNote.Number)
+	assert.Equal(t, issued.ID, *creditNote.CreditedInvoiceID)
+	assert.Equal(t, issued.Number, creditNote.CreditedNumber)
+	assert.Equal(t, -1000_00, creditNote.Lines[0].Amount)
+	assert.Equal(t, -1000_00, creditNote.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIssueCreditNote_Guest_Forbidden(t *testing.T) {
+	invoiceService, mock, bridgeMock, _ := newInvoiceService(t)
+	rent := newAccessTestRent()
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: uuid.New()}, nil)
+	mock.ExpectBegin()
+	expectRentByID(mock, rent)
+	mock.ExpectRollback()
+
+	creditNote, err := invoiceService.IssueCreditNote(rent.ID, &user_service.UserData{Id: rent.ClientID})
+
+	assert.Nil(t, creditNote)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// applyMergePatch applies the JSON merge patch (RFC 7396) to the current state of a resource and decodes
+// the merged document into result. Fields which are absent in the patch keep their values, fields set to
+// null are removed and so end up with their zero values
+func applyMergePatch(current interface{}, patch []byte, result interface{}) error {
+	var patchDocument interface{}
+	if err := json.Unmarshal(patch, &patchDocument); err != nil {
+		return custom_errors.NewServiceBadRequestError("Invalid merge patch", err.Error())
+	}
+	if _, ok := patchDocument.(map[string]interface{}); !ok {
+		return custom_errors.NewServiceBadRequestError("Invalid merge patch", "the patch must be a JSON object")
+	}
+
+	currentBody, err := json.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to serialize resource: %w", err)
+	}
+	var currentDocument interface{}
+	if err := json.Unmarshal(currentBody, &currentDocument); err != nil {
+		return fmt.Errorf("failed to serialize resource: %w", err)
+	}
+
+	mergedBody, err := json.Marshal(mergeDocuments(currentDocument, patchDocument))
+	if err != nil {
+		return fmt.Errorf("failed to serialize merged resource: %w", err)
+	}
+	// Fields which cannot be changed, such as the client of a rent, are rejected instead of being ignored
+	decoder := json.NewDecoder(bytes.NewReader(mergedBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result); err != nil {
+		return custom_errors.NewServiceBadRequestError("Invalid merge patch", err.Error())
+	}
+	return nil
+}
+
+func mergeDocuments(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergeDocuments(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	"booking_service/internal/db"
+	"booking_service/internal/metrics"
+	"booking_service/internal/service_interaction/notification_service"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// enqueueNotification stores the notification in the outbox within the transaction which changes the rent,
+// so the notification is sent if and only if the change is committed
+func enqueueNotification(tx *sql.Tx, notificationData *notification_service.NotificationData) error {
+	payload, err := json.Marshal(notificationData)
+	if err != nil {
+		return fmt.Errorf("failed to serialize notification data: %w", err)
+	}
+
+	query := `INSERT INTO notification_outbox (event_type, payload) VALUES ($1, $2)`
+	if _, err := tx.Exec(query, notificationData.EventType, payload); err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return nil
+}
+
+type OutboxRelay struct {
+	Db                        *db.Database
+	notificationServiceBridge notification_service.INotificationServiceBridge
+	cfg                       *config.OutboxConfig
+}
+
+func NewOutboxRelay(
+	database *db.Database,
+	notificationServiceBridge notification_service.INotificationServiceBridge,
+	cfg *config.OutboxConfig) *OutboxRelay {
+	return &OutboxRelay{Db: database, notificationServiceBridge: notificationServiceBridge, cfg: cfg}
+}
+
+// Run publishes pending notifications until the context is cancelled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	slog.Info("Starting outbox relay")
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			if _, err := r.RelayBatch(ctx); err != nil {
+				slog.Error(fmt.Sprintf("Failed to relay outbox: %v", err))
+			}
+			if err := r.UpdateLagMetrics(); err != nil {
+				slog.Error(fmt.Sprintf("Failed to update outbox metrics: %v", err))
+			}
+		}
+	}
+}
+
+// RelayBatch publishes a batch of due notifications and returns how many of them were sent.
+// Failed notifications are retried later with an exponential backoff
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	tx, err := r.Db.Connection.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Rows locked by another relay instance are skipped instead of being sent twice
+	query := `
+		SELECT o.id, o.payload, o.attempts
+		FROM notification_outbox o
+		WHERE o.sent_at IS NULL AND o.next_attempt_at <= now()
+		ORDER BY o.created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(query, r.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch outbox messages: %w", err)
+	}
+
+	type outboxMessage struct {
+		id       uuid.UUID
+		payload  []byte
+		attempts int
+	}
+	var messages []outboxMessage
+	for rows.Next() {
+		var message outboxMessage
+		if err := rows.Scan(&message.id, &message.payload, &message.attempts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate over outbox messages: %w", err)
+	}
+
+	sent := 0
+	for _, message := range messages {
+		if err := r.notificationServiceBridge.SendMessage(ctx, message.payload); err != nil {
+			metrics.OutboxPublishFailuresTotal.Inc()
+			nextAttemptAt := time.Now().Add(r.backoff(message.attempts + 1))
+			_, err = tx.Exec(`
+				UPDATE notification_outbox
+				SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+				WHERE id = $1`, message.id, err.Error(), nextAttemptAt)
+			if err != nil {
+				return 0, fmt.Errorf("failed to reschedule outbox message: %w", err)
+			}
+			continue
+		}
+
+		if _, err := tx.Exec(`UPDATE notification_outbox SET sent_at = now() WHERE id = $1`, message.id); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox message as sent: %w", err)
+		}
+		metrics.OutboxPublishedTotal.Inc()
+		sent++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return sent, nil
+}
+
+// UpdateLagMetrics exposes how many notifications wait in the outbox and for how long
+func (r *OutboxRelay) UpdateLagMetrics() error {
+	query := `
+		SELECT COUNT(*), COALESCE(EXTRACT(EPOCH FROM now() - MIN(o.created_at)), 0)
+		FROM notification_outbox o
+		WHERE o.sent_at IS NULL`
+	var pending int
+	var lagSeconds float64
+	if err := r.Db.Connection.QueryRow(query).Scan(&pending, &lagSeconds); err != nil {
+		return fmt.Errorf("failed to fetch outbox lag: %w", err)
+	}
+
+	metrics.OutboxPendingMessages.Set(float64(pending))
+	metrics.OutboxRelayLag.Set(lagSeconds)
+	return nil
+}
+
+// backoff returns the delay before the given attempt: 1s, 2s, 4s... up to MaxBackoff
+func (r *OutboxRelay) backoff(attempt int) time.Duration {
+	delay := time.Second << min(attempt-1, 30)
+	return min(delay, r.cfg.MaxBackoff)
+}
@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	"booking_service/internal/services"
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newOutboxConfig() *config.OutboxConfig {
+	return &config.OutboxConfig{PollInterval: time.Second, BatchSize: 10, MaxBackoff: time.Minute}
+}
+
+func TestRelayBatch_Success(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockNotificationServiceBridge{}
+	relay := services.NewOutboxRelay(&db2.Database{Connection: db}, bridgeMock, newOutboxConfig())
+
+	messageID := uuid.New()
+	payload := []byte(`{"event_type":"booking_created"}`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT o.id, o.payload, o.attempts FROM notification_outbox o").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "attempts"}).AddRow(messageID, payload, 0))
+	mock.ExpectExec(`UPDATE notification_outbox SET sent_at = now\(\) WHERE id = \$1`).
+		WithArgs(messageID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	bridgeMock.On("SendMessage", context.Background(), payload).Return(nil)
+
+	sent, err := relay.RelayBatch(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	bridgeMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelayBatch_SendFailed_Rescheduled(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockNotificationServiceBridge{}
+	relay := services.NewOutboxRelay(&db2.Database{Connection: db}, bridgeMock, newOutboxConfig())
+
+	messageID := uuid.New()
+	payload := []byte(`{"event_type":"booking_cancelled"}`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT o.id, o.payload, o.attempts FROM notification_outbox o").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "attempts"}).AddRow(messageID, payload, 2))
+	mock.ExpectExec("UPDATE notification_outbox SET attempts = attempts \\+ 1, last_error = \\$2, next_attempt_at = \\$3").
+		WithArgs(messageID, "kafka is unavailable", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	bridgeMock.On("SendMessage", context.Background(), payload).Return(errors.New("kafka is unavailable"))
+
+	sent, err := relay.RelayBatch(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	bridgeMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelayBatch_NoMessages(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockNotificationServiceBridge{}
+	relay := services.NewOutboxRelay(&db2.Database{Connection: db}, bridgeMock, newOutboxConfig())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT o.id, o.payload, o.attempts FROM notification_outbox o").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "attempts"}))
+	mock.ExpectCommit()
+
+	sent, err := relay.RelayBatch(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	bridgeMock.AssertNotCalled(t, "SendMessage")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateLagMetrics(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	relay := services.NewOutboxRelay(&db2.Database{Connection: db}, &MockNotificationServiceBridge{}, newOutboxConfig())
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COALESCE\\(EXTRACT\\(EPOCH FROM now\\(\\) - MIN\\(o.created_at\\)\\), 0\\) FROM notification_outbox o").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "lag"}).AddRow(3, 12.5))
+
+	err := relay.UpdateLagMetrics()
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/notification_service"
+	"booking_service/internal/service_interaction/payment_provider"
+	"booking_service/internal/service_interaction/user_service"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// Payment statuses of a rent. A payment is pending while the provider has not reported its outcome,
+// a refund is pending from the cancellation of the rent until the provider has returned the money
+const (
+	PaymentStatusUnpaid        = "unpaid"
+	PaymentStatusPending       = "pending"
+	PaymentStatusRefundPending = "refund_pending"
+	PaymentStatusAuthorized    = payment_provider.StatusAuthorized
+	PaymentStatusDeclined      = payment_provider.StatusDeclined
+	PaymentStatusCaptured      = payment_provider.StatusCaptured
+	PaymentStatusRefunded      = payment_provider.StatusRefunded
+)
+
+type IPaymentService interface {
+	AuthorizePayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error)
+	CapturePayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error)
+	RefundPayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error)
+	HandleWebhook(payload []byte, signature string) error
+}
+
+type PaymentService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	userServiceBridge  user_service.IUserServiceBridge
+	provider           payment_provider.IPaymentProvider
+}
+
+func NewPaymentService(
+	database *db.Database,
+	hotelServiceBridge hotel_service.IHotelServiceBridge,
+	userServiceBridge user_service.IUserServiceBridge,
+	provider payment_provider.IPaymentProvider) *PaymentService {
+	return &PaymentService{
+		Db:                 database,
+		hotelServiceBridge: hotelServiceBridge,
+		userServiceBridge:  userServiceBridge,
+		provider:           provider}
+}
+
+// rentPayment is the part of the rent needed to pay for it
+type rentPayment struct {
+	ID            uuid.UUID
+	HotelID       uuid.UUID
+	ClientID      uuid.UUID
+	Status        string
+	TotalAmount   int
+	Currency      string
+	RefundAmount  int
+	PaymentStatus string
+	PaymentID     string
+}
+
+func (p *rentPayment) response() *responses.PaymentResponse {
+	return &responses.PaymentResponse{
+		RentID:        p.ID,
+		PaymentID:     p.PaymentID,
+		PaymentStatus: p.PaymentStatus,
+		RentStatus:    p.Status,
+		Amount:        p.TotalAmount,
+		Currency:      p.Currency,
+	}
+}
+
+// IsPaymentAuthorized reports whether the money of the rent is secured, which is required to confirm it
+func IsPaymentAuthorized(paymentStatus string) bool {
+	return paymentStatus == PaymentStatusAuthorized || paymentStatus == PaymentStatusCaptured
+}
+
+// AuthorizePayment authorizes the price of a pending rent and confirms the rent once the payment is authorized.
+// The payment is marked pending before the provider is called, so a rent is never charged twice
+func (s *PaymentService) AuthorizePayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	slog.Info("Authorizing payment in service")
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := lockRentPayment(tx, rentID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	if payment.ClientID != caller.Id {
+		return nil, custom_errors.NewServiceForbiddenError("Access denied", "only the guest of the rent may pay for it")
+	}
+	if payment.Status != StatusPending {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Rent cannot be paid",
+			fmt.Sprintf("only pending rents are paid, the rent is %s", payment.Status))
+	}
+	if payment.PaymentStatus != PaymentStatusUnpaid && payment.PaymentStatus != PaymentStatusDeclined {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Rent cannot be paid",
+			fmt.Sprintf("the payment of the rent is already %s", payment.PaymentStatus))
+	}
+	if payment.TotalAmount <= 0 {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Rent cannot be paid", "the rent has no price")
+	}
+
+	if err := setPaymentStatus(tx, rentID, PaymentStatusPending, payment.PaymentID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	result, err := s.provider.Authorize(payment_provider.AuthorizeRequest{
+		Reference: rentID.String(),
+		Amount:    payment.TotalAmount,
+		Currency:  payment.Currency,
+	})
+	if err != nil {
+		// The outcome of a timed out payment is reported by the webhook, other failures allow to pay again
+		if !errors.Is(err, payment_provider.ErrTimeout) {
+			s.resetPendingPayment(rentID)
+		}
+		return nil, fmt.Errorf("failed to authorize payment: %w", err)
+	}
+	return s.settleAuthorization(rentID, result, caller)
+}
+
+// CapturePayment charges the authorized payment of the rent, it is done by the owner of the hotel
+func (s *PaymentService) CapturePayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	slog.Info("Capturing payment in service")
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := lockRentPayment(tx, rentID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, payment.HotelID); err != nil {
+		return nil, err
+	}
+	if payment.PaymentStatus != PaymentStatusAuthorized || payment.Status == StatusCancelled {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Payment cannot be captured",
+			fmt.Sprintf("the payment is %s and the rent is %s", payment.PaymentStatus, payment.Status))
+	}
+
+	if _, err := s.provider.Capture(payment.PaymentID, payment.TotalAmount); err != nil {
+		return nil, fmt.Errorf("failed to capture payment: %w", err)
+	}
+	if err := setPaymentStatus(tx, rentID, PaymentStatusCaptured, payment.PaymentID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	payment.PaymentStatus = PaymentStatusCaptured
+	return payment.response(), nil
+}
+
+// RefundPayment returns the refund settled by the cancellation of the rent to the guest. The refund is recorded
+// before the provider is called, if the provider fails the refund stays pending and the refund relay retries it
+func (s *PaymentService) RefundPayment(rentID uuid.UUID, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	slog.Info("Refunding payment in service")
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := lockRentPayment(tx, rentID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	if err := authorizeHotelOwner(s.hotelServiceBridge, caller, payment.HotelID); err != nil {
+		return nil, err
+	}
+	if payment.Status != StatusCancelled ||
+		(!IsPaymentAuthorized(payment.PaymentStatus) && payment.PaymentStatus != PaymentStatusRefundPending) {
+		return nil, custom_errors.NewServiceUnprocessableEntityError("Payment cannot be refunded",
+			"only the payment of a cancelled rent is refunded")
+	}
+
+	if payment.PaymentStatus != PaymentStatusRefundPending {
+		payment.PaymentStatus = PaymentStatusRefundPending
+		if err := setPaymentStatus(tx, rentID, payment.PaymentStatus, payment.PaymentID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if refunded, err := refundPayment(s.Db, s.provider, rentID); err != nil {
+		slog.Error(fmt.Sprintf("Failed to refund payment, the refund stays pending: %v", err))
+	} else if refunded != nil {
+		payment = refunded
+	}
+	payment.TotalAmount = payment.RefundAmount
+	return payment.response(), nil
+}
+
+// refundPayment returns the pending refund of the rent through the provider and returns the refunded payment.
+// The rent stays locked while the provider is called, so concurrent attempts refund it once. A refund made
+// by the provider before the commit failed is repeated safely, the provider returns the same refund again
+func refundPayment(database *db.Database, provider payment_provider.IPaymentProvider, rentID uuid.UUID) (*rentPayment, error) {
+	tx, err := database.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := lockRentPayment(tx, rentID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	// The refund was already made by a concurrent attempt
+	if payment.PaymentStatus != PaymentStatusRefundPending {
+		return payment, nil
+	}
+
+	if _, err := provider.Refund(payment.PaymentID, payment.RefundAmount); err != nil {
+		return nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+	payment.PaymentStatus = PaymentStatusRefunded
+	if err := setPaymentStatus(tx, rentID, payment.PaymentStatus, payment.PaymentID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return payment, nil
+}
+
+// HandleWebhook applies the outcome reported by the provider. Webhooks may be repeated,
+// an outcome which was already applied is ignored. An outcome for another amount than the one
+// of the rent is rejected
+func (s *PaymentService) HandleWebhook(payload []byte, signature string) error {
+	slog.Info("Handling payment webhook in service")
+	event, err := s.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+	rentID, err := uuid.Parse(event.Reference)
+	if err != nil {
+		return custom_errors.NewServiceBadRequestError("Invalid webhook", "the reference is not a rent ID")
+	}
+
+	switch event.Status {
+	case PaymentStatusAuthorized, PaymentStatusDeclined:
+		_, err = s.settleAuthorization(rentID, &payment_provider.Payment{
+			ID:        event.PaymentID,
+			Reference: event.Reference,
+			Status:    event.Status,
+			Amount:    event.Amount,
+		}, nil)
+		return err
+	case PaymentStatusCaptured, PaymentStatusRefunded:
+		return s.settleCharge(rentID, event)
+	default:
+		return custom_errors.NewServiceBadRequestError("Invalid webhook", "unknown payment status "+event.Status)
+	}
+}
+
+// settleAuthorization records the outcome of a pending payment and confirms the rent if the payment
+// was authorized. The guest is notified when the rent is confirmed while they are paying
+func (s *PaymentService) settleAuthorization(rentID uuid.UUID, result *payment_provider.Payment, caller *user_service.UserData) (*responses.PaymentResponse, error) {
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := lockRentPayment(tx, rentID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	// The outcome was already applied by the response of the provider or by an earlier webhook
+	if payment.PaymentStatus != PaymentStatusPending {
+		return payment.response(), nil
+	}
+	if result.Status == PaymentStatusAuthorized && result.Amount != payment.TotalAmount {
+		return nil, newAmountMismatchError(result.Amount, payment.TotalAmount)
+	}
+
+	payment.PaymentStatus = result.Status
+	payment.PaymentID = result.ID
+	// The rent was cancelled while the payment was pending, the authorized money goes back to the guest
+	if payment.PaymentStatus == PaymentStatusAuthorized && payment.Status == StatusCancelled {
+		payment.PaymentStatus = PaymentStatusRefundPending
+	}
+	if err := setPaymentStatus(tx, rentID, payment.PaymentStatus, payment.PaymentID); err != nil {
+		return nil, err
+	}
+
+	if payment.PaymentStatus == PaymentStatusAuthorized && payment.Status == StatusPending {
+		changedBy := uuid.Nil
+		if caller != nil {
+			changedBy = caller.Id
+		}
+		transition, err := changeStatus(tx, rentID, StatusConfirmed, changedBy, "payment authorized")
+		if err != nil {
+			return nil, err
+		}
+		payment.Status = StatusConfirmed
+
+		// A rent confirmed by a webhook is notified to the guest too, their contact data is fetched then
+		guest := caller
+		if guest == nil {
+			guest, err = s.userServiceBridge.GetUserContactDataByID(payment.ClientID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get guest contact data: %w", err)
+			}
+		}
+		if guest != nil {
+			rent, _, err := scanRentRow(tx.QueryRow(`
+				SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+				       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+				       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
+				FROM bookings b
+				WHERE b.id = $1`, rentID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch rent: %w", err)
+			}
+			notificationData := &notification_service.NotificationData{
+				EventType:       notification_service.EventBookingStatusChanged,
+				UserContactData: guest,
+				RentData:        rent,
+				Transition:      transition,
+			}
+			if err := enqueueNotification(tx, notificationData); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return payment.response(), nil
+}
+
+// settleCharge records a capture or a refund reported by the provider. The captured amount must be
+// the price of the rent and the refunded amount the refund settled by its cancellation
+func (s *PaymentService) settleCharge(rentID uuid.UUID, event *payment_provider.WebhookEvent) error {
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := lockRentPayment(tx, rentID)
+	if err != nil || payment == nil {
+		return err
+	}
+	// The event is for another payment of the rent, or the rent was not paid in a way it applies to.
+	// A pending refund may be reported before the refund relay has recorded it
+	applies := IsPaymentAuthorized(payment.PaymentStatus) ||
+		(payment.PaymentStatus == PaymentStatusRefundPending && event.Status == PaymentStatusRefunded)
+	if payment.PaymentID != event.PaymentID || !applies {
+		return nil
+	}
+	expectedAmount := payment.TotalAmount
+	if event.Status == PaymentStatusRefunded {
+		expectedAmount = payment.RefundAmount
+	}
+	if event.Amount != expectedAmount {
+		return newAmountMismatchError(event.Amount, expectedAmount)
+	}
+
+	if err := setPaymentStatus(tx, rentID, event.Status, payment.PaymentID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func newAmountMismatchError(amount int, expectedAmount int) error {
+	slog.Error(fmt.Sprintf("Payment provider reported %d instead of %d", amount, expectedAmount))
+	return custom_errors.NewServiceBadRequestError("Invalid payment amount",
+		fmt.Sprintf("the provider reported %d, the rent expects %d", amount, expectedAmount))
+}
+
+// resetPendingPayment allows to pay again after the provider failed to take the payment
+func (s *PaymentService) resetPendingPayment(rentID uuid.UUID) {
+	query := `UPDATE bookings SET payment_status = $2 WHERE id = $1 AND payment_status = $3`
+	if _, err := s.Db.Connection.Exec(query, rentID, PaymentStatusUnpaid, PaymentStatusPending); err != nil {
+		slog.Error(fmt.Sprintf("Failed to reset pending payment: %v", err))
+	}
+}
+
+func lockRentPayment(tx *sql.Tx, rentID uuid.UUID) (*rentPayment, error) {
+	query := `
+		SELECT b.hotel_id, b.client_id, b.status, COALESCE(b.total_amount, 0), COALESCE(b.currency, ''),
+		       COALESCE(b.refund_amount, 0), b.payment_status, COALESCE(b.payment_id, '')
+		FROM bookings b
+		WHERE b.id = $1
+		FOR UPDATE`
+	payment := &rentPayment{ID: rentID}
+	err := tx.QueryRow(query, rentID).Scan(&payment.HotelID, &payment.ClientID, &payment.Status, &payment.TotalAmount,
+		&payment.Currency, &payment.RefundAmount, &payment.PaymentStatus, &payment.PaymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch rent payment: %w", err)
+	}
+	return payment, nil
+}
+
+func setPaymentStatus(tx *sql.Tx, rentID uuid.UUID, paymentStatus string, paymentID string) error {
+	query := `UPDATE bookings SET payment_status = $2, payment_id = NULLIF($3, '') WHERE id = $1`
+	if _, err := tx.Exec(query, rentID, paymentStatus, paymentID); err != nil {
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+	return nil
+}
+
+// RefundRelay retries the refunds which are pending, such as the refunds of paid rents recorded by their
+// cancellation and the refunds the provider failed to make
+type RefundRelay struct {
+	Db       *db.Database
+	provider payment_provider.IPaymentProvider
+	cfg      *config.PaymentConfig
+}
+
+func NewRefundRelay(database *db.Database, provider payment_provider.IPaymentProvider, cfg *config.PaymentConfig) *RefundRelay {
+	return &RefundRelay{Db: database, provider: provider, cfg: cfg}
+}
+
+// Run makes the pending refunds until the context is cancelled
+func (r *RefundRelay) Run(ctx context.Context) {
+	slog.Info("Starting refund relay")
+	ticker := time.NewTicker(r.cfg.RefundInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Refund relay stopped")
+			return
+		case <-ticker.C:
+			if _, err := r.RelayBatch(); err != nil {
+				slog.Error(fmt.Sprintf("Failed to relay refunds: %v", err))
+			}
+		}
+	}
+}
+
+// RelayBatch makes a batch of pending refunds and returns how many of them are done, including the ones
+// a concurrent attempt has made meanwhile. A refund the provider fails to make stays pending until the next batch
+func (r *RefundRelay) RelayBatch() (int, error) {
+	query := `SELECT b.id FROM bookings b WHERE b.payment_status = $1 ORDER BY b.cancelled_at LIMIT $2`
+	rows, err := r.Db.Connection.Query(query, PaymentStatusRefundPending, r.cfg.RefundBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch pending refunds: %w", err)
+	}
+	var rentIDs []uuid.UUID
+	for rows.Next() {
+		var rentID uuid.UUID
+		if err := rows.Scan(&rentID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan pending refund: %w", err)
+		}
+		rentIDs = append(rentIDs, rentID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate over pending refunds: %w", err)
+	}
+
+	refunded := 0
+	for _, rentID := range rentIDs {
+		payment, err := refundPayment(r.Db, r.provider, rentID)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to refund payment of rent %s: %v", rentID, err))
+			continue
+		}
+		if payment != nil && payment.PaymentStatus == PaymentStatusRefunded {
+			refunded++
+		}
+	}
+	return refunded, nil
+}
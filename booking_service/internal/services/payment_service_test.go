@@ -0,0 +1,307 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/payment_provider"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var rentPaymentColumns = []string{"hotel_id", "client_id", "status", "total_amount", "currency", "refund_amount",
+	"payment_status", "payment_id"}
+
+func newPaymentService(t *testing.T, outcome string) (*services.PaymentService, *payment_provider.FakeProvider, sqlmock.Sqlmock, *MockHotelServiceBridge, *MockUserServiceBridge) {
+	db, mock := createMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	provider, err := payment_provider.NewFakeProvider(outcome, "secret")
+	assert.NoError(t, err)
+	bridgeMock := &MockHotelServiceBridge{}
+	userBridgeMock := &MockUserServiceBridge{}
+	paymentService := services.NewPaymentService(&db2.Database{Connection: db}, bridgeMock, userBridgeMock, provider)
+	return paymentService, provider, mock, bridgeMock, userBridgeMock
+}
+
+func expectRentPayment(mock sqlmock.Sqlmock, rentID uuid.UUID, hotelID uuid.UUID, clientID uuid.UUID, status string, paymentStatus string, paymentID string) {
+	mock.ExpectQuery(`SELECT b.hotel_id, b.client_id, b.status, COALESCE\(b.total_amount, 0\), COALESCE\(b.currency, ''\), COALESCE\(b.refund_amount, 0\), b.payment_status, COALESCE\(b.payment_id, ''\) FROM bookings b WHERE b.id = \$1 FOR UPDATE`).
+		WithArgs(rentID).
+		WillReturnRows(sqlmock.NewRows(rentPaymentColumns).
+			AddRow(hotelID, clientID, status, 2000_00, services.DefaultCurrency, 1500_00, paymentStatus, paymentID))
+}
+
+func expectPaymentStatus(mock sqlmock.Sqlmock, rentID uuid.UUID, paymentStatus string, paymentID string) {
+	mock.ExpectExec(`UPDATE bookings SET payment_status = \$2, payment_id = NULLIF\(\$3, ''\) WHERE id = \$1`).
+		WithArgs(rentID, paymentStatus, paymentID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestAuthorizePayment_Authorized_RentConfirmed(t *testing.T) {
+	paymentService, _, mock, _, _ := newPaymentService(t, payment_provider.OutcomeSucceed)
+	rent := newAccessTestRent()
+	caller := &user_service.UserData{Id: rent.ClientID}
+	paymentID := "fake_" + rent.ID.String()
+
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusUnpaid, "")
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusPending, "")
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusPending, "")
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusAuthorized, paymentID)
+	expectStatusChange(mock, rent.ID, services.StatusPending, services.StatusConfirmed, caller.Id, "payment authorized")
+	mock.ExpectQuery(`b.additional_guests, b.payment_status FROM bookings b WHERE b.id = \$1`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow(rent.ID, rent.HotelID, rent.RoomID, nil, rent.ClientID, rent.CheckInDate, rent.CheckOutDate, services.StatusConfirmed,
+				rent.NightPrice, rent.Nights, rent.Currency, rent.TotalAmount, rent.CreatedAt, rent.Version, 1, 0, "", nil, services.PaymentStatusAuthorized))
+	expectOutbox(mock, "booking_status_changed")
+	mock.ExpectCommit()
+
+	payment, err := paymentService.AuthorizePayment(rent.ID, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, paymentID, payment.PaymentID)
+	assert.Equal(t, services.PaymentStatusAuthorized, payment.PaymentStatus)
+	assert.Equal(t, services.StatusConfirmed, payment.RentStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthorizePayment_Declined_RentStaysPending(t *testing.T) {
+	paymentService, _, mock, _, _ := newPaymentService(t, payment_provider.OutcomeDecline)
+	rent := newAccessTestRent()
+	caller := &user_service.UserData{Id: rent.ClientID}
+	paymentID := "fake_" + rent.ID.String()
+
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusUnpaid, "")
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusPending, "")
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusPending, "")
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusDeclined, paymentID)
+	mock.ExpectCommit()
+
+	payment, err := paymentService.AuthorizePayment(rent.ID, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.PaymentStatusDeclined, payment.PaymentStatus)
+	assert.Equal(t, services.StatusPending, payment.RentStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthorizePayment_Timeout_SettledByWebhook(t *testing.T) {
+	paymentService, provider, mock, _, userBridgeMock := newPaymentService(t, payment_provider.OutcomeTimeout)
+	rent := newAccessTestRent()
+	caller := &user_service.UserData{Id: rent.ClientID, Email: "guest@example.com"}
+	paymentID := "fake_" + rent.ID.String()
+
+	// The payment stays pending, nobody may pay for the rent again until the provider reports the outcome
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusUnpaid, "")
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusPending, "")
+	mock.ExpectCommit()
+
+	payment, err := paymentService.AuthorizePayment(rent.ID, caller)
+
+	assert.Nil(t, payment)
+	assert.True(t, errors.Is(err, payment_provider.ErrTimeout))
+
+	// The guest is not making the request, so their contact data is fetched to notify them of the confirmation
+	userBridgeMock.On("GetUserContactDataByID", rent.ClientID).Return(caller, nil).Once()
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusPending, "")
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusAuthorized, paymentID)
+	expectStatusChange(mock, rent.ID, services.StatusPending, services.StatusConfirmed, uuid.Nil, "payment authorized")
+	mock.ExpectQuery(`b.additional_guests, b.payment_status FROM bookings b WHERE b.id = \$1`).
+		WithArgs(rent.ID).
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow(rent.ID, rent.HotelID, rent.RoomID, nil, rent.ClientID, rent.CheckInDate, rent.CheckOutDate, services.StatusConfirmed,
+				rent.NightPrice, rent.Nights, rent.Currency, rent.TotalAmount, rent.CreatedAt, rent.Version, 1, 0, "", nil, services.PaymentStatusAuthorized))
+	expectOutbox(mock, "booking_status_changed")
+	mock.ExpectCommit()
+
+	payload, signature, err := provider.Webhook(paymentID)
+	assert.NoError(t, err)
+	err = paymentService.HandleWebhook(payload, signature)
+
+	assert.NoError(t, err)
+	userBridgeMock.AssertExpectations(t)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandleWebhook_AuthorizedAmountMismatch_Rejected(t *testing.T) {
+	paymentService, provider, mock, _, _ := newPaymentService(t, payment_provider.OutcomeTimeout)
+	rent := newAccessTestRent()
+	paymentID := "fake_" + rent.ID.String()
+	// The provider authorized less than the price of the rent
+	_, err := provider.Authorize(payment_provider.AuthorizeRequest{Reference: rent.ID.String(), Amount: 1000_00})
+	assert.True(t, errors.Is(err, payment_provider.ErrTimeout))
+
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusPending, services.PaymentStatusPending, "")
+	mock.ExpectRollback()
+
+	payload, signature, err := provider.Webhook(paymentID)
+	assert.NoError(t, err)
+	err = paymentService.HandleWebhook(payload, signature)
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandleWebhook_CapturedAmountMismatch_Rejected(t *testing.T) {
+	paymentService, provider, mock, _, _ := newPaymentService(t, payment_provider.OutcomeSucceed)
+	rent := newAccessTestRent()
+	payment, _ := provider.Authorize(payment_provider.AuthorizeRequest{Reference: rent.ID.String(), Amount: 2000_00})
+	_, err := provider.Capture(payment.ID, 1000_00)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusConfirmed, services.PaymentStatusAuthorized, payment.ID)
+	mock.ExpectRollback()
+
+	payload, signature, err := provider.Webhook(payment.ID)
+	assert.NoError(t, err)
+	err = paymentService.HandleWebhook(payload, signature)
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthorizePayment_AlreadyAuthorized_UnprocessableEntity(t *testing.T) {
+	paymentService, _, mock, _, _ := newPaymentService(t, payment_provider.OutcomeSucceed)
+	rent := newAccessTestRent()
+
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusConfirmed, services.PaymentStatusAuthorized, "fake_1")
+	mock.ExpectRollback()
+
+	payment, err := paymentService.AuthorizePayment(rent.ID, &user_service.UserData{Id: rent.ClientID})
+
+	assert.Nil(t, payment)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefundPayment_CancelledRent_RefundAmountReturned(t *testing.T) {
+	paymentService, provider, mock, bridgeMock, _ := newPaymentService(t, payment_provider.OutcomeSucceed)
+	rent := newAccessTestRent()
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	payment, _ := provider.Authorize(payment_provider.AuthorizeRequest{Reference: rent.ID.String(), Amount: 2000_00})
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: owner.Id}, nil)
+	// The refund is recorded before the provider is called
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusCancelled, services.PaymentStatusAuthorized, payment.ID)
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusRefundPending, payment.ID)
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusCancelled, services.PaymentStatusRefundPending, payment.ID)
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusRefunded, payment.ID)
+	mock.ExpectCommit()
+
+	refund, err := paymentService.RefundPayment(rent.ID, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.PaymentStatusRefunded, refund.PaymentStatus)
+	assert.Equal(t, 1500_00, refund.Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefundPayment_ProviderFails_RefundStaysPending(t *testing.T) {
+	paymentService, _, mock, bridgeMock, _ := newPaymentService(t, payment_provider.OutcomeSucceed)
+	rent := newAccessTestRent()
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	// The provider does not know the payment, so it cannot refund it
+	paymentID := "fake_unknown"
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: owner.Id}, nil)
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusCancelled, services.PaymentStatusCaptured, paymentID)
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusRefundPending, paymentID)
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusCancelled, services.PaymentStatusRefundPending, paymentID)
+	mock.ExpectRollback()
+
+	refund, err := paymentService.RefundPayment(rent.ID, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.PaymentStatusRefundPending, refund.PaymentStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefundRelay_RelayBatch_PendingRefundsMade(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	provider, err := payment_provider.NewFakeProvider(payment_provider.OutcomeSucceed, "secret")
+	assert.NoError(t, err)
+	relay := services.NewRefundRelay(&db2.Database{Connection: db}, provider,
+		&config.PaymentConfig{RefundInterval: time.Minute, RefundBatchSize: 10})
+	rent := newAccessTestRent()
+	payment, _ := provider.Authorize(payment_provider.AuthorizeRequest{Reference: rent.ID.String(), Amount: 2000_00})
+	refundedRentID := uuid.New()
+
+	mock.ExpectQuery(`SELECT b.id FROM bookings b WHERE b.payment_status = \$1 ORDER BY b.cancelled_at LIMIT \$2`).
+		WithArgs(services.PaymentStatusRefundPending, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(rent.ID).AddRow(refundedRentID))
+	mock.ExpectBegin()
+	expectRentPayment(mock, rent.ID, rent.HotelID, rent.ClientID, services.StatusCancelled, services.PaymentStatusRefundPending, payment.ID)
+	expectPaymentStatus(mock, rent.ID, services.PaymentStatusRefunded, payment.ID)
+	mock.ExpectCommit()
+	// The second refund was made by a concurrent attempt in the meantime
+	mock.ExpectBegin()
+	expectRentPayment(mock, refundedRentID, rent.HotelID, rent.ClientID, services.StatusCancelled, services.PaymentStatusRefunded, "fake_2")
+	mock.ExpectRollback()
+
+	refunded, err := relay.RelayBatch()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, refunded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandleWebhook_ForgedSignature_Rejected(t *testing.T) {
+	paymentService, _, mock, _, _ := newPaymentService(t, payment_provider.OutcomeSucceed)
+
+	err := paymentService.HandleWebhook([]byte(`{"payment_id":"fake_1","reference":"1","status":"captured"}`), "forged")
+
+	assert.True(t, errors.Is(err, payment_provider.ErrInvalidSignature))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionRent_ConfirmUnpaidRent_UnprocessableEntity(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	rent := newAccessTestRent()
+	rent.Status = services.StatusPending
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+
+	bridgeMock.On("GetHotel", rent.HotelID).Return(&hotel_service.HotelData{ID: rent.HotelID, AdministratorID: owner.Id}, nil)
+	expectRentByID(mock, rent)
+
+	transition, err := bookingService.TransitionRent(rent.ID, requests.TransitionRentRequest{Status: services.StatusConfirmed}, owner)
+
+	assert.Nil(t, transition)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
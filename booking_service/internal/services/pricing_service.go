@@ -0,0 +1,85 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"fmt"
+	"log/slog"
+)
+
+type IPricingService interface {
+	Quote(request requests.QuoteRequest) (*responses.QuoteResponse, error)
+}
+
+type PricingService struct {
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	cfg                *config.PricingConfig
+}
+
+func NewPricingService(hotelServiceBridge hotel_service.IHotelServiceBridge, cfg *config.PricingConfig) *PricingService {
+	return &PricingService{hotelServiceBridge: hotelServiceBridge, cfg: cfg}
+}
+
+// Quote calculates the price of a stay. Rents are created with the same calculation,
+// so the quote shown to the guest always matches the booked price
+func (s *PricingService) Quote(request requests.QuoteRequest) (*responses.QuoteResponse, error) {
+	slog.Info("Quoting stay in service")
+	var nightPrice int
+	if request.RoomTypeID != nil {
+		roomType, err := s.hotelServiceBridge.GetRoomType(request.HotelID, *request.RoomTypeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get room type: %w", err)
+		}
+		if roomType == nil {
+			return nil, custom_errors.NewServiceBadRequestError("Room type not found", request.RoomTypeID.String())
+		}
+		nightPrice = roomType.NightPrice
+	} else {
+		price, err := s.hotelServiceBridge.GetHotelPrice(request.HotelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hotel price: %w", err)
+		}
+		nightPrice = price
+	}
+
+	nights := CountNights(request.CheckInDate, request.CheckOutDate)
+	breakdown := make([]responses.QuoteNightResponse, 0, nights)
+	subtotal := 0
+	for i := 0; i < nights; i++ {
+		breakdown = append(breakdown, responses.QuoteNightResponse{
+			Date:  request.CheckInDate.AddDate(0, 0, i).Format("2006-01-02"),
+			Price: nightPrice,
+		})
+		subtotal += nightPrice
+	}
+
+	// The discount of a promo code lowers the taxable price, the service fee is always charged in full
+	var promoCode string
+	var discount int
+	if request.Discount != nil {
+		promoCode = request.Discount.PromoCode
+		discount = calculateDiscount(*request.Discount, subtotal)
+	}
+
+	taxes := (subtotal - discount) * s.cfg.TaxPercent / 100
+	fees := s.cfg.ServiceFee
+	return &responses.QuoteResponse{
+		HotelID:      request.HotelID,
+		RoomTypeID:   request.RoomTypeID,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+		NightPrice:   nightPrice,
+		Nights:       nights,
+		Breakdown:    breakdown,
+		Subtotal:     subtotal,
+		PromoCode:    promoCode,
+		Discount:     discount,
+		Taxes:        taxes,
+		Fees:         fees,
+		Total:        subtotal - discount + taxes + fees,
+		Currency:     DefaultCurrency,
+	}, nil
+}
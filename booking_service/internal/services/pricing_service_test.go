@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/services"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestQuote_HotelPrice_WithTaxesAndFees(t *testing.T) {
+	bridgeMock := &MockHotelServiceBridge{}
+	pricingService := services.NewPricingService(bridgeMock, &config.PricingConfig{TaxPercent: 10, ServiceFee: 500_00})
+	request := requests.QuoteRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Date(2024, 12, 30, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000_00, nil)
+
+	quote, err := pricingService.Quote(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, quote.Nights)
+	assert.Equal(t, []string{"2024-12-30", "2024-12-31", "2025-01-01"},
+		[]string{quote.Breakdown[0].Date, quote.Breakdown[1].Date, quote.Breakdown[2].Date})
+	assert.Equal(t, 3000_00, quote.Subtotal)
+	assert.Equal(t, 300_00, quote.Taxes)
+	assert.Equal(t, 500_00, quote.Fees)
+	assert.Equal(t, 3800_00, quote.Total)
+	assert.Equal(t, services.DefaultCurrency, quote.Currency)
+}
+
+func TestQuote_PercentDiscount_TaxedAfterDiscount(t *testing.T) {
+	bridgeMock := &MockHotelServiceBridge{}
+	pricingService := services.NewPricingService(bridgeMock, &config.PricingConfig{TaxPercent: 10, ServiceFee: 500_00})
+	request := requests.QuoteRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2024, 12, 22, 12, 0, 0, 0, time.UTC),
+		Discount:     &requests.QuoteDiscount{PromoCode: "WINTER", Type: services.DiscountTypePercent, Value: 25},
+	}
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000_00, nil)
+
+	quote, err := pricingService.Quote(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2000_00, quote.Subtotal)
+	assert.Equal(t, "WINTER", quote.PromoCode)
+	assert.Equal(t, 500_00, quote.Discount)
+	assert.Equal(t, 150_00, quote.Taxes)
+	assert.Equal(t, 2150_00, quote.Total)
+}
+
+func TestQuote_FixedDiscountAbovePrice_NightsFree(t *testing.T) {
+	bridgeMock := &MockHotelServiceBridge{}
+	pricingService := services.NewPricingService(bridgeMock, &config.PricingConfig{TaxPercent: 10, ServiceFee: 500_00})
+	request := requests.QuoteRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC),
+		Discount:     &requests.QuoteDiscount{PromoCode: "GIFT", Type: services.DiscountTypeFixed, Value: 5000_00},
+	}
+
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000_00, nil)
+
+	quote, err := pricingService.Quote(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000_00, quote.Discount)
+	assert.Equal(t, 0, quote.Taxes)
+	// The service fee is charged in full
+	assert.Equal(t, 500_00, quote.Total)
+}
+
+func TestQuote_RoomType_UseRoomTypePrice(t *testing.T) {
+	bridgeMock := &MockHotelServiceBridge{}
+	pricingService := services.NewPricingService(bridgeMock, &config.PricingConfig{})
+	roomTypeID := uuid.New()
+	request := requests.QuoteRequest{
+		HotelID:      uuid.New(),
+		RoomTypeID:   &roomTypeID,
+		CheckInDate:  time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC),
+	}
+
+	bridgeMock.On("GetRoomType", request.HotelID, roomTypeID).
+		Return(&hotel_service.RoomTypeData{ID: roomTypeID, HotelID: request.HotelID, NightPrice: 2500_00}, nil)
+
+	quote, err := pricingService.Quote(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, quote.Nights)
+	assert.Equal(t, 2500_00, quote.NightPrice)
+	assert.Equal(t, 2500_00, quote.Total)
+}
+
+func TestQuote_UnknownRoomType_BadRequest(t *testing.T) {
+	bridgeMock := &MockHotelServiceBridge{}
+	pricingService := services.NewPricingService(bridgeMock, &config.PricingConfig{})
+	roomTypeID := uuid.New()
+	request := requests.QuoteRequest{
+		HotelID:      uuid.New(),
+		RoomTypeID:   &roomTypeID,
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+	}
+
+	bridgeMock.On("GetRoomType", request.HotelID, roomTypeID).Return((*hotel_service.RoomTypeData)(nil), nil)
+
+	quote, err := pricingService.Quote(request)
+
+	assert.Nil(t, quote)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+}
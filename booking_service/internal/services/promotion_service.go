@@ -0,0 +1,367 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Kinds of discounts: a percentage of the price of the nights or a fixed amount taken off it
+const (
+	DiscountTypePercent = "percent"
+	DiscountTypeFixed   = "fixed"
+)
+
+type IPromotionService interface {
+	CreatePromoCode(request requests.SetPromoCodeRequest, caller *user_service.UserData) (*responses.PromoCodeResponse, error)
+	GetPromoCodes(hotelID *uuid.UUID, caller *user_service.UserData) ([]responses.PromoCodeResponse, error)
+	GetPromoCode(promoCodeID uuid.UUID, caller *user_service.UserData) (*responses.PromoCodeResponse, error)
+	UpdatePromoCode(promoCodeID uuid.UUID, request requests.SetPromoCodeRequest, caller *user_service.UserData) (*responses.PromoCodeResponse, error)
+	DeletePromoCode(promoCodeID uuid.UUID, caller *user_service.UserData) (*responses.PromoCodeResponse, error)
+}
+
+type PromotionService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	cfg                *config.PromotionConfig
+}
+
+func NewPromotionService(
+	database *db.Database,
+	hotelServiceBridge hotel_service.IHotelServiceBridge,
+	cfg *config.PromotionConfig) *PromotionService {
+	return &PromotionService{Db: database, hotelServiceBridge: hotelServiceBridge, cfg: cfg}
+}
+
+const promoCodeColumns = `p.id, p.code, p.hotel_id, p.discount_type, p.discount_value, p.valid_from, p.valid_until,
+		       p.max_uses, p.used_count, p.min_nights, p.created_at`
+
+func (s *PromotionService) CreatePromoCode(request requests.SetPromoCodeRequest, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	slog.Info("Creating promo code in service")
+	if err := s.authorizePromoCode(caller, request.HotelID); err != nil {
+		return nil, err
+	}
+
+	promoCode := &responses.PromoCodeResponse{
+		Code:          normalizePromoCode(request.Code),
+		HotelID:       request.HotelID,
+		DiscountType:  request.DiscountType,
+		DiscountValue: request.DiscountValue,
+		ValidFrom:     request.ValidFrom,
+		ValidUntil:    request.ValidUntil,
+		MaxUses:       request.MaxUses,
+		MinNights:     request.MinNights,
+	}
+	query := `
+		INSERT INTO promo_codes (code, hotel_id, discount_type, discount_value, valid_from, valid_until, max_uses, min_nights)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, used_count, created_at`
+	err := s.Db.Connection.QueryRow(query, promoCode.Code, request.HotelID, request.DiscountType, request.DiscountValue,
+		request.ValidFrom, request.ValidUntil, request.MaxUses, request.MinNights).
+		Scan(&promoCode.ID, &promoCode.UsedCount, &promoCode.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, newPromoCodeTakenError(promoCode.Code)
+		}
+		return nil, fmt.Errorf("failed to create promo code: %w", err)
+	}
+	return promoCode, nil
+}
+
+// GetPromoCodes returns the codes of the hotel, or the global codes if the hotel is not given
+func (s *PromotionService) GetPromoCodes(hotelID *uuid.UUID, caller *user_service.UserData) ([]responses.PromoCodeResponse, error) {
+	slog.Info("Getting promo codes in service")
+	if err := s.authorizePromoCode(caller, hotelID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + promoCodeColumns + `
+		FROM promo_codes p
+		WHERE p.hotel_id IS NOT DISTINCT FROM $1
+		ORDER BY p.created_at, p.id`
+	rows, err := s.Db.Connection.Query(query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve promo codes: %w", err)
+	}
+	defer rows.Close()
+
+	promoCodes := []responses.PromoCodeResponse{}
+	for rows.Next() {
+		promoCode, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promo code: %w", err)
+		}
+		promoCodes = append(promoCodes, *promoCode)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over promo codes: %w", err)
+	}
+	return promoCodes, nil
+}
+
+func (s *PromotionService) GetPromoCode(promoCodeID uuid.UUID, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	slog.Info("Getting promo code in service")
+	promoCode, err := getPromoCode(s.Db.Connection, promoCodeID)
+	if err != nil || promoCode == nil {
+		return nil, err
+	}
+	if err := s.authorizePromoCode(caller, promoCode.HotelID); err != nil {
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+// UpdatePromoCode replaces the terms of the code, the hotel of the code and its usage are kept
+func (s *PromotionService) UpdatePromoCode(promoCodeID uuid.UUID, request requests.SetPromoCodeRequest, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	slog.Info("Updating promo code in service")
+	promoCode, err := getPromoCode(s.Db.Connection, promoCodeID)
+	if err != nil || promoCode == nil {
+		return nil, err
+	}
+	if err := s.authorizePromoCode(caller, promoCode.HotelID); err != nil {
+		return nil, err
+	}
+	if request.HotelID != nil && (promoCode.HotelID == nil || *request.HotelID != *promoCode.HotelID) {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid promo code", "the hotel of a promo code cannot be changed")
+	}
+
+	promoCode.Code = normalizePromoCode(request.Code)
+	promoCode.DiscountType = request.DiscountType
+	promoCode.DiscountValue = request.DiscountValue
+	promoCode.ValidFrom = request.ValidFrom
+	promoCode.ValidUntil = request.ValidUntil
+	promoCode.MaxUses = request.MaxUses
+	promoCode.MinNights = request.MinNights
+	query := `
+		UPDATE promo_codes
+		SET code = $2, discount_type = $3, discount_value = $4, valid_from = $5, valid_until = $6,
+		    max_uses = $7, min_nights = $8
+		WHERE id = $1
+		RETURNING used_count`
+	err = s.Db.Connection.QueryRow(query, promoCodeID, promoCode.Code, request.DiscountType, request.DiscountValue,
+		request.ValidFrom, request.ValidUntil, request.MaxUses, request.MinNights).Scan(&promoCode.UsedCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if isUniqueViolation(err) {
+			return nil, newPromoCodeTakenError(promoCode.Code)
+		}
+		// The usage limit cannot be lowered below the number of times the code was already used
+		if isCheckViolation(err, "chk_promo_code_usage") {
+			return nil, custom_errors.NewServiceUnprocessableEntityError("Invalid promo code",
+				"the promo code was used more times than the new usage limit")
+		}
+		return nil, fmt.Errorf("failed to update promo code: %w", err)
+	}
+	return promoCode, nil
+}
+
+// DeletePromoCode removes the code, the rents booked with it keep their discounted price
+func (s *PromotionService) DeletePromoCode(promoCodeID uuid.UUID, caller *user_service.UserData) (*responses.PromoCodeResponse, error) {
+	slog.Info("Deleting promo code in service")
+	promoCode, err := getPromoCode(s.Db.Connection, promoCodeID)
+	if err != nil || promoCode == nil {
+		return nil, err
+	}
+	if err := s.authorizePromoCode(caller, promoCode.HotelID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Db.Connection.Exec(`DELETE FROM promo_codes WHERE id = $1`, promoCodeID); err != nil {
+		return nil, fmt.Errorf("failed to delete promo code: %w", err)
+	}
+	return promoCode, nil
+}
+
+// authorizePromoCode allows the owner of the hotel to manage its codes and the configured administrators
+// to manage the global codes
+func (s *PromotionService) authorizePromoCode(caller *user_service.UserData, hotelID *uuid.UUID) error {
+	if hotelID != nil {
+		return authorizeHotelOwner(s.hotelServiceBridge, caller, *hotelID)
+	}
+	if !slices.Contains(s.cfg.AdminIDs, caller.Id) {
+		return custom_errors.NewServiceForbiddenError("Access denied", "only administrators may manage global promo codes")
+	}
+	return nil
+}
+
+func getPromoCode(querier rowQuerier, promoCodeID uuid.UUID) (*responses.PromoCodeResponse, error) {
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_codes p WHERE p.id = $1`
+	promoCode, err := scanPromoCode(querier.QueryRow(query, promoCodeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch promo code: %w", err)
+	}
+	return promoCode, nil
+}
+
+func findPromoCode(querier rowQuerier, code string) (*responses.PromoCodeResponse, error) {
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_codes p WHERE upper(p.code) = $1`
+	promoCode, err := scanPromoCode(querier.QueryRow(query, normalizePromoCode(code)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch promo code: %w", err)
+	}
+	return promoCode, nil
+}
+
+func scanPromoCode(row rowScanner) (*responses.PromoCodeResponse, error) {
+	var promoCode responses.PromoCodeResponse
+	err := row.Scan(&promoCode.ID, &promoCode.Code, &promoCode.HotelID, &promoCode.DiscountType, &promoCode.DiscountValue,
+		&promoCode.ValidFrom, &promoCode.ValidUntil, &promoCode.MaxUses, &promoCode.UsedCount, &promoCode.MinNights,
+		&promoCode.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &promoCode, nil
+}
+
+// resolvePromoCode checks that the code may be used for the stay and returns the discount to quote it with
+func (s *BookingService) resolvePromoCode(request requests.CreateRentRequest) (*responses.PromoCodeResponse, *requests.QuoteDiscount, error) {
+	promoCode, err := findPromoCode(s.Db.Connection, request.PromoCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if promoCode == nil {
+		return nil, nil, newInvalidPromoCodeError("the promo code does not exist")
+	}
+
+	now := time.Now()
+	switch {
+	case promoCode.HotelID != nil && *promoCode.HotelID != request.HotelID:
+		return nil, nil, newInvalidPromoCodeError("the promo code is not valid for this hotel")
+	case promoCode.ValidFrom != nil && now.Before(*promoCode.ValidFrom):
+		return nil, nil, newInvalidPromoCodeError("the promo code is not valid yet")
+	case promoCode.ValidUntil != nil && !now.Before(*promoCode.ValidUntil):
+		return nil, nil, newInvalidPromoCodeError("the promo code has expired")
+	case CountNights(request.CheckInDate, request.CheckOutDate) < promoCode.MinNights:
+		return nil, nil, newInvalidPromoCodeError(fmt.Sprintf("the promo code requires a stay of at least %d nights", promoCode.MinNights))
+	case promoCode.MaxUses != nil && promoCode.UsedCount >= *promoCode.MaxUses:
+		return nil, nil, newPromoCodeUsedUpError()
+	}
+
+	return promoCode, &requests.QuoteDiscount{
+		PromoCode: promoCode.Code,
+		Type:      promoCode.DiscountType,
+		Value:     promoCode.DiscountValue,
+	}, nil
+}
+
+// redeemPromoCode records the use of the code by the rent. The usage is increased by a conditional update,
+// concurrent bookings wait for the row lock and then recheck the limit, so it is never exceeded
+func redeemPromoCode(tx *sql.Tx, promoCode *responses.PromoCodeResponse, rentID uuid.UUID, discount int) error {
+	query := `
+		UPDATE promo_codes SET used_count = used_count + 1
+		WHERE id = $1 AND (max_uses IS NULL OR used_count < max_uses)
+		  AND (valid_from IS NULL OR valid_from <= now()) AND (valid_until IS NULL OR valid_until > now())`
+	result, err := tx.Exec(query, promoCode.ID)
+	if err != nil {
+		return fmt.Errorf("failed to use promo code: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check promo code usage: %w", err)
+	}
+	if rowsAffected == 0 {
+		return newPromoCodeUsedUpError()
+	}
+
+	// The terms are stored with the redemption, the rent is repriced with them even if the code changes
+	query = `
+		INSERT INTO promo_code_redemptions (booking_id, promo_code_id, code, discount_type, discount_value,
+		                                    min_nights, discount_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := tx.Exec(query, rentID, promoCode.ID, promoCode.Code, promoCode.DiscountType,
+		promoCode.DiscountValue, promoCode.MinNights, discount); err != nil {
+		return fmt.Errorf("failed to record promo code usage: %w", err)
+	}
+	return nil
+}
+
+// redeemedDiscount is the promo code a rent was booked with
+type redeemedDiscount struct {
+	Terms     requests.QuoteDiscount
+	MinNights int
+}
+
+// getRedeemedDiscount returns the terms of the promo code the rent was booked with, or nil if it was booked
+// without one
+func getRedeemedDiscount(tx *sql.Tx, rentID uuid.UUID) (*redeemedDiscount, error) {
+	query := `
+		SELECT r.code, r.discount_type, r.discount_value, r.min_nights
+		FROM promo_code_redemptions r
+		WHERE r.booking_id = $1`
+	var discount redeemedDiscount
+	err := tx.QueryRow(query, rentID).
+		Scan(&discount.Terms.PromoCode, &discount.Terms.Type, &discount.Terms.Value, &discount.MinNights)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch rent discount: %w", err)
+	}
+	return &discount, nil
+}
+
+// updateRedeemedDiscount records the discount of the modified rent, the usage of the code does not change
+func updateRedeemedDiscount(tx *sql.Tx, rentID uuid.UUID, discount int) error {
+	query := `UPDATE promo_code_redemptions SET discount_amount = $2 WHERE booking_id = $1`
+	if _, err := tx.Exec(query, rentID, discount); err != nil {
+		return fmt.Errorf("failed to update rent discount: %w", err)
+	}
+	return nil
+}
+
+// calculateDiscount returns the amount taken off the price of the nights, it never exceeds the price
+func calculateDiscount(discount requests.QuoteDiscount, subtotal int) int {
+	if discount.Type == DiscountTypePercent {
+		return subtotal * discount.Value / 100
+	}
+	return min(discount.Value, subtotal)
+}
+
+// normalizePromoCode makes the codes case-insensitive, guests type them by hand
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func newInvalidPromoCodeError(details string) error {
+	return custom_errors.NewServiceUnprocessableEntityError("Invalid promo code", details)
+}
+
+func newPromoCodeUsedUpError() error {
+	return custom_errors.NewServiceUnprocessableEntityError("Invalid promo code", "the promo code has been used up")
+}
+
+func newPromoCodeTakenError(code string) error {
+	return custom_errors.NewServiceUnprocessableEntityError("Promo code already exists", code)
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isCheckViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23514" && pqErr.Constraint == constraint
+}
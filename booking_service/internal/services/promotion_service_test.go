@@ -0,0 +1,255 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var promoCodeColumns = []string{"id", "code", "hotel_id", "discount_type", "discount_value", "valid_from", "valid_until",
+	"max_uses", "used_count", "min_nights", "created_at"}
+
+func TestCreatePromoCode_HotelOwner_Created(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	promotionService := services.NewPromotionService(&db2.Database{Connection: db}, bridgeMock, &config.PromotionConfig{})
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	hotelID := uuid.New()
+	maxUses := 100
+	request := requests.SetPromoCodeRequest{
+		Code:          " winter ",
+		HotelID:       &hotelID,
+		DiscountType:  services.DiscountTypePercent,
+		DiscountValue: 10,
+		MaxUses:       &maxUses,
+		MinNights:     2,
+	}
+	promoCodeID := uuid.New()
+
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, AdministratorID: owner.Id}, nil)
+	mock.ExpectQuery(`INSERT INTO promo_codes \(code, hotel_id, discount_type, discount_value, valid_from, valid_until, max_uses, min_nights\)`).
+		WithArgs("WINTER", &hotelID, services.DiscountTypePercent, 10, nil, nil, &maxUses, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "used_count", "created_at"}).AddRow(promoCodeID, 0, time.Now()))
+
+	promoCode, err := promotionService.CreatePromoCode(request, owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, promoCodeID, promoCode.ID)
+	assert.Equal(t, "WINTER", promoCode.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePromoCode_GlobalByOwner_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	adminID := uuid.New()
+	promotionService := services.NewPromotionService(&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&config.PromotionConfig{AdminIDs: []uuid.UUID{adminID}})
+	request := requests.SetPromoCodeRequest{Code: "WELCOME", DiscountType: services.DiscountTypeFixed, DiscountValue: 500_00}
+
+	promoCode, err := promotionService.CreatePromoCode(request, &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner})
+
+	assert.Nil(t, promoCode)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePromoCode_CodeTaken_UnprocessableEntity(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	adminID := uuid.New()
+	promotionService := services.NewPromotionService(&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&config.PromotionConfig{AdminIDs: []uuid.UUID{adminID}})
+	request := requests.SetPromoCodeRequest{Code: "WELCOME", DiscountType: services.DiscountTypeFixed, DiscountValue: 500_00}
+
+	mock.ExpectQuery(`INSERT INTO promo_codes`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	promoCode, err := promotionService.CreatePromoCode(request, &user_service.UserData{Id: adminID})
+
+	assert.Nil(t, promoCode)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePromoCode_LimitBelowUsage_UnprocessableEntity(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	adminID := uuid.New()
+	promotionService := services.NewPromotionService(&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&config.PromotionConfig{AdminIDs: []uuid.UUID{adminID}})
+	promoCodeID := uuid.New()
+	maxUses := 1
+	request := requests.SetPromoCodeRequest{Code: "WELCOME", DiscountType: services.DiscountTypeFixed, DiscountValue: 500_00,
+		MaxUses: &maxUses}
+
+	mock.ExpectQuery(`FROM promo_codes p WHERE p.id = \$1`).
+		WithArgs(promoCodeID).
+		WillReturnRows(sqlmock.NewRows(promoCodeColumns).
+			AddRow(promoCodeID, "WELCOME", nil, services.DiscountTypeFixed, 500_00, nil, nil, nil, 5, 0, time.Now()))
+	mock.ExpectQuery(`UPDATE promo_codes SET code = \$2, discount_type = \$3, discount_value = \$4, valid_from = \$5, valid_until = \$6, max_uses = \$7, min_nights = \$8 WHERE id = \$1 RETURNING used_count`).
+		WithArgs(promoCodeID, "WELCOME", services.DiscountTypeFixed, 500_00, nil, nil, &maxUses, 0).
+		WillReturnError(&pq.Error{Code: "23514", Constraint: "chk_promo_code_usage"})
+
+	promoCode, err := promotionService.UpdatePromoCode(promoCodeID, request, &user_service.UserData{Id: adminID})
+
+	assert.Nil(t, promoCode)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePromoCode_AnotherHotelOwner_Forbidden(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	promotionService := services.NewPromotionService(&db2.Database{Connection: db}, bridgeMock, &config.PromotionConfig{})
+	promoCodeID, hotelID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`FROM promo_codes p WHERE p.id = \$1`).
+		WithArgs(promoCodeID).
+		WillReturnRows(sqlmock.NewRows(promoCodeColumns).
+			AddRow(promoCodeID, "WINTER", hotelID, services.DiscountTypePercent, 10, nil, nil, nil, 0, 0, time.Now()))
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, AdministratorID: uuid.New()}, nil)
+
+	promoCode, err := promotionService.DeletePromoCode(promoCodeID, &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner})
+
+	assert.Nil(t, promoCode)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_WithPromoCode_DiscountAppliedAndUsageRecorded(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(48 * time.Hour),
+		PromoCode:    "winter",
+	}
+	caller := &user_service.UserData{Id: uuid.New()}
+	promoCodeID, rentID := uuid.New(), uuid.New()
+	nightPrice := 1000_00
+
+	mock.ExpectQuery(`FROM promo_codes p WHERE upper\(p.code\) = \$1`).
+		WithArgs("WINTER").
+		WillReturnRows(sqlmock.NewRows(promoCodeColumns).
+			AddRow(promoCodeID, "WINTER", request.HotelID, services.DiscountTypePercent, 10, nil, nil, 10, 3, 2, time.Now()))
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(nightPrice, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(request.HotelID, caller.Id, request.CheckInDate, request.CheckOutDate, nil, nightPrice, 2,
+			services.DefaultCurrency, 1800_00, 0, 0, 1, 0, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).
+			AddRow(rentID, uuid.New(), nil, time.Now()))
+	mock.ExpectExec(`UPDATE promo_codes SET used_count = used_count \+ 1 WHERE id = \$1 AND \(max_uses IS NULL OR used_count < max_uses\)`).
+		WithArgs(promoCodeID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO promo_code_redemptions \(booking_id, promo_code_id, code, discount_type, discount_value,\s+min_nights, discount_amount\)`).
+		WithArgs(rentID, promoCodeID, "WINTER", services.DiscountTypePercent, 10, 2, 200_00).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "booking_created")
+	mock.ExpectCommit()
+
+	id, err := bookingService.CreateRent(request, caller, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, rentID, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_PromoCodeUsedUpConcurrently_NothingBooked(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		PromoCode:    "LAST",
+	}
+	promoCodeID := uuid.New()
+
+	// The last use was free when the code was checked and is taken by another booking before this one commits
+	mock.ExpectQuery(`FROM promo_codes p WHERE upper\(p.code\) = \$1`).
+		WithArgs("LAST").
+		WillReturnRows(sqlmock.NewRows(promoCodeColumns).
+			AddRow(promoCodeID, "LAST", nil, services.DiscountTypeFixed, 100_00, nil, nil, 1, 0, 0, time.Now()))
+	bridgeMock.On("GetHotelPrice", request.HotelID).Return(1000_00, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "room_type_id", "created_at"}).
+			AddRow(uuid.New(), uuid.New(), nil, time.Now()))
+	mock.ExpectExec(`UPDATE promo_codes SET used_count = used_count \+ 1`).
+		WithArgs(promoCodeID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	id, err := bookingService.CreateRent(request, &user_service.UserData{Id: uuid.New()}, "")
+
+	assert.Equal(t, uuid.Nil, id)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRent_PromoCodeOfAnotherHotel_UnprocessableEntity(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, &MockHotelServiceBridge{},
+		&MockCancellationPolicyService{},
+		services.NewPricingService(&MockHotelServiceBridge{}, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+
+	request := requests.CreateRentRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		PromoCode:    "WINTER",
+	}
+
+	mock.ExpectQuery(`FROM promo_codes p WHERE upper\(p.code\) = \$1`).
+		WithArgs("WINTER").
+		WillReturnRows(sqlmock.NewRows(promoCodeColumns).
+			AddRow(uuid.New(), "WINTER", uuid.New(), services.DiscountTypePercent, 10, nil, nil, nil, 0, 0, time.Now()))
+
+	id, err := bookingService.CreateRent(request, &user_service.UserData{Id: uuid.New()}, "")
+
+	assert.Equal(t, uuid.Nil, id)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceUnprocessableEntityError)))
+	assert.Contains(t, err.Error(), "not valid for this hotel")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// authorizeRent allows the client of the rent and the owner of its hotel to access it
+func (s *BookingService) authorizeRent(caller *user_service.UserData, rent *responses.GetRentResponse) error {
+	if rent.ClientID == caller.Id {
+		return nil
+	}
+	return s.authorizeHotel(caller, rent.HotelID)
+}
+
+// authorizeHotel allows only the owner administering the hotel
+func (s *BookingService) authorizeHotel(caller *user_service.UserData, hotelID uuid.UUID) error {
+	return authorizeHotelOwner(s.hotelServiceBridge, caller, hotelID)
+}
+
+func authorizeHotelOwner(hotelServiceBridge hotel_service.IHotelServiceBridge, caller *user_service.UserData, hotelID uuid.UUID) error {
+	if !caller.IsOwner() {
+		return custom_errors.NewServiceForbiddenError("Access denied", "only the hotel owner may do it")
+	}
+
+	hotel, err := hotelServiceBridge.GetHotel(hotelID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch hotel: %w", err)
+	}
+	if hotel == nil || hotel.AdministratorID != caller.Id {
+		return custom_errors.NewServiceForbiddenError("Access denied", "the hotel is administered by another owner")
+	}
+	return nil
+}
+
+// restrictRentFilter narrows the filter to the rents the caller may see: guests see their own rents,
+// owners see their own rents and the rents of the hotels they administer
+func (s *BookingService) restrictRentFilter(filter *requests.RentFilter, caller *user_service.UserData) error {
+	if filter.ClientID != uuid.Nil && filter.ClientID == caller.Id {
+		return nil
+	}
+
+	if !caller.IsOwner() {
+		if filter.ClientID != uuid.Nil {
+			return custom_errors.NewServiceForbiddenError("Access denied", "guests may list only their own rents")
+		}
+		filter.ClientID = caller.Id
+		return nil
+	}
+
+	return s.restrictToAdministeredHotels(filter, caller)
+}
+
+// restrictExportFilter narrows the filter of an export to the hotels of the owner. Unlike the list of rents,
+// the own stays of an owner at hotels of other owners are not exported
+func (s *BookingService) restrictExportFilter(filter *requests.RentFilter, caller *user_service.UserData) error {
+	if !caller.IsOwner() {
+		return custom_errors.NewServiceForbiddenError("Access denied", "only hotel owners may export rents")
+	}
+	return s.restrictToAdministeredHotels(filter, caller)
+}
+
+// restrictToAdministeredHotels narrows the filter of the owner to the hotels the owner administers
+func (s *BookingService) restrictToAdministeredHotels(filter *requests.RentFilter, caller *user_service.UserData) error {
+	if filter.HotelID != uuid.Nil {
+		return s.authorizeHotel(caller, filter.HotelID)
+	}
+
+	hotelIDs, err := s.hotelServiceBridge.GetHotelsByAdministrator(caller.Id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch hotels of the owner: %w", err)
+	}
+	if hotelIDs == nil {
+		hotelIDs = []uuid.UUID{}
+	}
+	filter.HotelIDs = hotelIDs
+	return nil
+}
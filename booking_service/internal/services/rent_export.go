@@ -0,0 +1,83 @@
+package services
+
+import (
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/user_service"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// ExportRents passes the rents of the hotels of the owner which match the filter to write one by one,
+// in the order of check-in. The rows are read from the connection as they are written, so the export
+// is never held in memory. The export stops at the first error of write
+func (s *BookingService) ExportRents(ctx context.Context, filter requests.RentFilter, caller *user_service.UserData,
+	write func(rent *responses.ExportedRentResponse) error) error {
+	slog.Info("Exporting rents in service")
+	if err := s.restrictExportFilter(&filter, caller); err != nil {
+		return err
+	}
+	// An owner without hotels has no rents to export
+	if filter.HotelIDs != nil && len(filter.HotelIDs) == 0 {
+		return nil
+	}
+	condition, params, _, err := rentFilterCondition(filter)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		SELECT b.id, b.hotel_id, b.room_id, b.room_type_id, b.client_id, b.check_in_date, b.check_out_date, b.status,
+		       b.night_price, b.nights, b.currency, b.total_amount, b.created_at,
+		       b.version, b.adults, b.children, b.primary_guest_name, b.additional_guests, b.payment_status
+		FROM bookings b
+		WHERE 1=1` + condition + `
+		ORDER BY b.check_in_date, b.id`
+	rows, err := s.Db.Connection.QueryContext(ctx, query, params...)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rent, hasSnapshot, err := scanRentRow(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan rent: %w", err)
+		}
+		if err := write(newExportedRent(rent, hasSnapshot)); err != nil {
+			return fmt.Errorf("failed to write rent: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate over rents: %w", err)
+	}
+	return nil
+}
+
+// newExportedRent keeps the prices of the rent only if they were stored with it
+func newExportedRent(rent *responses.GetRentResponse, hasSnapshot bool) *responses.ExportedRentResponse {
+	exported := &responses.ExportedRentResponse{
+		ID:               rent.ID,
+		HotelID:          rent.HotelID,
+		RoomID:           rent.RoomID,
+		RoomTypeID:       rent.RoomTypeID,
+		ClientID:         rent.ClientID,
+		CheckInDate:      rent.CheckInDate,
+		CheckOutDate:     rent.CheckOutDate,
+		Status:           rent.Status,
+		PaymentStatus:    rent.PaymentStatus,
+		Adults:           rent.Adults,
+		Children:         rent.Children,
+		PrimaryGuestName: rent.PrimaryGuestName,
+		CreatedAt:        rent.CreatedAt,
+		Version:          rent.Version,
+	}
+	if hasSnapshot {
+		exported.NightPrice = &rent.NightPrice
+		exported.Nights = &rent.Nights
+		exported.Currency = &rent.Currency
+		exported.TotalAmount = &rent.TotalAmount
+	}
+	return exported
+}
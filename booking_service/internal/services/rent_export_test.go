@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var exportedRentColumns = []string{"id", "hotel_id", "room_id", "room_type_id", "client_id", "check_in_date",
+	"check_out_date", "status", "night_price", "nights", "currency", "total_amount", "created_at", "version", "adults",
+	"children", "primary_guest_name", "additional_guests", "payment_status"}
+
+func newExportBookingService(t *testing.T) (*services.BookingService, sqlmock.Sqlmock, *MockHotelServiceBridge) {
+	db, mock := createMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	bridgeMock := &MockHotelServiceBridge{}
+	bookingService := services.NewBookingService(
+		&db2.Database{Connection: db}, bridgeMock,
+		&MockCancellationPolicyService{},
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.IdempotencyConfig{KeyTTL: time.Hour}, &config.HoldConfig{TTL: 10 * time.Minute}, &MockWaitlistService{})
+	return bookingService, mock, bridgeMock
+}
+
+func TestExportRents_Owner_StoredPricesWritten(t *testing.T) {
+	bookingService, mock, bridgeMock := newExportBookingService(t)
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	hotelIDs := []uuid.UUID{uuid.New()}
+	bridgeMock.On("GetHotelsByAdministrator", owner.Id).Return(hotelIDs, nil)
+
+	now := time.Now()
+	pricedID, legacyID := uuid.New(), uuid.New()
+	mock.ExpectQuery(`FROM bookings b\s+WHERE 1=1 AND b.hotel_id = ANY\(\$1\) AND b.status = \$2\s+ORDER BY b.check_in_date, b.id`).
+		WithArgs(pq.Array(hotelIDs), services.StatusCheckedOut).
+		WillReturnRows(sqlmock.NewRows(exportedRentColumns).
+			AddRow(pricedID, hotelIDs[0], uuid.New(), nil, uuid.New(), now, now.Add(48*time.Hour), services.StatusCheckedOut,
+				1000_00, 2, services.DefaultCurrency, 2000_00, now, 1, 2, 0, "Ivan Petrov", nil, "paid").
+			AddRow(legacyID, hotelIDs[0], uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusCheckedOut,
+				nil, nil, nil, nil, now, 1, 1, 0, "", nil, "unpaid"))
+
+	var exported []*responses.ExportedRentResponse
+	err := bookingService.ExportRents(context.Background(), requests.RentFilter{Status: services.StatusCheckedOut}, owner,
+		func(rent *responses.ExportedRentResponse) error {
+			exported = append(exported, rent)
+			return nil
+		})
+
+	assert.NoError(t, err)
+	assert.Len(t, exported, 2)
+	assert.Equal(t, pricedID, exported[0].ID)
+	assert.Equal(t, 2000_00, *exported[0].TotalAmount)
+	assert.Equal(t, services.DefaultCurrency, *exported[0].Currency)
+	// Rents without stored prices are not priced at the current prices
+	assert.Equal(t, legacyID, exported[1].ID)
+	assert.Nil(t, exported[1].NightPrice)
+	assert.Nil(t, exported[1].TotalAmount)
+	bridgeMock.AssertNotCalled(t, "GetHotelPrices")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRents_OwnerFilteringOwnStays_RestrictedToAdministeredHotels(t *testing.T) {
+	bookingService, mock, bridgeMock := newExportBookingService(t)
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	hotelIDs := []uuid.UUID{uuid.New()}
+	bridgeMock.On("GetHotelsByAdministrator", owner.Id).Return(hotelIDs, nil)
+
+	mock.ExpectQuery(`FROM bookings b\s+WHERE 1=1 AND b.client_id = \$1 AND b.status <> 'blocked' AND b.hotel_id = ANY\(\$2\)`).
+		WithArgs(owner.Id, pq.Array(hotelIDs)).
+		WillReturnRows(sqlmock.NewRows(exportedRentColumns))
+
+	err := bookingService.ExportRents(context.Background(), requests.RentFilter{ClientID: owner.Id}, owner,
+		func(rent *responses.ExportedRentResponse) error { return nil })
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRents_Guest_Forbidden(t *testing.T) {
+	bookingService, mock, _ := newExportBookingService(t)
+	guest := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleGuest}
+
+	err := bookingService.ExportRents(context.Background(), requests.RentFilter{ClientID: guest.Id}, guest,
+		func(rent *responses.ExportedRentResponse) error { return nil })
+
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceForbiddenError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRents_WriteFailed_ExportStopped(t *testing.T) {
+	bookingService, mock, bridgeMock := newExportBookingService(t)
+	owner := &user_service.UserData{Id: uuid.New(), Role: user_service.RoleOwner}
+	hotelID := uuid.New()
+	bridgeMock.On("GetHotel", hotelID).Return(&hotel_service.HotelData{ID: hotelID, AdministratorID: owner.Id}, nil)
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM bookings b\s+WHERE 1=1 AND b.hotel_id = \$1\s+ORDER BY b.check_in_date, b.id`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows(exportedRentColumns).
+			AddRow(uuid.New(), hotelID, uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusConfirmed,
+				nil, nil, nil, nil, now, 1, 1, 0, "", nil, "unpaid").
+			AddRow(uuid.New(), hotelID, uuid.New(), nil, uuid.New(), now, now.Add(24*time.Hour), services.StatusConfirmed,
+				nil, nil, nil, nil, now, 1, 1, 0, "", nil, "unpaid"))
+
+	written := 0
+	err := bookingService.ExportRents(context.Background(), requests.RentFilter{HotelID: hotelID}, owner,
+		func(rent *responses.ExportedRentResponse) error {
+			written++
+			return errors.New("connection closed")
+		})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, written)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
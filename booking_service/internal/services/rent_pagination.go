@@ -0,0 +1,119 @@
+package services
+
+import (
+	custom_errors "booking_service/internal/errors"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Sort options of the rents list
+const (
+	SortByCheckInDate = "check_in_date"
+	SortByCreatedAt   = "created_at"
+	SortOrderAsc      = "asc"
+	SortOrderDesc     = "desc"
+)
+
+// Page sizes of the rents list
+const (
+	DefaultRentsLimit = 50
+	MaxRentsLimit     = 200
+)
+
+// Ways the stay of a rent is matched against the from-to window of the rents list. The window includes
+// its start and excludes its end, either end of it may be left open
+const (
+	// DateModeOverlaps matches the rents with at least a part of the stay in the window, e.g. the guests in-house
+	DateModeOverlaps = "overlaps"
+	// DateModeWithin matches the rents with the whole stay in the window
+	DateModeWithin = "within"
+	// DateModeStartsIn matches the rents arriving in the window
+	DateModeStartsIn = "starts_in"
+	// DateModeEndsIn matches the rents departing in the window
+	DateModeEndsIn = "ends_in"
+)
+
+// IsValidDateMode reports whether the rents list may match the stays in this way
+func IsValidDateMode(mode string) bool {
+	return mode == DateModeOverlaps || mode == DateModeWithin || mode == DateModeStartsIn || mode == DateModeEndsIn
+}
+
+// rentDateCondition matches the stay of the rent against the window. The stay is compared as a range,
+// so overlap and containment are served by the range index of the stays. An open end of the window is NULL,
+// which leaves the range unbounded
+func rentDateCondition(mode string, from *time.Time, to *time.Time, params []interface{}, counter int) (string, []interface{}, int) {
+	switch mode {
+	case DateModeStartsIn, DateModeEndsIn:
+		column := "b.check_in_date"
+		if mode == DateModeEndsIn {
+			column = "b.check_out_date"
+		}
+		condition := ""
+		if from != nil {
+			condition += fmt.Sprintf(" AND %s >= $%d", column, counter)
+			params = append(params, from)
+			counter++
+		}
+		if to != nil {
+			condition += fmt.Sprintf(" AND %s < $%d", column, counter)
+			params = append(params, to)
+			counter++
+		}
+		return condition, params, counter
+	}
+
+	operator := "&&"
+	if mode == DateModeWithin {
+		operator = "<@"
+	}
+	condition := fmt.Sprintf(" AND tstzrange(b.check_in_date, b.check_out_date) %s tstzrange($%d::timestamptz, $%d::timestamptz)",
+		operator, counter, counter+1)
+	return condition, append(params, from, to), counter + 2
+}
+
+// IsValidSortBy reports whether the rents list may be sorted by the field
+func IsValidSortBy(sortBy string) bool {
+	return sortBy == SortByCheckInDate || sortBy == SortByCreatedAt
+}
+
+// IsValidSortOrder reports whether the sort order is known
+func IsValidSortOrder(sortOrder string) bool {
+	return sortOrder == SortOrderAsc || sortOrder == SortOrderDesc
+}
+
+// rentCursor is the position after the last rent of a page. It remembers the sort
+// it was made for, so it cannot be used to continue a list sorted in another way
+type rentCursor struct {
+	SortBy    string    `json:"sort_by"`
+	SortOrder string    `json:"sort_order"`
+	Value     time.Time `json:"value"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func encodeRentCursor(cursor rentCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeRentCursor(encoded string, sortBy string, sortOrder string) (*rentCursor, error) {
+	invalidCursorError := custom_errors.NewServiceBadRequestError("Invalid cursor", "")
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalidCursorError
+	}
+
+	var cursor rentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, invalidCursorError
+	}
+	if cursor.SortBy != sortBy || cursor.SortOrder != sortOrder {
+		return nil, custom_errors.NewServiceBadRequestError("Invalid cursor", "the cursor was made for another sort")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"booking_service/internal/config"
+	"booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/metrics"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/rest/dtos/responses"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/notification_service"
+	"booking_service/internal/service_interaction/user_service"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// Statuses of the waitlist entries
+const (
+	WaitlistStatusWaiting = "waiting"
+	WaitlistStatusOffered = "offered"
+	WaitlistStatusExpired = "expired"
+)
+
+type IWaitlistService interface {
+	JoinWaitlist(request requests.JoinWaitlistRequest, caller *user_service.UserData) (*responses.WaitlistEntryResponse, error)
+	// OfferFreedCapacity offers holds to the oldest waiting entries of the hotel whose stay has a free room
+	// and returns how many offers were made
+	OfferFreedCapacity(hotelID uuid.UUID) (int, error)
+}
+
+type WaitlistService struct {
+	Db                 *db.Database
+	hotelServiceBridge hotel_service.IHotelServiceBridge
+	pricingService     IPricingService
+	holdCfg            *config.HoldConfig
+}
+
+func NewWaitlistService(
+	database *db.Database,
+	hotelServiceBridge hotel_service.IHotelServiceBridge,
+	pricingService IPricingService,
+	holdCfg *config.HoldConfig) *WaitlistService {
+	return &WaitlistService{
+		Db:                 database,
+		hotelServiceBridge: hotelServiceBridge,
+		pricingService:     pricingService,
+		holdCfg:            holdCfg}
+}
+
+type waitlistEntry struct {
+	ID           uuid.UUID
+	ClientID     uuid.UUID
+	ClientEmail  string
+	ClientPhone  string
+	RoomTypeID   *uuid.UUID
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+}
+
+func (s *WaitlistService) JoinWaitlist(request requests.JoinWaitlistRequest, caller *user_service.UserData) (*responses.WaitlistEntryResponse, error) {
+	slog.Info("Joining waitlist in service")
+	if request.RoomTypeID != nil {
+		roomType, err := s.hotelServiceBridge.GetRoomType(request.HotelID, *request.RoomTypeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get room type: %w", err)
+		}
+		if roomType == nil {
+			return nil, custom_errors.NewServiceBadRequestError("Room type not found", request.RoomTypeID.String())
+		}
+	}
+
+	entry := &responses.WaitlistEntryResponse{
+		HotelID:      request.HotelID,
+		RoomTypeID:   request.RoomTypeID,
+		CheckInDate:  request.CheckInDate,
+		CheckOutDate: request.CheckOutDate,
+	}
+	query := `
+		INSERT INTO waitlist_entries (client_id, client_email, client_phone, hotel_id, room_type_id,
+		                              check_in_date, check_out_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at`
+	err := s.Db.Connection.QueryRow(query, caller.Id, caller.Email, caller.Phone, request.HotelID,
+		request.RoomTypeID, request.CheckInDate, request.CheckOutDate).
+		Scan(&entry.ID, &entry.Status, &entry.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return entry, nil
+}
+
+// OfferFreedCapacity goes through the waiting entries of the hotel from the oldest one and offers a hold to every
+// entry whose stay has a free room. Entries locked by a concurrent offering are skipped, that offering handles them
+func (s *WaitlistService) OfferFreedCapacity(hotelID uuid.UUID) (int, error) {
+	slog.Info("Offering freed capacity to waitlist in service")
+	tx, err := s.Db.Connection.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entries, err := getWaitingEntries(tx, hotelID)
+	if err != nil {
+		return 0, err
+	}
+
+	offered := 0
+	for _, entry := range entries {
+		quote, err := s.pricingService.Quote(requests.QuoteRequest{
+			HotelID:      hotelID,
+			RoomTypeID:   entry.RoomTypeID,
+			CheckInDate:  entry.CheckInDate,
+			CheckOutDate: entry.CheckOutDate,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to quote waitlist offer: %w", err)
+		}
+
+		expiresAt := time.Now().Add(s.holdCfg.OfferTTL)
+		hold := requests.CreateHoldRequest{
+			HotelID:      hotelID,
+			RoomTypeID:   entry.RoomTypeID,
+			CheckInDate:  entry.CheckInDate,
+			CheckOutDate: entry.CheckOutDate,
+		}
+		// A hold rejected by the exclusion constraint aborts the transaction, so every entry is offered
+		// behind a savepoint to keep the offers made to the older entries
+		if _, err := tx.Exec(`SAVEPOINT waitlist_offer`); err != nil {
+			return 0, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		holdID, err := insertHold(tx, hold, entry.ClientID, quote, expiresAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) || isExclusionViolation(err) {
+				// The freed rooms do not fit the stay of the entry, a younger entry may still get one
+				if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT waitlist_offer`); err != nil {
+					return 0, fmt.Errorf("failed to roll back to savepoint: %w", err)
+				}
+				continue
+			}
+			return 0, fmt.Errorf("failed to create waitlist offer: %w", err)
+		}
+		if _, err := tx.Exec(`RELEASE SAVEPOINT waitlist_offer`); err != nil {
+			return 0, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+
+		query := `UPDATE waitlist_entries SET status = $2, hold_id = $3, offered_at = now() WHERE id = $1`
+		if _, err := tx.Exec(query, entry.ID, WaitlistStatusOffered, holdID); err != nil {
+			return 0, fmt.Errorf("failed to mark waitlist entry as offered: %w", err)
+		}
+
+		notificationData := &notification_service.NotificationData{
+			EventType: notification_service.EventWaitlistOffer,
+			UserContactData: &user_service.UserData{
+				Id:    entry.ClientID,
+				Email: entry.ClientEmail,
+				Phone: entry.ClientPhone,
+			},
+			RentData: &responses.GetRentResponse{
+				ID:           holdID,
+				HotelID:      hotelID,
+				RoomTypeID:   entry.RoomTypeID,
+				ClientID:     entry.ClientID,
+				CheckInDate:  entry.CheckInDate,
+				CheckOutDate: entry.CheckOutDate,
+				Status:       StatusHeld,
+				NightPrice:   quote.NightPrice,
+				Nights:       quote.Nights,
+				Currency:     quote.Currency,
+				TotalAmount:  quote.Total,
+			},
+			Offer: &responses.WaitlistOfferResponse{
+				EntryID:   entry.ID,
+				HoldID:    holdID,
+				ExpiresAt: expiresAt,
+			},
+		}
+		if err := enqueueNotification(tx, notificationData); err != nil {
+			return 0, err
+		}
+		offered++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	metrics.WaitlistOffersTotal.Add(float64(offered))
+	return offered, nil
+}
+
+// getWaitingEntries locks the waiting entries of the hotel whose stay has not begun, the oldest one first
+func getWaitingEntries(tx *sql.Tx, hotelID uuid.UUID) ([]waitlistEntry, error) {
+	query := `
+		SELECT w.id, w.client_id, w.client_email, w.client_phone, w.room_type_id, w.check_in_date, w.check_out_date
+		FROM waitlist_entries w
+		WHERE w.hotel_id = $1 AND w.status = 'waiting' AND w.check_in_date > now()
+		ORDER BY w.created_at
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve waitlist entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []waitlistEntry
+	for rows.Next() {
+		var entry waitlistEntry
+		if err := rows.Scan(&entry.ID, &entry.ClientID, &entry.ClientEmail, &entry.ClientPhone, &entry.RoomTypeID,
+			&entry.CheckInDate, &entry.CheckOutDate); err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over waitlist entries: %w", err)
+	}
+	return entries, nil
+}
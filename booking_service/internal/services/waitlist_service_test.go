@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"booking_service/internal/config"
+	db2 "booking_service/internal/db"
+	custom_errors "booking_service/internal/errors"
+	"booking_service/internal/rest/dtos/requests"
+	"booking_service/internal/service_interaction/hotel_service"
+	"booking_service/internal/service_interaction/user_service"
+	"booking_service/internal/services"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var waitlistEntryColumns = []string{"id", "client_id", "client_email", "client_phone", "room_type_id",
+	"check_in_date", "check_out_date"}
+
+func newWaitlistService(db *sql.DB, bridgeMock *MockHotelServiceBridge) *services.WaitlistService {
+	return services.NewWaitlistService(&db2.Database{Connection: db}, bridgeMock,
+		services.NewPricingService(bridgeMock, &config.PricingConfig{}),
+		&config.HoldConfig{TTL: 10 * time.Minute, OfferTTL: 2 * time.Hour})
+}
+
+func TestJoinWaitlist_CommonCase_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	waitlistService := newWaitlistService(db, &MockHotelServiceBridge{})
+	caller := &user_service.UserData{Id: uuid.New(), Email: "guest@example.com", Phone: "123"}
+	request := requests.JoinWaitlistRequest{
+		HotelID:      uuid.New(),
+		CheckInDate:  time.Now().Add(48 * time.Hour),
+		CheckOutDate: time.Now().Add(72 * time.Hour),
+	}
+	entryID := uuid.New()
+
+	mock.ExpectQuery(`INSERT INTO waitlist_entries \(client_id, client_email, client_phone, hotel_id, room_type_id, check_in_date, check_out_date\)`).
+		WithArgs(caller.Id, caller.Email, caller.Phone, request.HotelID, nil, request.CheckInDate, request.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).
+			AddRow(entryID, services.WaitlistStatusWaiting, time.Now()))
+
+	entry, err := waitlistService.JoinWaitlist(request, caller)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entryID, entry.ID)
+	assert.Equal(t, services.WaitlistStatusWaiting, entry.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJoinWaitlist_UnknownRoomType_BadRequest(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistService := newWaitlistService(db, bridgeMock)
+	roomTypeID := uuid.New()
+	request := requests.JoinWaitlistRequest{
+		HotelID:      uuid.New(),
+		RoomTypeID:   &roomTypeID,
+		CheckInDate:  time.Now().Add(48 * time.Hour),
+		CheckOutDate: time.Now().Add(72 * time.Hour),
+	}
+
+	bridgeMock.On("GetRoomType", request.HotelID, roomTypeID).Return((*hotel_service.RoomTypeData)(nil), nil)
+
+	entry, err := waitlistService.JoinWaitlist(request, &user_service.UserData{Id: uuid.New()})
+
+	assert.Nil(t, entry)
+	assert.True(t, errors.As(err, new(*custom_errors.ServiceBadRequestError)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOfferFreedCapacity_OldestFittingEntryOffered(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistService := newWaitlistService(db, bridgeMock)
+	hotelID := uuid.New()
+	nightPrice := 1000_00
+	checkInDate := time.Now().Add(48 * time.Hour)
+	oldestEntryID, youngerEntryID := uuid.New(), uuid.New()
+	oldestClientID, youngerClientID := uuid.New(), uuid.New()
+	holdID := uuid.New()
+
+	bridgeMock.On("GetHotelPrice", hotelID).Return(nightPrice, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM waitlist_entries w WHERE w.hotel_id = \$1 AND w.status = 'waiting' AND w.check_in_date > now\(\) ORDER BY w.created_at FOR UPDATE SKIP LOCKED`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows(waitlistEntryColumns).
+			AddRow(oldestEntryID, oldestClientID, "oldest@example.com", "", nil, checkInDate, checkInDate.Add(72*time.Hour)).
+			AddRow(youngerEntryID, youngerClientID, "younger@example.com", "", nil, checkInDate, checkInDate.Add(24*time.Hour)))
+	// The freed room is too short for the stay of the oldest entry
+	mock.ExpectExec(`SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(hotelID, oldestClientID, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nightPrice, 3,
+			services.DefaultCurrency, 3*nightPrice, 0, 0, sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WithArgs(hotelID, youngerClientID, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nightPrice, 1,
+			services.DefaultCurrency, nightPrice, 0, 0, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holdID))
+	mock.ExpectExec(`RELEASE SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE waitlist_entries SET status = \$2, hold_id = \$3, offered_at = now\(\) WHERE id = \$1`).
+		WithArgs(youngerEntryID, services.WaitlistStatusOffered, holdID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "waitlist_offer")
+	mock.ExpectCommit()
+
+	offered, err := waitlistService.OfferFreedCapacity(hotelID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, offered)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOfferFreedCapacity_HoldRejectedByConstraint_YoungerEntryOffered(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistService := newWaitlistService(db, bridgeMock)
+	hotelID := uuid.New()
+	nightPrice := 1000_00
+	checkInDate := time.Now().Add(48 * time.Hour)
+	oldestEntryID, youngerEntryID := uuid.New(), uuid.New()
+	holdID := uuid.New()
+
+	bridgeMock.On("GetHotelPrice", hotelID).Return(nightPrice, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM waitlist_entries w`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows(waitlistEntryColumns).
+			AddRow(oldestEntryID, uuid.New(), "oldest@example.com", "", nil, checkInDate, checkInDate.Add(24*time.Hour)).
+			AddRow(youngerEntryID, uuid.New(), "younger@example.com", "", nil, checkInDate, checkInDate.Add(24*time.Hour)))
+	// A concurrent rent took the room first, the aborted hold is rolled back so the transaction goes on
+	mock.ExpectExec(`SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO bookings`).WillReturnError(&pq.Error{Code: "23P01"})
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO bookings`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holdID))
+	mock.ExpectExec(`RELEASE SAVEPOINT waitlist_offer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE waitlist_entries SET status`).
+		WithArgs(youngerEntryID, services.WaitlistStatusOffered, holdID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutbox(mock, "waitlist_offer")
+	mock.ExpectCommit()
+
+	offered, err := waitlistService.OfferFreedCapacity(hotelID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, offered)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOfferFreedCapacity_NoWaitingEntries(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	bridgeMock := &MockHotelServiceBridge{}
+	waitlistService := newWaitlistService(db, bridgeMock)
+	hotelID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM waitlist_entries w`).
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows(waitlistEntryColumns))
+	mock.ExpectCommit()
+
+	offered, err := waitlistService.OfferFreedCapacity(hotelID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, offered)
+	bridgeMock.AssertNotCalled(t, "GetHotelPrice", hotelID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -26,7 +26,7 @@ func StartApp() {
 
 	defer tracing.ShutdownTracerProvider(context.Background(), cfg.TracerProvider)
 
-	server.NewServer(cfg.ServerConfig, cfg.HotelService)
+	server.NewServer(cfg.ServerConfig, cfg.HotelService, cfg.RoomTypeService)
 }
 
 func loadEnv() error {
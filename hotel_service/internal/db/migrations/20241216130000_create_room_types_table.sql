@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE room_types (
+    id UUID PRIMARY KEY,
+    hotel_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    capacity INT NOT NULL,
+    bed_configuration TEXT NOT NULL,
+    rooms_count INT NOT NULL,
+    night_price INT NOT NULL,
+    CONSTRAINT fk_hotel FOREIGN KEY (hotel_id) REFERENCES hotels (id) ON DELETE CASCADE,
+    CONSTRAINT chk_room_type_capacity CHECK (capacity > 0),
+    CONSTRAINT chk_room_type_rooms_count CHECK (rooms_count >= 0),
+    CONSTRAINT chk_room_type_night_price CHECK (night_price > 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS room_types;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change of a hotel increments its version, so a change based on a stale copy can be detected
+ALTER TABLE hotels
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE hotels
+    DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+package requests
+
+type UpdateRoomTypeRequest struct {
+	Name             string `json:"name"`
+	Capacity         int    `json:"capacity"`
+	BedConfiguration string `json:"bed_configuration"`
+	RoomsCount       int    `json:"rooms_count"`
+	NightPrice       int    `json:"night_price"`
+}
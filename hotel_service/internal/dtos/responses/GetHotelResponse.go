@@ -7,4 +7,5 @@ type GetHotelResponse struct {
 	HotelName  string    `json:"hotel_name"`
 	NightPrice int       `json:"night_price"`
 	AdminId    uuid.UUID `json:"admin_id"`
+	Version    int       `json:"version"`
 }
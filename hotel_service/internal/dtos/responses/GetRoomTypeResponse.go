@@ -0,0 +1,13 @@
+package responses
+
+import "github.com/google/uuid"
+
+type GetRoomTypeResponse struct {
+	Id               uuid.UUID `json:"id"`
+	HotelId          uuid.UUID `json:"hotel_id"`
+	Name             string    `json:"name"`
+	Capacity         int       `json:"capacity"`
+	BedConfiguration string    `json:"bed_configuration"`
+	RoomsCount       int       `json:"rooms_count"`
+	NightPrice       int       `json:"night_price"`
+}
@@ -0,0 +1,5 @@
+package responses
+
+type GetRoomTypesResponse struct {
+	RoomTypes []GetRoomTypeResponse `json:"room_types"`
+}
@@ -12,9 +12,10 @@ import (
 )
 
 type CommonConfiguration struct {
-	ServerConfig   *config.ServerConfig
-	HotelService   *services.HotelService
-	TracerProvider *trace.TracerProvider
+	ServerConfig    *config.ServerConfig
+	HotelService    *services.HotelService
+	RoomTypeService *services.RoomTypeService
+	TracerProvider  *trace.TracerProvider
 }
 
 func NewCommonConfiguration() (*CommonConfiguration, error) {
@@ -41,6 +42,7 @@ func NewCommonConfiguration() (*CommonConfiguration, error) {
 	slog.Info("Connection to database established")
 
 	hotelService := services.NewHotelService(db)
+	roomTypeService := services.NewRoomTypeService(db)
 
 	// Register metrics
 	metrics.Register()
@@ -48,8 +50,9 @@ func NewCommonConfiguration() (*CommonConfiguration, error) {
 
 	slog.Info("Common configuration was successfully created")
 	return &CommonConfiguration{
-		ServerConfig:   cfg,
-		HotelService:   hotelService,
-		TracerProvider: tracerProvider,
+		ServerConfig:    cfg,
+		HotelService:    hotelService,
+		RoomTypeService: roomTypeService,
+		TracerProvider:  tracerProvider,
 	}, nil
 }
@@ -18,9 +18,9 @@ import (
 	"time"
 )
 
-func NewServer(cfg *config.ServerConfig, hotelService services.IHotelService) {
+func NewServer(cfg *config.ServerConfig, hotelService services.IHotelService, roomTypeService services.IRoomTypeService) {
 	slog.Info("Starting a server")
-	router := SetupApiRouter(cfg, hotelService)
+	router := SetupApiRouter(cfg, hotelService, roomTypeService)
 
 	// Server configuration
 	srv := &http.Server{
@@ -29,7 +29,7 @@ func NewServer(cfg *config.ServerConfig, hotelService services.IHotelService) {
 	}
 
 	// gRPC Server setup
-	grpcHotelService := service_interaction.NewBookingServiceBridge(hotelService)
+	grpcHotelService := service_interaction.NewBookingServiceBridge(hotelService, roomTypeService)
 	grpcServer := grpc.NewServer()
 	pb.RegisterHotelServiceServer(grpcServer, grpcHotelService)
 
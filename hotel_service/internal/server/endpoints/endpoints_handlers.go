@@ -2,11 +2,14 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"hotel_service/internal/dtos/requests"
 	"hotel_service/internal/services"
+	"io"
 	"net/http"
+	"strings"
 )
 
 func CreateHotelHandler(service services.IHotelService) http.HandlerFunc {
@@ -53,7 +56,20 @@ func UpdateHotelHandler(service services.IHotelService) http.HandlerFunc {
 			return
 		}
 
-		if err := service.Update(hotelID, req); err != nil {
+		version, ok := getIfMatchVersion(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.Update(hotelID, req, version); err != nil {
+			if errors.Is(err, services.ErrHotelNotFound) {
+				http.Error(w, "Hotel with given id does not exist", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, services.ErrVersionMismatch) {
+				http.Error(w, "Hotel was modified", http.StatusPreconditionFailed)
+				return
+			}
 			http.Error(w, "Failed to update hotel", http.StatusInternalServerError)
 			return
 		}
@@ -62,6 +78,61 @@ func UpdateHotelHandler(service services.IHotelService) http.HandlerFunc {
 	}
 }
 
+// PatchHotelHandler updates only the fields of the hotel present in the JSON merge patch
+func PatchHotelHandler(service services.IHotelService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		if contentType != "" && !strings.HasPrefix(contentType, "application/merge-patch+json") &&
+			!strings.HasPrefix(contentType, "application/json") {
+			http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		patch, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			return
+		}
+
+		version, ok := getIfMatchVersion(w, r)
+		if !ok {
+			return
+		}
+
+		res, err := service.PatchHotel(hotelID, patch, version)
+		if err != nil {
+			if errors.Is(err, services.ErrHotelNotFound) {
+				http.Error(w, "Hotel with given id does not exist", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, services.ErrVersionMismatch) {
+				http.Error(w, "Hotel was modified", http.StatusPreconditionFailed)
+				return
+			}
+			if errors.Is(err, services.ErrInvalidPatch) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "Failed to update hotel", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", formatETag(res.Version))
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 func GetHotelHandler(service services.IHotelService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -79,9 +150,11 @@ func GetHotelHandler(service services.IHotelService) http.HandlerFunc {
 
 		if res == nil {
 			http.Error(w, "Hotel does not exist", http.StatusNotFound)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", formatETag(res.Version))
 
 		if err := json.NewEncoder(w).Encode(res); err != nil {
 			// Handle encoding error
@@ -128,7 +201,16 @@ func DeleteHotelHandler(service services.IHotelService) http.HandlerFunc {
 			return
 		}
 
-		if err := service.DeleteHotel(hotelID); err != nil {
+		version, ok := getIfMatchVersion(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeleteHotel(hotelID, version); err != nil {
+			if errors.Is(err, services.ErrVersionMismatch) {
+				http.Error(w, "Hotel was modified", http.StatusPreconditionFailed)
+				return
+			}
 			http.Error(w, "Failed to delete hotel", http.StatusInternalServerError)
 			return
 		}
@@ -0,0 +1,30 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The entity tag of a hotel is its version in quotes
+func formatETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// getIfMatchVersion answers 428 to a request without If-Match and 412 to one whose tag is not a version
+func getIfMatchVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+		return 0, false
+	}
+
+	tag, err := strconv.Unquote(ifMatch)
+	if err == nil && strings.HasPrefix(ifMatch, `"`) {
+		if version, err := strconv.Atoi(tag); err == nil {
+			return version, true
+		}
+	}
+	http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+	return 0, false
+}
@@ -0,0 +1,175 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"hotel_service/internal/dtos/requests"
+	"hotel_service/internal/services"
+	"net/http"
+	"strings"
+)
+
+func CreateRoomTypeHandler(service services.IRoomTypeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req requests.CreateRoomTypeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			return
+		}
+
+		if msg := validateRoomType(req.Name, req.Capacity, req.RoomsCount, req.NightPrice); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+
+		id, err := service.CreateRoomType(hotelID, req)
+		if err != nil {
+			http.Error(w, "Failed to create room type", http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(id); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func UpdateRoomTypeHandler(service services.IRoomTypeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req requests.UpdateRoomTypeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		hotelID, roomTypeID, ok := parseRoomTypePath(w, r)
+		if !ok {
+			return
+		}
+
+		if msg := validateRoomType(req.Name, req.Capacity, req.RoomsCount, req.NightPrice); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+
+		if err := service.UpdateRoomType(hotelID, roomTypeID, req); err != nil {
+			if errors.Is(err, services.ErrRoomTypeNotFound) {
+				http.Error(w, "Room type with given id does not exist", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Failed to update room type", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func GetRoomTypeHandler(service services.IRoomTypeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hotelID, roomTypeID, ok := parseRoomTypePath(w, r)
+		if !ok {
+			return
+		}
+
+		res, err := service.GetRoomTypeByID(hotelID, roomTypeID)
+		if err != nil {
+			http.Error(w, "Failed to get room type", http.StatusInternalServerError)
+			return
+		}
+
+		if res == nil {
+			http.Error(w, "Room type does not exist", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func GetRoomTypesHandler(service services.IRoomTypeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		hotelID, err := uuid.Parse(vars["hotel_id"])
+		if err != nil {
+			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+			return
+		}
+
+		res, err := service.GetRoomTypes(hotelID)
+		if err != nil {
+			http.Error(w, "Failed to fetch room types", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func DeleteRoomTypeHandler(service services.IRoomTypeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hotelID, roomTypeID, ok := parseRoomTypePath(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeleteRoomType(hotelID, roomTypeID); err != nil {
+			http.Error(w, "Failed to delete room type", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseRoomTypePath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	hotelID, err := uuid.Parse(vars["hotel_id"])
+	if err != nil {
+		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	roomTypeID, err := uuid.Parse(vars["room_type_id"])
+	if err != nil {
+		http.Error(w, "Invalid room type ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return hotelID, roomTypeID, true
+}
+
+// validateRoomType returns a message describing the first invalid field or an empty string
+func validateRoomType(name string, capacity int, roomsCount int, nightPrice int) string {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return "Room type name cannot be empty"
+	case capacity <= 0:
+		return "Capacity must be positive"
+	case roomsCount < 0:
+		return "Rooms count cannot be negative"
+	case nightPrice <= 0:
+		return "Night price must be positive"
+	}
+	return ""
+}
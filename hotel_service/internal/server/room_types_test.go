@@ -0,0 +1,234 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"hotel_service/internal/dtos/requests"
+	"hotel_service/internal/dtos/responses"
+	"hotel_service/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// region Room Type Service Mock
+type MockRoomTypeService struct {
+	mock.Mock
+}
+
+func (m *MockRoomTypeService) CreateRoomType(hotelID uuid.UUID, req requests.CreateRoomTypeRequest) (uuid.UUID, error) {
+	args := m.Called(hotelID, req)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockRoomTypeService) UpdateRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID, req requests.UpdateRoomTypeRequest) error {
+	args := m.Called(hotelID, roomTypeID, req)
+	return args.Error(0)
+}
+
+func (m *MockRoomTypeService) GetRoomTypeByID(hotelID uuid.UUID, roomTypeID uuid.UUID) (*responses.GetRoomTypeResponse, error) {
+	args := m.Called(hotelID, roomTypeID)
+	return args.Get(0).(*responses.GetRoomTypeResponse), args.Error(1)
+}
+
+func (m *MockRoomTypeService) GetRoomTypes(hotelID uuid.UUID) (*responses.GetRoomTypesResponse, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(*responses.GetRoomTypesResponse), args.Error(1)
+}
+
+func (m *MockRoomTypeService) DeleteRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID) error {
+	args := m.Called(hotelID, roomTypeID)
+	return args.Error(0)
+}
+
+// endregion
+
+// region Test Room Type Endpoints
+
+func TestCreateRoomType_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	hotelID := uuid.New()
+	reqBody := requests.CreateRoomTypeRequest{
+		Name:             "Deluxe",
+		Capacity:         2,
+		BedConfiguration: "1 king",
+		RoomsCount:       5,
+		NightPrice:       15000,
+	}
+
+	id := uuid.New()
+	mockService.On("CreateRoomType", hotelID, reqBody).Return(id, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/hotel/"+hotelID.String()+"/rooms", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var returnedId uuid.UUID
+	err := json.NewDecoder(rec.Body).Decode(&returnedId)
+	assert.NoError(t, err)
+	assert.Equal(t, id, returnedId)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateRoomType_InvalidCapacity_ErrorBadRequest(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	reqBody := requests.CreateRoomTypeRequest{
+		Name:       "Deluxe",
+		Capacity:   0,
+		RoomsCount: 5,
+		NightPrice: 15000,
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/hotel/"+uuid.New().String()+"/rooms", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateRoomType_RoomTypeDoesNotExist_ErrorNotFound(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	reqBody := requests.UpdateRoomTypeRequest{
+		Name:       "Deluxe",
+		Capacity:   3,
+		RoomsCount: 5,
+		NightPrice: 17000,
+	}
+
+	mockService.On("UpdateRoomType", hotelID, roomTypeID, reqBody).Return(services.ErrRoomTypeNotFound)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID.String()+"/rooms/"+roomTypeID.String(), bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRoomType_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	response := &responses.GetRoomTypeResponse{
+		Id:               roomTypeID,
+		HotelId:          hotelID,
+		Name:             "Deluxe",
+		Capacity:         2,
+		BedConfiguration: "1 king",
+		RoomsCount:       5,
+		NightPrice:       15000,
+	}
+
+	mockService.On("GetRoomTypeByID", hotelID, roomTypeID).Return(response, nil)
+
+	req := httptest.NewRequest("GET", "/api/hotel/"+hotelID.String()+"/rooms/"+roomTypeID.String(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resBody responses.GetRoomTypeResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, response, &resBody)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRoomType_RoomTypeDoesNotExist_ErrorNotFound(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	mockService.On("GetRoomTypeByID", hotelID, roomTypeID).Return((*responses.GetRoomTypeResponse)(nil), nil)
+
+	req := httptest.NewRequest("GET", "/api/hotel/"+hotelID.String()+"/rooms/"+roomTypeID.String(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRoomTypes_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	hotelID := uuid.New()
+	response := &responses.GetRoomTypesResponse{
+		RoomTypes: []responses.GetRoomTypeResponse{
+			{Id: uuid.New(), HotelId: hotelID, Name: "Standard", Capacity: 2, RoomsCount: 10, NightPrice: 10000},
+		},
+	}
+
+	mockService.On("GetRoomTypes", hotelID).Return(response, nil)
+
+	req := httptest.NewRequest("GET", "/api/hotel/"+hotelID.String()+"/rooms", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resBody responses.GetRoomTypesResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, response, &resBody)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteRoomType_InvalidUUID_ErrorBadRequest(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	req := httptest.NewRequest("DELETE", "/api/hotel/"+uuid.New().String()+"/rooms/12345", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDeleteRoomType_CommonCase_NoContent(t *testing.T) {
+	mockService := new(MockRoomTypeService)
+	router := setupRoomTypeTestRouter(mockService)
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	mockService.On("DeleteRoomType", hotelID, roomTypeID).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/api/hotel/"+hotelID.String()+"/rooms/"+roomTypeID.String(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+// endregion
@@ -15,7 +15,10 @@ import (
 	"strconv"
 )
 
-func SetupApiRouter(cfg *config.ServerConfig, hotelService services.IHotelService) *mux.Router {
+func SetupApiRouter(
+	cfg *config.ServerConfig,
+	hotelService services.IHotelService,
+	roomTypeService services.IRoomTypeService) *mux.Router {
 	router := mux.NewRouter()
 
 	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
@@ -26,10 +29,17 @@ func SetupApiRouter(cfg *config.ServerConfig, hotelService services.IHotelServic
 
 	apiRouter.HandleFunc("/hotel", endpoints.CreateHotelHandler(hotelService)).Methods("POST")
 	apiRouter.HandleFunc("/hotel/{hotel_id}", endpoints.UpdateHotelHandler(hotelService)).Methods("PUT")
+	apiRouter.HandleFunc("/hotel/{hotel_id}", endpoints.PatchHotelHandler(hotelService)).Methods("PATCH")
 	apiRouter.HandleFunc("/hotel/{hotel_id}", endpoints.GetHotelHandler(hotelService)).Methods("GET")
 	apiRouter.HandleFunc("/hotel", endpoints.GetAllHotelsHandler(hotelService)).Methods("GET")
 	apiRouter.HandleFunc("/hotel/{hotel_id}", endpoints.DeleteHotelHandler(hotelService)).Methods("DELETE")
 
+	apiRouter.HandleFunc("/hotel/{hotel_id}/rooms", endpoints.CreateRoomTypeHandler(roomTypeService)).Methods("POST")
+	apiRouter.HandleFunc("/hotel/{hotel_id}/rooms", endpoints.GetRoomTypesHandler(roomTypeService)).Methods("GET")
+	apiRouter.HandleFunc("/hotel/{hotel_id}/rooms/{room_type_id}", endpoints.GetRoomTypeHandler(roomTypeService)).Methods("GET")
+	apiRouter.HandleFunc("/hotel/{hotel_id}/rooms/{room_type_id}", endpoints.UpdateRoomTypeHandler(roomTypeService)).Methods("PUT")
+	apiRouter.HandleFunc("/hotel/{hotel_id}/rooms/{room_type_id}", endpoints.DeleteRoomTypeHandler(roomTypeService)).Methods("DELETE")
+
 	return router
 }
 
@@ -108,28 +118,4 @@ func getAllHotelsHandler(service services.IHotelService) http.HandlerFunc {
 	}
 }
 
-func deleteHotelHandler(service services.IHotelService) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		slog.Info("Calling the hotel deletion handler")
-		vars := mux.Vars(r)
-		hotelID, err := uuid.Parse(vars["hotel_id"])
-		if err != nil {
-			http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
-			slog.Error("Invalid hotel ID" + strconv.Itoa(http.StatusBadRequest))
-			return
-		}
-		slog.Info("Hotel ID: " + hotelID.String())
-
-		if err := service.DeleteHotel(hotelID); err != nil {
-			http.Error(w, "Failed to delete hotel", http.StatusInternalServerError)
-			slog.Error("Failed to delete hotel" + strconv.Itoa(http.StatusInternalServerError))
-			return
-		}
-
-		w.WriteHeader(http.StatusNoContent)
-		slog.Info("The hotel was successfully deleted")
-		slog.Info("Hotel ID: " + hotelID.String())
-	}
-}
-
 // endregion
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -28,23 +29,33 @@ func (m *MockHotelService) Create(req requests.CreateHotelRequest) (uuid.UUID, e
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *MockHotelService) Update(hotelID uuid.UUID, req requests.UpdateHotelRequest) error {
-	args := m.Called(hotelID, req)
+func (m *MockHotelService) Update(hotelID uuid.UUID, req requests.UpdateHotelRequest, version int) error {
+	args := m.Called(hotelID, req, version)
 	return args.Error(0)
 }
 
+func (m *MockHotelService) PatchHotel(hotelID uuid.UUID, patch []byte, version int) (*responses.GetHotelResponse, error) {
+	args := m.Called(hotelID, patch, version)
+	return args.Get(0).(*responses.GetHotelResponse), args.Error(1)
+}
+
 func (m *MockHotelService) GetByID(hotelID uuid.UUID) (*responses.GetHotelResponse, error) {
 	args := m.Called(hotelID)
 	return args.Get(0).(*responses.GetHotelResponse), args.Error(1)
 }
 
+func (m *MockHotelService) GetNightPrices(hotelIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	args := m.Called(hotelIDs)
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
 func (m *MockHotelService) GetAllHotels(adminID *uuid.UUID) (*responses.GetHotelsResponse, error) {
 	args := m.Called(adminID)
 	return args.Get(0).(*responses.GetHotelsResponse), args.Error(1)
 }
 
-func (m *MockHotelService) DeleteHotel(hotelID uuid.UUID) error {
-	args := m.Called(hotelID)
+func (m *MockHotelService) DeleteHotel(hotelID uuid.UUID, version int) error {
+	args := m.Called(hotelID, version)
 	return args.Error(0)
 }
 
@@ -57,7 +68,11 @@ func (m *MockHotelService) ExistsById(id uuid.UUID) (bool, error) {
 
 // region Helpers
 func setupTestRouter(hotelService services.IHotelService) *mux.Router {
-	return server.SetupApiRouter(&config.ServerConfig{Prefix: "/api"}, hotelService)
+	return server.SetupApiRouter(&config.ServerConfig{Prefix: "/api"}, hotelService, new(MockRoomTypeService))
+}
+
+func setupRoomTypeTestRouter(roomTypeService services.IRoomTypeService) *mux.Router {
+	return server.SetupApiRouter(&config.ServerConfig{Prefix: "/api"}, new(MockHotelService), roomTypeService)
 }
 
 // endregion
@@ -125,10 +140,11 @@ func TestUpdateHotel_CommonCase_Ok(t *testing.T) {
 	}
 
 	mockService.On("ExistsById", hotelID).Return(true, nil)
-	mockService.On("Update", hotelID, reqBody).Return(nil)
+	mockService.On("Update", hotelID, reqBody, 1).Return(nil)
 
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID.String(), bytes.NewReader(body))
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -151,6 +167,7 @@ func TestUpdateHotel_HotelDoesNotExist_ErrorNotFound(t *testing.T) {
 
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID.String(), bytes.NewReader(body))
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -171,6 +188,7 @@ func TestUpdateHotel_InvalidUUID_ErrorBadRequest(t *testing.T) {
 
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest("PUT", "/api/hotel/"+hotelID, bytes.NewReader(body))
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -179,6 +197,100 @@ func TestUpdateHotel_InvalidUUID_ErrorBadRequest(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestPatchHotel_CommonCase_Ok(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+	patch := []byte(`{"night_price":12000}`)
+	response := &responses.GetHotelResponse{Id: hotelID, HotelName: "Test Hotel", NightPrice: 12000}
+	mockService.On("PatchHotel", hotelID, patch, 1).Return(response, nil)
+
+	req := httptest.NewRequest("PATCH", "/api/hotel/"+hotelID.String(), bytes.NewReader(patch))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resBody responses.GetHotelResponse
+	err := json.NewDecoder(rec.Body).Decode(&resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, response, &resBody)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchHotel_HotelDoesNotExist_ErrorNotFound(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+	patch := []byte(`{"night_price":12000}`)
+	mockService.On("PatchHotel", hotelID, patch, 1).Return((*responses.GetHotelResponse)(nil), services.ErrHotelNotFound)
+
+	req := httptest.NewRequest("PATCH", "/api/hotel/"+hotelID.String(), bytes.NewReader(patch))
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchHotel_InvalidPatch_ErrorBadRequest(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+	patch := []byte(`{"night_price":null}`)
+	mockService.On("PatchHotel", hotelID, patch, 1).
+		Return((*responses.GetHotelResponse)(nil), fmt.Errorf("%w: night price must be positive", services.ErrInvalidPatch))
+
+	req := httptest.NewRequest("PATCH", "/api/hotel/"+hotelID.String(), bytes.NewReader(patch))
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchHotel_UnsupportedContentType_Error(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("PATCH", "/api/hotel/"+uuid.New().String(), bytes.NewReader([]byte("night_price=1")))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	mockService.AssertNotCalled(t, "PatchHotel")
+}
+
+func TestPatchHotel_StaleVersion_ErrorPreconditionFailed(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+	patch := []byte(`{"night_price":12000}`)
+	mockService.On("PatchHotel", hotelID, patch, 1).Return((*responses.GetHotelResponse)(nil), services.ErrVersionMismatch)
+
+	req := httptest.NewRequest("PATCH", "/api/hotel/"+hotelID.String(), bytes.NewReader(patch))
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGetHotelWithoutPastRents_HotelExists_Ok(t *testing.T) {
 	mockService := new(MockHotelService)
 	router := setupTestRouter(mockService)
@@ -188,6 +300,7 @@ func TestGetHotelWithoutPastRents_HotelExists_Ok(t *testing.T) {
 		Id:         hotelID,
 		HotelName:  "Test Hotel",
 		NightPrice: 10000,
+		Version:    2,
 	}
 
 	mockService.On("GetByID", hotelID).Return(response, nil)
@@ -198,6 +311,7 @@ func TestGetHotelWithoutPastRents_HotelExists_Ok(t *testing.T) {
 	router.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
 
 	var resBody responses.GetHotelResponse
 	err := json.NewDecoder(rec.Body).Decode(&resBody)
@@ -314,9 +428,10 @@ func TestDeleteHotel_HotelExists_NoContent(t *testing.T) {
 
 	hotelID := uuid.New()
 
-	mockService.On("DeleteHotel", hotelID).Return(nil)
+	mockService.On("DeleteHotel", hotelID, 1).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/api/hotel/"+hotelID.String(), nil)
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -331,9 +446,10 @@ func TestDeleteHotel_HotelDoesNotExist_NoError(t *testing.T) {
 
 	hotelID := uuid.New()
 
-	mockService.On("DeleteHotel", hotelID).Return(nil)
+	mockService.On("DeleteHotel", hotelID, 1).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/api/hotel/"+hotelID.String(), nil)
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
@@ -342,6 +458,37 @@ func TestDeleteHotel_HotelDoesNotExist_NoError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestDeleteHotel_StaleVersion_ErrorPreconditionFailed(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	hotelID := uuid.New()
+
+	mockService.On("DeleteHotel", hotelID, 1).Return(services.ErrVersionMismatch)
+
+	req := httptest.NewRequest("DELETE", "/api/hotel/"+hotelID.String(), nil)
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteHotel_MissingIfMatch_ErrorPreconditionRequired(t *testing.T) {
+	mockService := new(MockHotelService)
+	router := setupTestRouter(mockService)
+
+	req := httptest.NewRequest("DELETE", "/api/hotel/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	mockService.AssertNotCalled(t, "DeleteHotel")
+}
+
 func TestDeleteHotel_InvalidUUID_ErrorBadRequest(t *testing.T) {
 	mockService := new(MockHotelService)
 	router := setupTestRouter(mockService)
@@ -349,6 +496,7 @@ func TestDeleteHotel_InvalidUUID_ErrorBadRequest(t *testing.T) {
 	hotelID := "12345"
 
 	req := httptest.NewRequest("DELETE", "/api/hotel/"+hotelID, nil)
+	req.Header.Set("If-Match", `"1"`)
 	rec := httptest.NewRecorder()
 
 	router.ServeHTTP(rec, req)
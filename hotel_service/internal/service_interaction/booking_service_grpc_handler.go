@@ -5,20 +5,24 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"hotel_service/internal/dtos/responses"
 	pb "hotel_service/internal/service_interaction/gen"
 	"hotel_service/internal/services"
 	"log/slog"
+	"strconv"
 )
 
 type BookingServiceBridge struct {
 	pb.UnimplementedHotelServiceServer
-	hotelService services.IHotelService
+	hotelService    services.IHotelService
+	roomTypeService services.IRoomTypeService
 }
 
-func NewBookingServiceBridge(hotelService services.IHotelService) *BookingServiceBridge {
+func NewBookingServiceBridge(hotelService services.IHotelService, roomTypeService services.IRoomTypeService) *BookingServiceBridge {
 	return &BookingServiceBridge{
 		UnimplementedHotelServiceServer: pb.UnimplementedHotelServiceServer{},
 		hotelService:                    hotelService,
+		roomTypeService:                 roomTypeService,
 	}
 }
 
@@ -37,3 +41,127 @@ func (s *BookingServiceBridge) GetHotelPrice(ctx context.Context, req *pb.GetHot
 
 	return &pb.GetHotelPriceResponse{Price: int32(hotel.NightPrice)}, nil
 }
+
+func (s *BookingServiceBridge) GetHotelPrices(ctx context.Context, req *pb.GetHotelPricesRequest) (*pb.GetHotelPricesResponse, error) {
+	slog.Info("Handling request to get prices of " + strconv.Itoa(len(req.HotelId)) + " hotels")
+
+	hotelIDs := make([]uuid.UUID, 0, len(req.HotelId))
+	for _, hotelID := range req.HotelId {
+		id, err := uuid.Parse(hotelID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid hotel ID: %v", err)
+		}
+		hotelIDs = append(hotelIDs, id)
+	}
+
+	prices, err := s.hotelService.GetNightPrices(hotelIDs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get hotel prices: %v", err)
+	}
+
+	response := &pb.GetHotelPricesResponse{}
+	for hotelID, price := range prices {
+		response.Prices = append(response.Prices, &pb.HotelPrice{HotelId: hotelID.String(), Price: int32(price)})
+	}
+	return response, nil
+}
+
+func (s *BookingServiceBridge) GetHotel(ctx context.Context, req *pb.GetHotelRequest) (*pb.GetHotelResponse, error) {
+	slog.Info("Handling request to get hotel with id " + req.HotelId)
+
+	id, err := uuid.Parse(req.HotelId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hotel ID: %v", err)
+	}
+
+	hotel, err := s.hotelService.GetByID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get hotel: %v", err)
+	}
+	if hotel == nil {
+		return nil, status.Errorf(codes.NotFound, "hotel not found")
+	}
+
+	return &pb.GetHotelResponse{
+		Id:              hotel.Id.String(),
+		HotelName:       hotel.HotelName,
+		NightPrice:      int32(hotel.NightPrice),
+		AdministratorId: hotel.AdminId.String(),
+	}, nil
+}
+
+func (s *BookingServiceBridge) GetHotelsByAdministrator(ctx context.Context, req *pb.GetHotelsByAdministratorRequest) (*pb.GetHotelsByAdministratorResponse, error) {
+	slog.Info("Handling request to get hotels of administrator with id " + req.AdministratorId)
+
+	administratorID, err := uuid.Parse(req.AdministratorId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid administrator ID: %v", err)
+	}
+
+	hotels, err := s.hotelService.GetAllHotels(&administratorID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get hotels: %v", err)
+	}
+
+	response := &pb.GetHotelsByAdministratorResponse{}
+	for _, hotel := range hotels.Hotels {
+		response.HotelId = append(response.HotelId, hotel.Id.String())
+	}
+	return response, nil
+}
+
+func (s *BookingServiceBridge) GetRoomTypes(ctx context.Context, req *pb.GetRoomTypesRequest) (*pb.GetRoomTypesResponse, error) {
+	slog.Info("Handling request to get room types of hotel with id " + req.HotelId)
+
+	hotelID, err := uuid.Parse(req.HotelId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hotel ID: %v", err)
+	}
+
+	roomTypes, err := s.roomTypeService.GetRoomTypes(hotelID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room types: %v", err)
+	}
+
+	response := &pb.GetRoomTypesResponse{}
+	for i := range roomTypes.RoomTypes {
+		response.RoomTypes = append(response.RoomTypes, toProtoRoomType(&roomTypes.RoomTypes[i]))
+	}
+	return response, nil
+}
+
+func (s *BookingServiceBridge) GetRoomType(ctx context.Context, req *pb.GetRoomTypeRequest) (*pb.GetRoomTypeResponse, error) {
+	slog.Info("Handling request to get room type with id " + req.RoomTypeId)
+
+	hotelID, err := uuid.Parse(req.HotelId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hotel ID: %v", err)
+	}
+
+	roomTypeID, err := uuid.Parse(req.RoomTypeId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid room type ID: %v", err)
+	}
+
+	roomType, err := s.roomTypeService.GetRoomTypeByID(hotelID, roomTypeID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room type: %v", err)
+	}
+	if roomType == nil {
+		return nil, status.Errorf(codes.NotFound, "room type not found")
+	}
+
+	return &pb.GetRoomTypeResponse{RoomType: toProtoRoomType(roomType)}, nil
+}
+
+func toProtoRoomType(roomType *responses.GetRoomTypeResponse) *pb.RoomType {
+	return &pb.RoomType{
+		Id:               roomType.Id.String(),
+		HotelId:          roomType.HotelId.String(),
+		Name:             roomType.Name,
+		Capacity:         int32(roomType.Capacity),
+		BedConfiguration: roomType.BedConfiguration,
+		RoomsCount:       int32(roomType.RoomsCount),
+		NightPrice:       int32(roomType.NightPrice),
+	}
+}
@@ -3,21 +3,31 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"hotel_service/internal/db"
 	"hotel_service/internal/dtos/requests"
 	"hotel_service/internal/dtos/responses"
 	"log/slog"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+var ErrHotelNotFound = errors.New("hotel not found")
+
+// ErrVersionMismatch is returned when the hotel was changed since the client has read it
+var ErrVersionMismatch = errors.New("hotel version does not match")
+
 type IHotelService interface {
 	Create(request requests.CreateHotelRequest) (uuid.UUID, error)
-	Update(hotelID uuid.UUID, request requests.UpdateHotelRequest) error
+	Update(hotelID uuid.UUID, request requests.UpdateHotelRequest, version int) error
+	PatchHotel(hotelID uuid.UUID, patch []byte, version int) (*responses.GetHotelResponse, error)
 	GetByID(hotelID uuid.UUID) (*responses.GetHotelResponse, error)
+	GetNightPrices(hotelIDs []uuid.UUID) (map[uuid.UUID]int, error)
 	ExistsById(id uuid.UUID) (bool, error)
 	GetAllHotels(adminUUID *uuid.UUID) (*responses.GetHotelsResponse, error)
-	DeleteHotel(hotelID uuid.UUID) error
+	DeleteHotel(hotelID uuid.UUID, version int) error
 }
 
 type HotelService struct {
@@ -39,23 +49,67 @@ func (s *HotelService) Create(request requests.CreateHotelRequest) (uuid.UUID, e
 	return hotelID, nil
 }
 
-func (s *HotelService) Update(hotelID uuid.UUID, request requests.UpdateHotelRequest) error {
+// Update saves the hotel only if it is still at the version the client has seen
+func (s *HotelService) Update(hotelID uuid.UUID, request requests.UpdateHotelRequest, version int) error {
 	slog.Info("Update hotel in service")
-	query := `UPDATE hotels SET hotel_name = $1, night_price = $2 WHERE id = $3`
-	_, err := s.Db.Connection.Exec(query, request.HotelName, request.NightPrice, hotelID)
+	query := `UPDATE hotels SET hotel_name = $1, night_price = $2, version = version + 1 WHERE id = $3 AND version = $4`
+	result, err := s.Db.Connection.Exec(query, request.HotelName, request.NightPrice, hotelID, version)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rowsAffected == 0 {
+		return s.versionMismatchOrNotFound(hotelID)
+	}
 	return nil
 }
 
+// PatchHotel changes only the fields present in the JSON merge patch and returns the updated hotel.
+// The merged hotel is validated before it is saved
+func (s *HotelService) PatchHotel(hotelID uuid.UUID, patch []byte, version int) (*responses.GetHotelResponse, error) {
+	slog.Info("Patching hotel in service")
+	hotel, err := s.GetByID(hotelID)
+	if err != nil {
+		return nil, err
+	}
+	if hotel == nil {
+		return nil, ErrHotelNotFound
+	}
+	if hotel.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	current := requests.UpdateHotelRequest{HotelName: hotel.HotelName, NightPrice: hotel.NightPrice}
+	request, err := mergeHotelPatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.TrimSpace(request.HotelName) == "":
+		return nil, fmt.Errorf("%w: hotel name cannot be empty", ErrInvalidPatch)
+	case request.NightPrice <= 0:
+		return nil, fmt.Errorf("%w: night price must be positive", ErrInvalidPatch)
+	}
+
+	if err := s.Update(hotelID, request, version); err != nil {
+		return nil, err
+	}
+	hotel.HotelName = request.HotelName
+	hotel.NightPrice = request.NightPrice
+	hotel.Version = version + 1
+	return hotel, nil
+}
+
 func (s *HotelService) GetByID(hotelID uuid.UUID) (*responses.GetHotelResponse, error) {
 	slog.Info("Getting hotel by ID in service")
-	query := `SELECT id, hotel_name, night_price, administrator_id FROM hotels WHERE id = $1`
+	query := `SELECT id, hotel_name, night_price, administrator_id, version FROM hotels WHERE id = $1`
 	row := s.Db.Connection.QueryRow(query, hotelID)
 
 	var response responses.GetHotelResponse
-	if err := row.Scan(&response.Id, &response.HotelName, &response.NightPrice, &response.AdminId); err != nil {
+	if err := row.Scan(&response.Id, &response.HotelName, &response.NightPrice, &response.AdminId, &response.Version); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil // Hotel does not exist
 		}
@@ -66,15 +120,20 @@ func (s *HotelService) GetByID(hotelID uuid.UUID) (*responses.GetHotelResponse,
 
 func (s *HotelService) GetAllHotels(adminID *uuid.UUID) (*responses.GetHotelsResponse, error) {
 	slog.Info("Getting all hotels in service")
-	query := `SELECT id, hotel_name, night_price, administrator_id FROM hotels`
-	rows, err := s.Db.Connection.Query(query)
+	query := `SELECT id, hotel_name, night_price, administrator_id, version FROM hotels`
+	params := []interface{}{}
+	if adminID != nil {
+		query += ` WHERE administrator_id = $1`
+		params = append(params, *adminID)
+	}
+	rows, err := s.Db.Connection.Query(query, params...)
 	if (err != nil) {
 		return nil, err
 	}
 	var response responses.GetHotelsResponse
 	for rows.Next() {
 		h := responses.GetHotelResponse {}
-		err := rows.Scan(&h.Id, &h.HotelName, &h.NightPrice, &h.AdminId)
+		err := rows.Scan(&h.Id, &h.HotelName, &h.NightPrice, &h.AdminId, &h.Version)
 		if (err != nil) {
 			return nil, err
 		}
@@ -83,16 +142,39 @@ func (s *HotelService) GetAllHotels(adminID *uuid.UUID) (*responses.GetHotelsRes
 	return &response, nil
 }
 
-func (s *HotelService) DeleteHotel(hotelID uuid.UUID) error {
+// DeleteHotel deletes the hotel only if it is still at the version the client has seen.
+// Deleting a hotel which does not exist is not an error
+func (s *HotelService) DeleteHotel(hotelID uuid.UUID, version int) error {
 	slog.Info("Deletion hotel in service")
-	query := `DELETE FROM hotels WHERE id = $1`
-	_, err := s.Db.Connection.Exec(query, hotelID)
+	query := `DELETE FROM hotels WHERE id = $1 AND version = $2`
+	result, err := s.Db.Connection.Exec(query, hotelID, version)
 	if (err != nil) {
 		return err
 	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if err := s.versionMismatchOrNotFound(hotelID); !errors.Is(err, ErrHotelNotFound) {
+			return err
+		}
+	}
 	return nil
 }
 
+// versionMismatchOrNotFound tells why a conditional change of the hotel has not changed any row
+func (s *HotelService) versionMismatchOrNotFound(hotelID uuid.UUID) error {
+	exists, err := s.ExistsById(hotelID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrHotelNotFound
+	}
+	return ErrVersionMismatch
+}
+
 func (s *HotelService) ExistsById(id uuid.UUID) (bool, error) {
 	slog.Info("Existence hotel by ID in service")
 	query := `SELECT EXISTS(SELECT 1 FROM hotels WHERE id = $1)`
@@ -100,3 +182,33 @@ func (s *HotelService) ExistsById(id uuid.UUID) (bool, error) {
 	err := s.Db.Connection.QueryRow(query, id).Scan(&exists)
 	return exists, err
 }
+
+// GetNightPrices returns the night prices of the hotels in one query, hotels which do not exist are skipped
+func (s *HotelService) GetNightPrices(hotelIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	slog.Info("Getting night prices of hotels in service")
+	ids := make([]string, 0, len(hotelIDs))
+	for _, id := range hotelIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `SELECT id, night_price FROM hotels WHERE id = ANY($1::uuid[])`
+	rows, err := s.Db.Connection.Query(query, pq.StringArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make(map[uuid.UUID]int, len(hotelIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var price int
+		if err := rows.Scan(&id, &price); err != nil {
+			return nil, err
+		}
+		prices[id] = price
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	. "hotel_service/internal/db"
 	"hotel_service/internal/dtos/requests"
@@ -69,11 +70,11 @@ func TestUpdateHotel_CommonCase_Ok(t *testing.T) {
 		NightPrice: 150,
 	}
 
-	mock.ExpectExec("UPDATE hotels SET hotel_name = \\$1, night_price = \\$2 WHERE id = \\$3").
-        WithArgs(request2.HotelName, request2.NightPrice, hotelID).
+	mock.ExpectExec("UPDATE hotels SET hotel_name = \\$1, night_price = \\$2, version = version \\+ 1 WHERE id = \\$3 AND version = \\$4").
+        WithArgs(request2.HotelName, request2.NightPrice, hotelID, 1).
         WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = hotelService.Update(hotelID, request2)
+	err = hotelService.Update(hotelID, request2, 1)
 
 	assert.NoError(t, err)
     assert.NoError(t, mock.ExpectationsWereMet())
@@ -101,11 +102,11 @@ func TestDeleteHotel_CommonCase_Ok(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	mock.ExpectExec("DELETE FROM hotels WHERE id = \\$1").
-        WithArgs(hotelID).
+	mock.ExpectExec("DELETE FROM hotels WHERE id = \\$1 AND version = \\$2").
+        WithArgs(hotelID, 1).
         WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = hotelService.DeleteHotel(hotelID)
+	err = hotelService.DeleteHotel(hotelID, 1)
 
 	assert.NoError(t, err)
     assert.NoError(t, mock.ExpectationsWereMet())
@@ -117,11 +118,12 @@ func TestGetAllHotels_CommonCase_Ok(t *testing.T) {
 
 	hotelService := services.NewHotelService(&Database{Connection: db})
 	adminID := uuid.New()
-    rows := sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id"}).
-        AddRow(uuid.New(), "Test Hotel 1", 100, adminID).
-        AddRow(uuid.New(), "Test Hotel 2", 200, adminID)
+    rows := sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id", "version"}).
+        AddRow(uuid.New(), "Test Hotel 1", 100, adminID, 1).
+        AddRow(uuid.New(), "Test Hotel 2", 200, adminID, 1)
 
-    mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id FROM hotels").
+    mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels WHERE administrator_id = \\$1").
+        WithArgs(adminID).
         WillReturnRows(rows)
 
     response, err := hotelService.GetAllHotels(&adminID)
@@ -145,10 +147,10 @@ func TestGetHotelById_CommonCase_ReturnHotel(t *testing.T) {
 	hotelService := services.NewHotelService(&Database{Connection: db})
 
 	hotelID := uuid.New()
-	rows := sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id"}).
-		AddRow(hotelID, "Test Hotel", 100, uuid.New())
+	rows := sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id", "version"}).
+		AddRow(hotelID, "Test Hotel", 100, uuid.New(), 1)
 
-	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id FROM hotels").
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
 		WithArgs(hotelID).
 		WillReturnRows(rows)
 
@@ -161,6 +163,146 @@ func TestGetHotelById_CommonCase_ReturnHotel(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestPatchHotel_OnlyNightPrice_NameKept(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id", "version"}).
+			AddRow(hotelID, "Test Hotel", 100, uuid.New(), 1))
+	mock.ExpectExec("UPDATE hotels SET hotel_name = \\$1, night_price = \\$2, version = version \\+ 1 WHERE id = \\$3 AND version = \\$4").
+		WithArgs("Test Hotel", 150, hotelID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	response, err := hotelService.PatchHotel(hotelID, []byte(`{"night_price":150}`), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Hotel", response.HotelName)
+	assert.Equal(t, 150, response.NightPrice)
+	assert.Equal(t, 2, response.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchHotel_NullName_ErrInvalidPatch(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id", "version"}).
+			AddRow(hotelID, "Test Hotel", 100, uuid.New(), 1))
+
+	response, err := hotelService.PatchHotel(hotelID, []byte(`{"hotel_name":null}`), 1)
+
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, services.ErrInvalidPatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchHotel_UnknownField_ErrInvalidPatch(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id", "version"}).
+			AddRow(hotelID, "Test Hotel", 100, uuid.New(), 1))
+
+	response, err := hotelService.PatchHotel(hotelID, []byte(`{"administrator_id":"`+uuid.New().String()+`"}`), 1)
+
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, services.ErrInvalidPatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchHotel_HotelDoesNotExist_ErrHotelNotFound(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+
+	response, err := hotelService.PatchHotel(hotelID, []byte(`{"night_price":150}`), 1)
+
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, services.ErrHotelNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchHotel_StaleVersion_ErrVersionMismatch(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_name", "night_price", "administrator_id", "version"}).
+			AddRow(hotelID, "Test Hotel", 100, uuid.New(), 3))
+
+	response, err := hotelService.PatchHotel(hotelID, []byte(`{"night_price":150}`), 2)
+
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, services.ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateHotel_ChangedConcurrently_ErrVersionMismatch(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	request := requests.UpdateHotelRequest{HotelName: "Test Hotel", NightPrice: 150}
+	mock.ExpectExec("UPDATE hotels SET hotel_name = \\$1, night_price = \\$2, version = version \\+ 1 WHERE id = \\$3 AND version = \\$4").
+		WithArgs(request.HotelName, request.NightPrice, hotelID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err := hotelService.Update(hotelID, request, 1)
+
+	assert.ErrorIs(t, err, services.ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteHotel_HotelDoesNotExist_NoError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	mock.ExpectExec("DELETE FROM hotels WHERE id = \\$1 AND version = \\$2").
+		WithArgs(hotelID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	err := hotelService.DeleteHotel(hotelID, 1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetHotelById_HotelWithIdDoesNotExist_ReturnNil(t *testing.T) {
 	db, mock := createMockDB(t)
 	defer db.Close()
@@ -169,7 +311,7 @@ func TestGetHotelById_HotelWithIdDoesNotExist_ReturnNil(t *testing.T) {
 
 	hotelID := uuid.New()
 
-	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id FROM hotels").
+	mock.ExpectQuery("SELECT id, hotel_name, night_price, administrator_id, version FROM hotels").
 		WithArgs(hotelID).
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
@@ -217,3 +359,25 @@ func TestExistsById_DoesNotExist_ReturnFalse(t *testing.T) {
 	assert.False(t, exists)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetNightPrices_CommonCase_ReturnPrices(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	hotelService := services.NewHotelService(&Database{Connection: db})
+
+	firstHotelID := uuid.New()
+	secondHotelID := uuid.New()
+	missingHotelID := uuid.New()
+	mock.ExpectQuery(`SELECT id, night_price FROM hotels WHERE id = ANY\(\$1::uuid\[\]\)`).
+		WithArgs(pq.StringArray{firstHotelID.String(), secondHotelID.String(), missingHotelID.String()}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "night_price"}).
+			AddRow(firstHotelID, 100).
+			AddRow(secondHotelID, 200))
+
+	prices, err := hotelService.GetNightPrices([]uuid.UUID{firstHotelID, secondHotelID, missingHotelID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{firstHotelID: 100, secondHotelID: 200}, prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hotel_service/internal/dtos/requests"
+)
+
+var ErrInvalidPatch = errors.New("invalid merge patch")
+
+// mergeHotelPatch applies the JSON merge patch (RFC 7396) to the hotel. A hotel has no nested objects,
+// so every member of the patch either replaces a field or, being null, clears it
+func mergeHotelPatch(hotel requests.UpdateHotelRequest, patch []byte) (requests.UpdateHotelRequest, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+		return hotel, fmt.Errorf("%w: the patch must be a JSON object", ErrInvalidPatch)
+	}
+
+	for name, value := range members {
+		var field interface{}
+		switch name {
+		case "hotel_name":
+			hotel.HotelName = ""
+			field = &hotel.HotelName
+		case "night_price":
+			hotel.NightPrice = 0
+			field = &hotel.NightPrice
+		default:
+			return hotel, fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, name)
+		}
+		if bytes.Equal(value, []byte("null")) {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			return hotel, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+	}
+	return hotel, nil
+}
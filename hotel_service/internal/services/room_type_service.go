@@ -0,0 +1,119 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"hotel_service/internal/db"
+	"hotel_service/internal/dtos/requests"
+	"hotel_service/internal/dtos/responses"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+var ErrRoomTypeNotFound = errors.New("room type not found")
+
+type IRoomTypeService interface {
+	CreateRoomType(hotelID uuid.UUID, request requests.CreateRoomTypeRequest) (uuid.UUID, error)
+	UpdateRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID, request requests.UpdateRoomTypeRequest) error
+	GetRoomTypeByID(hotelID uuid.UUID, roomTypeID uuid.UUID) (*responses.GetRoomTypeResponse, error)
+	GetRoomTypes(hotelID uuid.UUID) (*responses.GetRoomTypesResponse, error)
+	DeleteRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID) error
+}
+
+type RoomTypeService struct {
+	Db *db.Database
+}
+
+func NewRoomTypeService(database *db.Database) *RoomTypeService {
+	return &RoomTypeService{Db: database}
+}
+
+func (s *RoomTypeService) CreateRoomType(hotelID uuid.UUID, request requests.CreateRoomTypeRequest) (uuid.UUID, error) {
+	slog.Info("Creation room type in service")
+	roomTypeID := uuid.New()
+	query := `
+		INSERT INTO room_types (id, hotel_id, name, capacity, bed_configuration, rooms_count, night_price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := s.Db.Connection.Exec(query, roomTypeID, hotelID, request.Name, request.Capacity,
+		request.BedConfiguration, request.RoomsCount, request.NightPrice)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return roomTypeID, nil
+}
+
+func (s *RoomTypeService) UpdateRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID, request requests.UpdateRoomTypeRequest) error {
+	slog.Info("Update room type in service")
+	query := `
+		UPDATE room_types
+		SET name = $1, capacity = $2, bed_configuration = $3, rooms_count = $4, night_price = $5
+		WHERE id = $6 AND hotel_id = $7`
+	result, err := s.Db.Connection.Exec(query, request.Name, request.Capacity, request.BedConfiguration,
+		request.RoomsCount, request.NightPrice, roomTypeID, hotelID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRoomTypeNotFound
+	}
+	return nil
+}
+
+func (s *RoomTypeService) GetRoomTypeByID(hotelID uuid.UUID, roomTypeID uuid.UUID) (*responses.GetRoomTypeResponse, error) {
+	slog.Info("Getting room type by ID in service")
+	query := `
+		SELECT id, hotel_id, name, capacity, bed_configuration, rooms_count, night_price
+		FROM room_types
+		WHERE id = $1 AND hotel_id = $2`
+	row := s.Db.Connection.QueryRow(query, roomTypeID, hotelID)
+
+	var response responses.GetRoomTypeResponse
+	if err := row.Scan(&response.Id, &response.HotelId, &response.Name, &response.Capacity,
+		&response.BedConfiguration, &response.RoomsCount, &response.NightPrice); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Room type does not exist
+		}
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *RoomTypeService) GetRoomTypes(hotelID uuid.UUID) (*responses.GetRoomTypesResponse, error) {
+	slog.Info("Getting room types of hotel in service")
+	query := `
+		SELECT id, hotel_id, name, capacity, bed_configuration, rooms_count, night_price
+		FROM room_types
+		WHERE hotel_id = $1
+		ORDER BY name`
+	rows, err := s.Db.Connection.Query(query, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	response := responses.GetRoomTypesResponse{RoomTypes: []responses.GetRoomTypeResponse{}}
+	for rows.Next() {
+		rt := responses.GetRoomTypeResponse{}
+		err := rows.Scan(&rt.Id, &rt.HotelId, &rt.Name, &rt.Capacity, &rt.BedConfiguration, &rt.RoomsCount, &rt.NightPrice)
+		if err != nil {
+			return nil, err
+		}
+		response.RoomTypes = append(response.RoomTypes, rt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *RoomTypeService) DeleteRoomType(hotelID uuid.UUID, roomTypeID uuid.UUID) error {
+	slog.Info("Deletion room type in service")
+	query := `DELETE FROM room_types WHERE id = $1 AND hotel_id = $2`
+	_, err := s.Db.Connection.Exec(query, roomTypeID, hotelID)
+	return err
+}
@@ -0,0 +1,174 @@
+package services_test
+
+import (
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	. "hotel_service/internal/db"
+	"hotel_service/internal/dtos/requests"
+	"hotel_service/internal/services"
+	"testing"
+)
+
+func TestCreateRoomType_CommonCase_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	request := requests.CreateRoomTypeRequest{
+		Name:             "Deluxe",
+		Capacity:         2,
+		BedConfiguration: "1 king",
+		RoomsCount:       5,
+		NightPrice:       15000,
+	}
+
+	mock.ExpectExec("INSERT INTO room_types").
+		WithArgs(sqlmock.AnyArg(), hotelID, request.Name, request.Capacity, request.BedConfiguration,
+			request.RoomsCount, request.NightPrice).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	id, err := roomTypeService.CreateRoomType(hotelID, request)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRoomType_RoomTypeDoesNotExist_ReturnNotFound(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	request := requests.UpdateRoomTypeRequest{
+		Name:             "Deluxe",
+		Capacity:         3,
+		BedConfiguration: "1 king, 1 single",
+		RoomsCount:       4,
+		NightPrice:       17000,
+	}
+
+	mock.ExpectExec("UPDATE room_types SET name = \\$1, capacity = \\$2, bed_configuration = \\$3, rooms_count = \\$4, night_price = \\$5 WHERE id = \\$6 AND hotel_id = \\$7").
+		WithArgs(request.Name, request.Capacity, request.BedConfiguration, request.RoomsCount, request.NightPrice,
+			roomTypeID, hotelID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := roomTypeService.UpdateRoomType(hotelID, roomTypeID, request)
+
+	assert.ErrorIs(t, err, services.ErrRoomTypeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRoomType_RowsAffectedFailed_ReturnError(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	request := requests.UpdateRoomTypeRequest{Name: "Deluxe", Capacity: 3, RoomsCount: 4, NightPrice: 17000}
+
+	mock.ExpectExec("UPDATE room_types").
+		WillReturnResult(sqlmock.NewErrorResult(errors.New("driver error")))
+
+	err := roomTypeService.UpdateRoomType(hotelID, roomTypeID, request)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, services.ErrRoomTypeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoomTypeById_CommonCase_ReturnRoomType(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "hotel_id", "name", "capacity", "bed_configuration", "rooms_count", "night_price"}).
+		AddRow(roomTypeID, hotelID, "Deluxe", 2, "1 king", 5, 15000)
+
+	mock.ExpectQuery("SELECT id, hotel_id, name, capacity, bed_configuration, rooms_count, night_price FROM room_types").
+		WithArgs(roomTypeID, hotelID).
+		WillReturnRows(rows)
+
+	response, err := roomTypeService.GetRoomTypeByID(hotelID, roomTypeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, roomTypeID, response.Id)
+	assert.Equal(t, "Deluxe", response.Name)
+	assert.Equal(t, 2, response.Capacity)
+	assert.Equal(t, 15000, response.NightPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoomTypeById_RoomTypeDoesNotExist_ReturnNil(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+
+	mock.ExpectQuery("SELECT id, hotel_id, name, capacity, bed_configuration, rooms_count, night_price FROM room_types").
+		WithArgs(roomTypeID, hotelID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+
+	response, err := roomTypeService.GetRoomTypeByID(hotelID, roomTypeID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoomTypes_CommonCase_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "hotel_id", "name", "capacity", "bed_configuration", "rooms_count", "night_price"}).
+		AddRow(uuid.New(), hotelID, "Deluxe", 2, "1 king", 5, 15000).
+		AddRow(uuid.New(), hotelID, "Family", 4, "2 queen", 3, 20000)
+
+	mock.ExpectQuery("SELECT id, hotel_id, name, capacity, bed_configuration, rooms_count, night_price FROM room_types WHERE hotel_id = \\$1").
+		WithArgs(hotelID).
+		WillReturnRows(rows)
+
+	response, err := roomTypeService.GetRoomTypes(hotelID)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.RoomTypes, 2)
+	assert.Equal(t, "Family", response.RoomTypes[1].Name)
+	assert.Equal(t, 4, response.RoomTypes[1].Capacity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRoomType_CommonCase_Ok(t *testing.T) {
+	db, mock := createMockDB(t)
+	defer db.Close()
+
+	roomTypeService := services.NewRoomTypeService(&Database{Connection: db})
+
+	hotelID := uuid.New()
+	roomTypeID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM room_types WHERE id = \\$1 AND hotel_id = \\$2").
+		WithArgs(roomTypeID, hotelID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := roomTypeService.DeleteRoomType(hotelID, roomTypeID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
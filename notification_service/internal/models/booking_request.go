@@ -5,9 +5,25 @@ import (
 	"time"
 )
 
+// Types of the booking events which are sent by the booking service
+const (
+	EventBookingCreated       = "booking_created"
+	EventBookingCancelled     = "booking_cancelled"
+	EventBookingStatusChanged = "booking_status_changed"
+	EventWaitlistOffer        = "waitlist_offer"
+	EventGroupBookingCreated  = "group_booking_created"
+	EventBookingModified      = "booking_modified"
+)
+
 type NotificationData struct {
-	UserContactData *UserContactData `json:"user_contact_data"`
-	RentData        *RentData        `json:"rent_data"`
+	EventType       string            `json:"event_type"`
+	UserContactData *UserContactData  `json:"user_contact_data"`
+	RentData        *RentData         `json:"rent_data"`
+	Cancellation    *CancellationData `json:"cancellation,omitempty"`
+	Transition      *TransitionData   `json:"transition,omitempty"`
+	Offer           *OfferData        `json:"offer,omitempty"`
+	Group           *GroupData        `json:"group,omitempty"`
+	Modification    *ModificationData `json:"modification,omitempty"`
 }
 
 type UserContactData struct {
@@ -16,10 +32,70 @@ type UserContactData struct {
 }
 
 type RentData struct {
-	ID           uuid.UUID `json:"id"`
+	ID               uuid.UUID `json:"id"`
+	HotelID          uuid.UUID `json:"hotel_id"`
+	ClientID         uuid.UUID `json:"client_id"`
+	NightPrice       int       `json:"night_price"`
+	Nights           int       `json:"nights"`
+	Currency         string    `json:"currency"`
+	TotalAmount      int       `json:"total_amount"`
+	CheckInDate      time.Time `json:"check_in_date"`
+	CheckOutDate     time.Time `json:"check_out_date"`
+	Status           string    `json:"status"`
+	Adults           int       `json:"adults"`
+	Children         int       `json:"children"`
+	PrimaryGuestName string    `json:"primary_guest_name,omitempty"`
+	AdditionalGuests []string  `json:"additional_guests,omitempty"`
+}
+
+type CancellationData struct {
+	RentID        uuid.UUID `json:"rent_id"`
+	CancelledAt   time.Time `json:"cancelled_at"`
+	CancelledBy   uuid.UUID `json:"cancelled_by"`
+	Reason        string    `json:"reason"`
+	TotalAmount   int       `json:"total_amount"`
+	PenaltyAmount int       `json:"penalty_amount"`
+	RefundAmount  int       `json:"refund_amount"`
+}
+
+type TransitionData struct {
+	ID         uuid.UUID `json:"id"`
+	RentID     uuid.UUID `json:"rent_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  uuid.UUID `json:"changed_by"`
+	Reason     string    `json:"reason"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+type OfferData struct {
+	EntryID   uuid.UUID `json:"entry_id"`
+	HoldID    uuid.UUID `json:"hold_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type GroupData struct {
+	ID          uuid.UUID  `json:"id"`
+	HotelID     uuid.UUID  `json:"hotel_id"`
+	ClientID    uuid.UUID  `json:"client_id"`
+	Rents       []RentData `json:"rents"`
+	TotalAmount int        `json:"total_amount"`
+	Currency    string     `json:"currency"`
+}
+
+// StayData is the stay of a rent before or after it was modified
+type StayData struct {
 	HotelID      uuid.UUID `json:"hotel_id"`
-	ClientID     uuid.UUID `json:"client_id"`
-	NightPrice   int       `json:"night_price"`
 	CheckInDate  time.Time `json:"check_in_date"`
 	CheckOutDate time.Time `json:"check_out_date"`
+	Nights       int       `json:"nights"`
+	TotalAmount  int       `json:"total_amount"`
+}
+
+type ModificationData struct {
+	RentID          uuid.UUID `json:"rent_id"`
+	Before          StayData  `json:"before"`
+	After           StayData  `json:"after"`
+	Currency        string    `json:"currency"`
+	PriceDifference int       `json:"price_difference"`
 }
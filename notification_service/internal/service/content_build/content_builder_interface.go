@@ -3,5 +3,6 @@ package content_build
 import "notification_service/internal/models"
 
 type IContentBuilder interface {
+	BuildSubject(notification models.NotificationData) string
 	BuildContent(notification models.NotificationData) string
 }
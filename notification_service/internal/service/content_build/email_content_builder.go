@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"notification_service/internal/models"
+	"strings"
 )
 
 type EmailContentBuilder struct{}
@@ -12,7 +13,37 @@ func NewEmailContentBuilder() *EmailContentBuilder {
 	return &EmailContentBuilder{}
 }
 
+func (e *EmailContentBuilder) BuildSubject(notification models.NotificationData) string {
+	switch notification.EventType {
+	case models.EventBookingCancelled:
+		return "Booking Cancellation"
+	case models.EventBookingStatusChanged:
+		return "Booking Status Update"
+	case models.EventWaitlistOffer:
+		return "A Room Is Available"
+	case models.EventGroupBookingCreated:
+		return "Group Booking Confirmation"
+	case models.EventBookingModified:
+		return "Booking Modification"
+	default:
+		return "Booking Confirmation"
+	}
+}
+
 func (e *EmailContentBuilder) BuildContent(notification models.NotificationData) string {
+	switch notification.EventType {
+	case models.EventBookingCancelled:
+		return e.buildCancellationContent(notification)
+	case models.EventBookingStatusChanged:
+		return e.buildStatusChangeContent(notification)
+	case models.EventWaitlistOffer:
+		return e.buildWaitlistOfferContent(notification)
+	case models.EventGroupBookingCreated:
+		return e.buildGroupContent(notification)
+	case models.EventBookingModified:
+		return e.buildModificationContent(notification)
+	}
+
 	booking := notification
 
 	fromDate := booking.RentData.CheckInDate.Format("January 2, 2006")
@@ -24,13 +55,206 @@ func (e *EmailContentBuilder) BuildContent(notification models.NotificationData)
 			"Hotel ID: %s\n"+
 			"Check-in Date: %s\n"+
 			"Check-out Date: %s\n"+
+			"Total: %d %s\n"+
+			"%s"+
 			"Client Email: %s\n\n"+
 			"We look forward to welcoming you. If you have any questions or need further assistance, feel free to reach out.\n\n"+
 			"Best regards,\n"+
 			"Your Hotel Team",
-		booking.RentData.HotelID, fromDate, toDate, booking.RentData.ClientID,
+		booking.RentData.HotelID, fromDate, toDate, booking.RentData.TotalAmount, booking.RentData.Currency,
+		buildGuestsContent(booking.RentData), booking.RentData.ClientID,
 	)
 	slog.Info("Built content of the email")
 
 	return emailContent
 }
+
+func (e *EmailContentBuilder) buildCancellationContent(notification models.NotificationData) string {
+	fromDate := notification.RentData.CheckInDate.Format("January 2, 2006")
+	toDate := notification.RentData.CheckOutDate.Format("January 2, 2006")
+
+	reason := "not specified"
+	penaltyAmount, refundAmount := 0, 0
+	if cancellation := notification.Cancellation; cancellation != nil {
+		if cancellation.Reason != "" {
+			reason = cancellation.Reason
+		}
+		penaltyAmount = cancellation.PenaltyAmount
+		refundAmount = cancellation.RefundAmount
+	}
+
+	emailContent := fmt.Sprintf(
+		"Dear Customer,\n\n"+
+			"Your booking at our hotel has been cancelled. Below are the cancellation details:\n\n"+
+			"Hotel ID: %s\n"+
+			"Check-in Date: %s\n"+
+			"Check-out Date: %s\n"+
+			"Reason: %s\n"+
+			"Cancellation Fee: %d\n"+
+			"Refund Amount: %d\n\n"+
+			"We hope to welcome you another time. If you have any questions or need further assistance, feel free to reach out.\n\n"+
+			"Best regards,\n"+
+			"Your Hotel Team",
+		notification.RentData.HotelID, fromDate, toDate, reason, penaltyAmount, refundAmount,
+	)
+	slog.Info("Built content of the cancellation email")
+
+	return emailContent
+}
+
+func (e *EmailContentBuilder) buildStatusChangeContent(notification models.NotificationData) string {
+	fromDate := notification.RentData.CheckInDate.Format("January 2, 2006")
+	toDate := notification.RentData.CheckOutDate.Format("January 2, 2006")
+
+	status := notification.RentData.Status
+	if notification.Transition != nil {
+		status = notification.Transition.ToStatus
+	}
+
+	emailContent := fmt.Sprintf(
+		"Dear Customer,\n\n"+
+			"The status of your booking at our hotel has changed. Below are your booking details:\n\n"+
+			"Hotel ID: %s\n"+
+			"Check-in Date: %s\n"+
+			"Check-out Date: %s\n"+
+			"Status: %s\n\n"+
+			"If you have any questions or need further assistance, feel free to reach out.\n\n"+
+			"Best regards,\n"+
+			"Your Hotel Team",
+		notification.RentData.HotelID, fromDate, toDate, strings.ReplaceAll(status, "_", " "),
+	)
+	slog.Info("Built content of the status change email")
+
+	return emailContent
+}
+
+func (e *EmailContentBuilder) buildWaitlistOfferContent(notification models.NotificationData) string {
+	fromDate := notification.RentData.CheckInDate.Format("January 2, 2006")
+	toDate := notification.RentData.CheckOutDate.Format("January 2, 2006")
+
+	holdID := notification.RentData.ID.String()
+	expiresAt := "soon"
+	if offer := notification.Offer; offer != nil {
+		holdID = offer.HoldID.String()
+		expiresAt = offer.ExpiresAt.Format("January 2, 2006 15:04 MST")
+	}
+
+	emailContent := fmt.Sprintf(
+		"Dear Customer,\n\n"+
+			"A room has become available for the stay you were waiting for. We are holding it for you:\n\n"+
+			"Hotel ID: %s\n"+
+			"Check-in Date: %s\n"+
+			"Check-out Date: %s\n"+
+			"Total: %d %s\n"+
+			"Hold ID: %s\n"+
+			"The hold expires: %s\n\n"+
+			"Book the room with the hold before it expires, after that it is offered to other guests.\n\n"+
+			"Best regards,\n"+
+			"Your Hotel Team",
+		notification.RentData.HotelID, fromDate, toDate, notification.RentData.TotalAmount,
+		notification.RentData.Currency, holdID, expiresAt,
+	)
+	slog.Info("Built content of the waitlist offer email")
+
+	return emailContent
+}
+
+func (e *EmailContentBuilder) buildGroupContent(notification models.NotificationData) string {
+	group := notification.Group
+
+	var rooms strings.Builder
+	for i, rent := range group.Rents {
+		rooms.WriteString(fmt.Sprintf("Room %d: %s - %s, %d %s\n", i+1,
+			rent.CheckInDate.Format("January 2, 2006"), rent.CheckOutDate.Format("January 2, 2006"),
+			rent.TotalAmount, rent.Currency))
+		rooms.WriteString(buildGuestsContent(&rent))
+	}
+
+	emailContent := fmt.Sprintf(
+		"Dear Customer,\n\n"+
+			"Thank you for your group booking at our hotel. Below are the details of the booked rooms:\n\n"+
+			"Hotel ID: %s\n"+
+			"Group ID: %s\n"+
+			"%s"+
+			"Total: %d %s\n\n"+
+			"We look forward to welcoming you. If you have any questions or need further assistance, feel free to reach out.\n\n"+
+			"Best regards,\n"+
+			"Your Hotel Team",
+		group.HotelID, group.ID, rooms.String(), group.TotalAmount, group.Currency,
+	)
+	slog.Info("Built content of the group booking email")
+
+	return emailContent
+}
+
+func (e *EmailContentBuilder) buildModificationContent(notification models.NotificationData) string {
+	modification := notification.Modification
+	if modification == nil {
+		// Without the previous stay only the current one can be shown
+		modification = &models.ModificationData{
+			After: models.StayData{
+				HotelID:      notification.RentData.HotelID,
+				CheckInDate:  notification.RentData.CheckInDate,
+				CheckOutDate: notification.RentData.CheckOutDate,
+				Nights:       notification.RentData.Nights,
+				TotalAmount:  notification.RentData.TotalAmount,
+			},
+			Currency: notification.RentData.Currency,
+		}
+	}
+
+	priceChange := "The price of your booking has not changed."
+	if modification.PriceDifference > 0 {
+		priceChange = fmt.Sprintf("You pay %d %s more for the new stay.", modification.PriceDifference, modification.Currency)
+	} else if modification.PriceDifference < 0 {
+		priceChange = fmt.Sprintf("The new stay costs %d %s less.", -modification.PriceDifference, modification.Currency)
+	}
+
+	emailContent := fmt.Sprintf(
+		"Dear Customer,\n\n"+
+			"Your booking at our hotel has been modified. Below are the details of the stay before and after the change:\n\n"+
+			"Before:\n%s\n"+
+			"After:\n%s\n"+
+			"%s\n\n"+
+			"If you did not request this change or need further assistance, feel free to reach out.\n\n"+
+			"Best regards,\n"+
+			"Your Hotel Team",
+		buildStayContent(modification.Before, modification.Currency),
+		buildStayContent(modification.After, modification.Currency), priceChange,
+	)
+	slog.Info("Built content of the modification email")
+
+	return emailContent
+}
+
+// buildStayContent describes a stay of the modification, a stay which is not known is shown as not available
+func buildStayContent(stay models.StayData, currency string) string {
+	if stay.CheckInDate.IsZero() {
+		return "not available\n"
+	}
+	return fmt.Sprintf(
+		"Hotel ID: %s\n"+
+			"Check-in Date: %s\n"+
+			"Check-out Date: %s\n"+
+			"Nights: %d\n"+
+			"Total: %d %s\n",
+		stay.HotelID, stay.CheckInDate.Format("January 2, 2006"), stay.CheckOutDate.Format("January 2, 2006"),
+		stay.Nights, stay.TotalAmount, currency,
+	)
+}
+
+// buildGuestsContent lists the guests of the rent, it is empty for the rents made before the guests were recorded
+func buildGuestsContent(rent *models.RentData) string {
+	if rent.Adults == 0 {
+		return ""
+	}
+
+	content := fmt.Sprintf("Guests: %d adults, %d children\n", rent.Adults, rent.Children)
+	if rent.PrimaryGuestName != "" {
+		content += fmt.Sprintf("Primary Guest: %s\n", rent.PrimaryGuestName)
+	}
+	if len(rent.AdditionalGuests) > 0 {
+		content += fmt.Sprintf("Additional Guests: %s\n", strings.Join(rent.AdditionalGuests, ", "))
+	}
+	return content
+}
@@ -30,3 +30,173 @@ func TestEmailContentBuilder_BuildContent(t *testing.T) {
 	// Проверим, что контент не пуст. Проверять текст сообщения не имеет смысла
 	assert.NotNil(t, content)
 }
+
+func TestEmailContentBuilder_BuildContent_Cancellation(t *testing.T) {
+	builder := content_build.NewEmailContentBuilder()
+	notification := models.NotificationData{
+		EventType: models.EventBookingCancelled,
+		UserContactData: &models.UserContactData{
+			Phone: "123",
+			Email: "test@gmail.com",
+		},
+		RentData: &models.RentData{
+			ID:           uuid.New(),
+			ClientID:     uuid.New(),
+			HotelID:      uuid.New(),
+			NightPrice:   10000,
+			CheckInDate:  time.Now(),
+			CheckOutDate: time.Now(),
+		},
+		Cancellation: &models.CancellationData{
+			Reason:        "Change of plans",
+			PenaltyAmount: 10000,
+			RefundAmount:  20000,
+		},
+	}
+
+	content := builder.BuildContent(notification)
+
+	assert.Equal(t, "Booking Cancellation", builder.BuildSubject(notification))
+	assert.Contains(t, content, "Change of plans")
+	assert.Contains(t, content, "Refund Amount: 20000")
+}
+
+func TestEmailContentBuilder_BuildContent_StatusChanged(t *testing.T) {
+	builder := content_build.NewEmailContentBuilder()
+	notification := models.NotificationData{
+		EventType: models.EventBookingStatusChanged,
+		UserContactData: &models.UserContactData{
+			Phone: "123",
+			Email: "test@gmail.com",
+		},
+		RentData: &models.RentData{
+			ID:           uuid.New(),
+			ClientID:     uuid.New(),
+			HotelID:      uuid.New(),
+			NightPrice:   10000,
+			CheckInDate:  time.Now(),
+			CheckOutDate: time.Now(),
+			Status:       "checked_in",
+		},
+		Transition: &models.TransitionData{FromStatus: "confirmed", ToStatus: "checked_in"},
+	}
+
+	content := builder.BuildContent(notification)
+
+	assert.Equal(t, "Booking Status Update", builder.BuildSubject(notification))
+	assert.Contains(t, content, "Status: checked in")
+}
+
+func TestEmailContentBuilder_BuildContent_WaitlistOffer(t *testing.T) {
+	builder := content_build.NewEmailContentBuilder()
+	holdID := uuid.New()
+	notification := models.NotificationData{
+		EventType: models.EventWaitlistOffer,
+		UserContactData: &models.UserContactData{
+			Phone: "123",
+			Email: "test@gmail.com",
+		},
+		RentData: &models.RentData{
+			ID:           holdID,
+			ClientID:     uuid.New(),
+			HotelID:      uuid.New(),
+			NightPrice:   10000,
+			CheckInDate:  time.Now(),
+			CheckOutDate: time.Now(),
+			Status:       "held",
+		},
+		Offer: &models.OfferData{EntryID: uuid.New(), HoldID: holdID, ExpiresAt: time.Now().Add(2 * time.Hour)},
+	}
+
+	content := builder.BuildContent(notification)
+
+	assert.Equal(t, "A Room Is Available", builder.BuildSubject(notification))
+	assert.Contains(t, content, "Hold ID: "+holdID.String())
+}
+
+func TestEmailContentBuilder_BuildContent_GroupBooking(t *testing.T) {
+	builder := content_build.NewEmailContentBuilder()
+	notification := models.NotificationData{
+		EventType: models.EventGroupBookingCreated,
+		UserContactData: &models.UserContactData{
+			Phone: "123",
+			Email: "test@gmail.com",
+		},
+		Group: &models.GroupData{
+			ID:      uuid.New(),
+			HotelID: uuid.New(),
+			Rents: []models.RentData{
+				{ID: uuid.New(), CheckInDate: time.Now(), CheckOutDate: time.Now(), TotalAmount: 10000, Currency: "RUB"},
+				{ID: uuid.New(), CheckInDate: time.Now(), CheckOutDate: time.Now(), TotalAmount: 20000, Currency: "RUB"},
+			},
+			TotalAmount: 30000,
+			Currency:    "RUB",
+		},
+	}
+
+	content := builder.BuildContent(notification)
+
+	assert.Equal(t, "Group Booking Confirmation", builder.BuildSubject(notification))
+	assert.Contains(t, content, "Room 2:")
+	assert.Contains(t, content, "Total: 30000 RUB")
+}
+
+func TestEmailContentBuilder_BuildContent_Modification(t *testing.T) {
+	builder := content_build.NewEmailContentBuilder()
+	hotelID := uuid.New()
+	checkIn := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	notification := models.NotificationData{
+		EventType: models.EventBookingModified,
+		UserContactData: &models.UserContactData{
+			Phone: "123",
+			Email: "test@gmail.com",
+		},
+		RentData: &models.RentData{
+			ID:           uuid.New(),
+			HotelID:      hotelID,
+			CheckInDate:  checkIn,
+			CheckOutDate: checkIn.AddDate(0, 0, 3),
+		},
+		Modification: &models.ModificationData{
+			Before:          models.StayData{HotelID: hotelID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2), Nights: 2, TotalAmount: 20000},
+			After:           models.StayData{HotelID: hotelID, CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 3), Nights: 3, TotalAmount: 30000},
+			Currency:        "RUB",
+			PriceDifference: 10000,
+		},
+	}
+
+	content := builder.BuildContent(notification)
+
+	assert.Equal(t, "Booking Modification", builder.BuildSubject(notification))
+	assert.Contains(t, content, "Check-out Date: January 12, 2025")
+	assert.Contains(t, content, "Check-out Date: January 13, 2025")
+	assert.Contains(t, content, "You pay 10000 RUB more")
+}
+
+func TestEmailContentBuilder_BuildContent_ListsGuests(t *testing.T) {
+	builder := content_build.NewEmailContentBuilder()
+
+	content := builder.BuildContent(models.NotificationData{
+		EventType: models.EventBookingCreated,
+		UserContactData: &models.UserContactData{
+			Phone: "123",
+			Email: "test@gmail.com",
+		},
+		RentData: &models.RentData{
+			ID:               uuid.New(),
+			ClientID:         uuid.New(),
+			HotelID:          uuid.New(),
+			NightPrice:       10000,
+			CheckInDate:      time.Now(),
+			CheckOutDate:     time.Now(),
+			Adults:           2,
+			Children:         1,
+			PrimaryGuestName: "Ivan Petrov",
+			AdditionalGuests: []string{"Anna Petrova", "Oleg Petrov"},
+		},
+	})
+
+	assert.Contains(t, content, "Guests: 2 adults, 1 children")
+	assert.Contains(t, content, "Primary Guest: Ivan Petrov")
+	assert.Contains(t, content, "Additional Guests: Anna Petrova, Oleg Petrov")
+}
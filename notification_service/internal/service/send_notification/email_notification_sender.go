@@ -30,7 +30,7 @@ func (e *EmailSender) Send(notification models.NotificationData) error {
 	auth := smtp.PlainAuth("", e.Username, e.Password, e.SMTPServer)
 	from := e.Username
 	to := []string{notification.UserContactData.Email}
-	subject := "Booking Confirmation"
+	subject := e.contentBuilder.BuildSubject(notification)
 	message := fmt.Sprintf("Subject: %s\r\n\r\n%s", subject, e.contentBuilder.BuildContent(notification))
 
 	slog.Info("Sending email...")
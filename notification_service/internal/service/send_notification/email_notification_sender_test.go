@@ -12,6 +12,11 @@ type MockContentBuilder struct {
 	mock.Mock
 }
 
+func (m *MockContentBuilder) BuildSubject(notification models.NotificationData) string {
+	args := m.Called(notification)
+	return args.String(0)
+}
+
 func (m *MockContentBuilder) BuildContent(notification models.NotificationData) string {
 	args := m.Called(notification)
 	return args.String(0)
@@ -5,21 +5,21 @@ go 1.23.1
 require (
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
+	golang.org/x/net v0.30.0
+	google.golang.org/grpc v1.69.0
+	google.golang.org/protobuf v1.35.1
 )
 
 require (
 	github.com/golang/protobuf v1.5.4 // indirect
-	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241015192408-796eee8c2d53 // indirect
-	google.golang.org/grpc v1.69.0 // indirect
-	google.golang.org/protobuf v1.35.1 // indirect
 )
 
 require (
 	github.com/golang-jwt/jwt/v4 v4.5.1
-	github.com/gorilla/mux v1.8.1 // indirect
+	github.com/gorilla/mux v1.8.1
 	github.com/mfridman/interpolate v0.0.2 // indirect
 	github.com/pressly/goose/v3 v3.23.0 // indirect
 	github.com/sethvargo/go-retry v0.3.0 // indirect
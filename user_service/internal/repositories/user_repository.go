@@ -40,6 +40,25 @@ func (s *UserRepository) GetByEmail(email string) (*user.UserModel, error) {
 	return &user, nil
 }
 
+func (s *UserRepository) GetById(id uuid.UUID) (*user.UserModel, error) {
+	query := `
+		SELECT u.id, u.username, u.email, u.password_hash, u.role
+		FROM users u
+		WHERE u.id = $1`
+
+	row := s.Db.Connection.QueryRow(query, id)
+
+	var user user.UserModel
+	if err := row.Scan(&user.Id, &user.Username, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	return &user, nil
+}
+
 func (s *UserRepository) Create(username string, email string, role user.Role, passwordHash string) (*uuid.UUID, error) {
 	query := `
 		INSERT INTO users (username, email, password_hash, role)
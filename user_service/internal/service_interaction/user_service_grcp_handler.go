@@ -1,10 +1,12 @@
 package service_interaction
 
 import (
+	"errors"
 	"log/slog"
 	pb "user_service/internal/service_interaction/gen"
 	"user_service/internal/services"
 
+	"github.com/google/uuid"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,13 +24,45 @@ func NewUserServiceBridge(userService services.IUserService) *BookingServiceBrid
 	}
 }
 
-func (s *BookingServiceBridge) GetHotelPrice(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
-	slog.Info("Handling request to get price of hotel with id " + req.Token)
+func (s *BookingServiceBridge) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	slog.Info("Handling request to get user by token")
 
 	user, err := s.userService.GetUserByToken(req.Token)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		return nil, status.Errorf(codes.Unauthenticated, "user not found: %v", err)
 	}
 
 	return &pb.GetUserResponse{Id: user.Id.String(), Email: user.Email, Username: user.Username, Role: user.Role.String()}, nil
 }
+
+func (s *BookingServiceBridge) GetUserContactData(ctx context.Context, req *pb.GetUserDataRequest) (*pb.GetUserDataResponse, error) {
+	slog.Info("Handling request to get contact data of user by token")
+
+	user, err := s.userService.GetUserByToken(req.Token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not found: %v", err)
+	}
+
+	// Users have no phone number yet, so only the email is sent
+	return &pb.GetUserDataResponse{Id: user.Id.String(), Email: user.Email, Role: user.Role.String()}, nil
+}
+
+// GetUserContactDataById lets the services reach users who are not the caller, such as the guest of a rent
+func (s *BookingServiceBridge) GetUserContactDataById(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.GetUserDataResponse, error) {
+	slog.Info("Handling request to get contact data of user by id")
+
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+	}
+
+	user, err := s.userService.GetUserById(id)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	return &pb.GetUserDataResponse{Id: user.Id.String(), Email: user.Email, Role: user.Role.String()}, nil
+}
@@ -1,18 +1,24 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"user_service/internal/dto/requests"
 	"user_service/internal/dto/responses"
 	"user_service/internal/repositories"
+
+	"github.com/google/uuid"
 )
 
 type IUserService interface {
 	Auth(request requests.AuthRequest) (responses.AuthResponse, error)
 	Create(request requests.CreateRequest) (responses.CreateResponse, error)
 	GetUserByToken(token string) (responses.MeResponse, error)
+	GetUserById(id uuid.UUID) (responses.MeResponse, error)
 }
 
+var ErrUserNotFound = errors.New("user not found")
+
 type UserService struct {
 	repository        *repositories.UserRepository
 	encryptionService *EncryptionService
@@ -73,3 +79,16 @@ func (s *UserService) GetUserByToken(token string) (responses.MeResponse, error)
 
 	return responses.MeResponse{Id: result.Id, Username: result.Username, Email: result.Email, Role: result.Role}, nil
 }
+
+func (s *UserService) GetUserById(id uuid.UUID) (responses.MeResponse, error) {
+	result, err := s.repository.GetById(id)
+
+	if err != nil {
+		return responses.MeResponse{}, err
+	}
+	if result == nil {
+		return responses.MeResponse{}, ErrUserNotFound
+	}
+
+	return responses.MeResponse{Id: result.Id, Username: result.Username, Email: result.Email, Role: result.Role}, nil
+}